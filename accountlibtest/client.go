@@ -0,0 +1,224 @@
+package accountlibtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+
+	"accountlib"
+	"accountlib/errors"
+	"accountlib/internal/listfilter"
+)
+
+// defaultPageSize - page size of lists without a page size
+const defaultPageSize = 100
+
+// the in-memory client implements the account operations of the client
+var _ accountlib.AccountsAPI = (*Client)(nil)
+
+// Client - in-memory implementation of the account client operations for unit tests
+// Missing accounts, duplicates and version mismatches fail with the same errors the api responses map to
+// Operations fail with the error of a done context, call options are accepted and ignored
+type Client struct {
+	mutex    sync.Mutex
+	accounts map[string]*accountlib.AccountData
+	errors   map[string]error
+}
+
+// NewClient - returns an in-memory client holding the given accounts
+func NewClient(accounts ...accountlib.AccountData) *Client {
+	client := &Client{
+		accounts: make(map[string]*accountlib.AccountData),
+		errors:   make(map[string]error),
+	}
+	for i := range accounts {
+		client.AddAccount(accounts[i])
+	}
+	return client
+}
+
+// AddAccount - stores an account, replacing any account with the same id
+func (client *Client) AddAccount(account accountlib.AccountData) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.accounts[account.ID] = account.Clone()
+}
+
+// SetError - makes every operation on the account id fail with the given error, nil removes the error
+func (client *Client) SetError(accountID string, err error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if err == nil {
+		delete(client.errors, accountID)
+		return
+	}
+	client.errors[accountID] = err
+}
+
+// Accounts - returns the stored accounts sorted by id
+func (client *Client) Accounts() []accountlib.AccountData {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	accounts := make([]accountlib.AccountData, 0, len(client.accounts))
+	for _, account := range client.accounts {
+		accounts = append(accounts, *account.Clone())
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	return accounts
+}
+
+// Fetch - returns the stored account
+func (client *Client) Fetch(ctx context.Context, accountID string, _ ...accountlib.CallOption) (*accountlib.AccountData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if err, ok := client.errors[accountID]; ok {
+		return nil, err
+	}
+	account, ok := client.accounts[accountID]
+	if !ok {
+		return nil, accounterrors.HandleErrorStatusCode(http.StatusNotFound, nil)
+	}
+	return account.Clone(), nil
+}
+
+// Create - stores a new account with version 0 based on the create params
+func (client *Client) Create(ctx context.Context, createParams accountlib.AccountCreateParams, _ ...accountlib.CallOption) (*accountlib.AccountData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if err, ok := client.errors[createParams.ID]; ok {
+		return nil, err
+	}
+	if _, ok := client.accounts[createParams.ID]; ok {
+		return nil, accounterrors.HandleErrorStatusCode(http.StatusConflict, nil)
+	}
+	account, err := accountFromParams(createParams)
+	if err != nil {
+		return nil, err
+	}
+	client.accounts[account.ID] = account
+	return account.Clone(), nil
+}
+
+// Delete - removes the stored account if the version matches
+func (client *Client) Delete(ctx context.Context, accountID string, version *int64, _ ...accountlib.CallOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if version == nil {
+		return errors.New("invalid version")
+	}
+	if err, ok := client.errors[accountID]; ok {
+		return err
+	}
+	account, ok := client.accounts[accountID]
+	if !ok {
+		return accounterrors.HandleErrorStatusCode(http.StatusNotFound, nil)
+	}
+	if account.Version != nil && *account.Version != *version {
+		return accounterrors.HandleErrorStatusCode(http.StatusConflict, nil)
+	}
+	delete(client.accounts, accountID)
+	return nil
+}
+
+// Update - replaces the attributes of the stored account if the version matches and increments its version
+func (client *Client) Update(ctx context.Context, updateParams accountlib.AccountUpdateParams, _ ...accountlib.CallOption) (*accountlib.AccountData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if updateParams.Version == nil {
+		return nil, errors.New("invalid version")
+	}
+	if err, ok := client.errors[updateParams.ID]; ok {
+		return nil, err
+	}
+	account, ok := client.accounts[updateParams.ID]
+	if !ok {
+		return nil, accounterrors.HandleErrorStatusCode(http.StatusNotFound, nil)
+	}
+	if account.Version != nil && *account.Version != *updateParams.Version {
+		return nil, accounterrors.HandleErrorStatusCode(http.StatusConflict, nil)
+	}
+	updated, err := accountFromParams(accountlib.AccountCreateParams{
+		Attributes:     updateParams.Attributes,
+		ID:             account.ID,
+		OrganisationID: account.OrganisationID,
+		Type:           account.Type,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated.Attributes != nil && account.Attributes != nil {
+		updated.Attributes.Status = account.Attributes.Status
+		updated.Attributes.StatusReason = account.Attributes.StatusReason
+	}
+	version := *updateParams.Version + 1
+	updated.Version = &version
+	client.accounts[account.ID] = updated
+	return updated.Clone(), nil
+}
+
+// List - returns a page of the stored accounts sorted by id, filtered by organisation_id, bank_id and country
+// Other filters are ignored, the total count holds the number of accounts matching the filters
+func (client *Client) List(ctx context.Context, listParams accountlib.ListParams, _ ...accountlib.CallOption) (*accountlib.AccountList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if listParams.PageNumber < 0 || listParams.PageSize < 0 {
+		return nil, accounterrors.HandleErrorStatusCode(http.StatusBadRequest, nil)
+	}
+	pageSize := listParams.PageSize
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	accounts := make([]accountlib.AccountData, 0)
+	for _, account := range client.Accounts() {
+		if matchesFilters(&account, listParams.Filter) {
+			accounts = append(accounts, account)
+		}
+	}
+	start := listParams.PageNumber * pageSize
+	if start > len(accounts) {
+		start = len(accounts)
+	}
+	end := start + pageSize
+	if end > len(accounts) {
+		end = len(accounts)
+	}
+	total := int64(len(accounts))
+	return &accountlib.AccountList{Data: accounts[start:end], TotalCount: &total}, nil
+}
+
+// matchesFilters - reports whether the account matches all supported filters
+func matchesFilters(account *accountlib.AccountData, filter map[string]string) bool {
+	return listfilter.Match(account, func(name string) string { return filter[name] })
+}
+
+// accountFromParams - converts create params into the account the api would return
+func accountFromParams(createParams accountlib.AccountCreateParams) (*accountlib.AccountData, error) {
+	account := &accountlib.AccountData{}
+	encoded, err := json.Marshal(createParams)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(encoded, account); err != nil {
+		return nil, err
+	}
+	version := int64(0)
+	account.Version = &version
+	return account, nil
+}
@@ -0,0 +1,120 @@
+package accountlibtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+)
+
+// TestClientCreateFetchDelete - tests the account lifecycle
+func TestClientCreateFetchDelete(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient()
+	params := accountlib.NewAccountBuilder().OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").Country("GB").Build()
+
+	created, err := client.Create(context.Background(), params)
+	check.Equal(err, nil)
+	check.Equal(*created.Version, int64(0))
+	check.Equal(*created.Attributes.Country, accountlib.CountryCode("GB"))
+
+	fetched, err := client.Fetch(context.Background(), params.ID)
+	check.Equal(err, nil)
+	check.True(fetched.Equal(created))
+
+	check.Equal(client.Delete(context.Background(), params.ID, created.Version), nil)
+	_, err = client.Fetch(context.Background(), params.ID)
+	check.Contains(err.Error(), "resource not found")
+}
+
+// TestClientConflicts - tests duplicate creates and stale deletes
+func TestClientConflicts(t *testing.T) {
+	check := assert.New(t)
+	version := int64(1)
+	client := NewClient(accountlib.AccountData{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3", Version: &version})
+
+	_, err := client.Create(context.Background(), accountlib.AccountCreateParams{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3"})
+	check.Contains(err.Error(), "request conflict")
+
+	staleVersion := int64(0)
+	err = client.Delete(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", &staleVersion)
+	check.Contains(err.Error(), "request conflict")
+	check.Len(client.Accounts(), 1)
+}
+
+// TestClientSetError - tests error injection per account id
+func TestClientSetError(t *testing.T) {
+	check := assert.New(t)
+	injected := errors.New("injected")
+	client := NewClient(accountlib.AccountData{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3"})
+
+	client.SetError("7eb322ba-57f6-465c-b600-79f26ac7fdc3", injected)
+	_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Equal(err, injected)
+
+	client.SetError("7eb322ba-57f6-465c-b600-79f26ac7fdc3", nil)
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Equal(err, nil)
+}
+
+// TestClientReturnsCopies - tests mutating returned accounts does not change stored ones
+func TestClientReturnsCopies(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(accountlib.AccountData{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3"})
+
+	fetched, _ := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	fetched.OrganisationID = "changed"
+	check.Equal(client.Accounts()[0].OrganisationID, "")
+}
+
+// TestClientUpdate - tests updates replace the attributes and increment the version
+func TestClientUpdate(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(ValidGBAccount().AccountData())
+	version := int64(0)
+
+	updated, err := client.Update(context.Background(), accountlib.AccountUpdateParams{
+		ID:         FixtureAccountID,
+		Version:    &version,
+		Attributes: ValidGBAccount().WithBic("BARCGB22").CreateParams().Attributes,
+	})
+	check.Equal(err, nil)
+	check.Equal(*updated.Version, int64(1))
+	check.Equal(updated.Attributes.Bic, "BARCGB22")
+	check.Equal(*updated.Attributes.Status, accountlib.AccountStatusConfirmed)
+
+	_, err = client.Update(context.Background(), accountlib.AccountUpdateParams{ID: FixtureAccountID, Version: &version})
+	check.Contains(err.Error(), "request conflict")
+}
+
+// TestClientList - tests paging and filtering of the stored accounts
+func TestClientList(t *testing.T) {
+	check := assert.New(t)
+	gb, de := accountlib.CountryCode("GB"), accountlib.CountryCode("DE")
+	client := NewClient(
+		accountlib.AccountData{ID: "0c1f2a4e-3b8d-4f4b-9a6e-1f2e3d4c5b6a", OrganisationID: "org-a", Attributes: &accountlib.AccountAttributes{Country: &gb}},
+		accountlib.AccountData{ID: "5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a", OrganisationID: "org-a", Attributes: &accountlib.AccountAttributes{Country: &de}},
+		accountlib.AccountData{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3", OrganisationID: "org-b"},
+	)
+	var api accountlib.AccountsAPI = client
+
+	accountList, err := api.List(context.Background(), accountlib.ListParams{PageNumber: 1, PageSize: 2})
+	check.Nil(err)
+	check.Len(accountList.Data, 1)
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", accountList.Data[0].ID)
+
+	accountList, err = api.List(context.Background(), accountlib.ListParams{Filter: map[string]string{"organisation_id": "org-a", "country": "DE"}})
+	check.Nil(err)
+	check.Len(accountList.Data, 1)
+	check.Equal("5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a", accountList.Data[0].ID)
+	check.Equal(int64(1), *accountList.TotalCount)
+
+	accountList, err = api.List(context.Background(), accountlib.ListParams{PageNumber: 5})
+	check.Nil(err)
+	check.Empty(accountList.Data)
+	_, err = api.List(context.Background(), accountlib.ListParams{PageSize: -1})
+	check.Contains(err.Error(), "bad request")
+}
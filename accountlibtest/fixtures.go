@@ -0,0 +1,142 @@
+package accountlibtest
+
+import (
+	"fmt"
+	"sync"
+
+	"accountlib"
+)
+
+// fixture ids, fixtures use fixed ids so tests stay deterministic
+const (
+	FixtureAccountID      = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	FixtureOrganisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+)
+
+// AccountFixture - builds consistent account fixtures which pass the validation of the library
+type AccountFixture struct {
+	params accountlib.AccountCreateParams
+}
+
+// bankDetails - bank details which are valid for the rules of a country
+type bankDetails struct {
+	bankID     string
+	bankIDCode string
+	bic        string
+	currency   accountlib.CurrencyCode
+}
+
+// countryBankDetails - bank details used by fixtures per country
+var countryBankDetails = map[accountlib.CountryCode]bankDetails{
+	"AU": {bankID: "123456", bankIDCode: "AUBSB", bic: "NATAAU33", currency: "AUD"},
+	"CA": {bankID: "012345678", bankIDCode: "CACPA", bic: "ROYCCAT2", currency: "CAD"},
+	"DE": {bankID: "37040044", bankIDCode: "DEBLZ", bic: "COBADEFF", currency: "EUR"},
+	"FR": {bankID: "2004101005", bankIDCode: "FR", bic: "BNPAFRPP", currency: "EUR"},
+	"GB": {bankID: "400300", bankIDCode: "GBDSC", bic: "NWBKGB22", currency: "GBP"},
+	"NL": {bic: "ABNANL2A", currency: "EUR"},
+	"US": {bankID: "021000021", bankIDCode: "USABA", bic: "CHASUS33", currency: "USD"},
+}
+
+// ValidGBAccount - returns a fixture of a valid GB account
+func ValidGBAccount() *AccountFixture {
+	return ValidAccount("GB")
+}
+
+// ValidAccount - returns a fixture of a valid account in the given country
+func ValidAccount(country accountlib.CountryCode) *AccountFixture {
+	fixture := &AccountFixture{
+		params: accountlib.NewAccountBuilder().
+			ID(FixtureAccountID).
+			OrganisationID(FixtureOrganisationID).
+			Name("Samantha Holder").
+			AlternativeNames("Sam Holder").
+			Build(),
+	}
+	return fixture.WithCountry(country)
+}
+
+// WithCountry - changes the country, bank details are replaced with ones valid for the country
+// Countries unknown to the fixtures get no bank details
+func (fixture *AccountFixture) WithCountry(country accountlib.CountryCode) *AccountFixture {
+	details := countryBankDetails[country]
+	attributes := fixture.params.Attributes
+	attributes.Country = &country
+	attributes.BankID = details.bankID
+	attributes.BankIDCode = details.bankIDCode
+	attributes.Bic = details.bic
+	attributes.BaseCurrency = details.currency
+	attributes.Iban = ""
+	return fixture
+}
+
+// WithID - changes the account id
+func (fixture *AccountFixture) WithID(accountID string) *AccountFixture {
+	fixture.params.ID = accountID
+	return fixture
+}
+
+// WithOrganisationID - changes the organisation id
+func (fixture *AccountFixture) WithOrganisationID(organisationID string) *AccountFixture {
+	fixture.params.OrganisationID = organisationID
+	return fixture
+}
+
+// WithName - changes the names of the account holder
+func (fixture *AccountFixture) WithName(names ...string) *AccountFixture {
+	fixture.params.Attributes.Name = names
+	return fixture
+}
+
+// WithBankID - changes the bank id
+func (fixture *AccountFixture) WithBankID(bankID string) *AccountFixture {
+	fixture.params.Attributes.BankID = bankID
+	return fixture
+}
+
+// WithBic - changes the bic
+func (fixture *AccountFixture) WithBic(bic string) *AccountFixture {
+	fixture.params.Attributes.Bic = bic
+	return fixture
+}
+
+// WithIban - changes the iban
+func (fixture *AccountFixture) WithIban(iban string) *AccountFixture {
+	fixture.params.Attributes.Iban = iban
+	return fixture
+}
+
+// WithAccountNumber - changes the account number
+func (fixture *AccountFixture) WithAccountNumber(accountNumber string) *AccountFixture {
+	fixture.params.Attributes.AccountNumber = accountNumber
+	return fixture
+}
+
+// CreateParams - returns the fixture as create params
+func (fixture *AccountFixture) CreateParams() accountlib.AccountCreateParams {
+	return *fixture.params.Clone()
+}
+
+// AccountData - returns the fixture as the confirmed account the api returns after creation
+func (fixture *AccountFixture) AccountData() accountlib.AccountData {
+	account, err := accountFromParams(fixture.CreateParams())
+	if err != nil {
+		// fixtures only hold values which can be encoded
+		panic(err)
+	}
+	status := accountlib.AccountStatusConfirmed
+	account.Attributes.Status = &status
+	return *account
+}
+
+// SequentialIDs - returns an id generator for deterministic tests, generating the uuids
+// 00000000-0000-4000-8000-000000000001, 00000000-0000-4000-8000-000000000002 and so on
+func SequentialIDs() accountlib.IDGenerator {
+	var mutex sync.Mutex
+	next := int64(0)
+	return func() string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		next++
+		return fmt.Sprintf("00000000-0000-4000-8000-%012d", next)
+	}
+}
@@ -0,0 +1,54 @@
+package accountlibtest
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+)
+
+// TestFixturesAreValid - tests fixtures of every known country pass validation
+func TestFixturesAreValid(t *testing.T) {
+	check := assert.New(t)
+	for country := range countryBankDetails {
+		params := ValidAccount(country).CreateParams()
+		check.Equal(params.Validate(), nil, string(country))
+	}
+}
+
+// TestFixtureWithCountry - tests changing the country replaces the bank details
+func TestFixtureWithCountry(t *testing.T) {
+	check := assert.New(t)
+	params := ValidGBAccount().WithCountry("AU").CreateParams()
+
+	check.Equal(*params.Attributes.Country, accountlib.CountryCode("AU"))
+	check.Equal(params.Attributes.BankIDCode, "AUBSB")
+	check.Equal(params.Validate(), nil)
+}
+
+// TestFixtureAccountData - tests the account data fixture matches the create params
+func TestFixtureAccountData(t *testing.T) {
+	check := assert.New(t)
+	fixture := ValidGBAccount().WithID("7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	data := fixture.AccountData()
+
+	check.Equal(data.ID, "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Equal(*data.Version, int64(0))
+	check.Equal(*data.Attributes.Status, accountlib.AccountStatusConfirmed)
+	check.Equal(data.Attributes.Name, fixture.CreateParams().Attributes.Name)
+}
+
+// TestSequentialIDs - tests the generated ids are sequential valid uuids
+func TestSequentialIDs(t *testing.T) {
+	check := assert.New(t)
+	generator := SequentialIDs()
+	check.Equal("00000000-0000-4000-8000-000000000001", generator())
+	check.Equal("00000000-0000-4000-8000-000000000002", generator())
+
+	params := accountlib.NewClient(&accountlib.ClientOptions{IDGenerator: generator}).NewAccountBuilder().Build()
+	check.Equal("00000000-0000-4000-8000-000000000003", params.ID)
+	_, err := uuid.Parse(params.ID)
+	check.Nil(err)
+}
@@ -0,0 +1,64 @@
+package accountlibtest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"accountlib"
+)
+
+// golden file constants
+const (
+	testdataDir     = "testdata"
+	updateGoldenEnv = "ACCOUNTLIB_UPDATE_GOLDEN"
+)
+
+// LoadTestdata - returns the content of a file in the testdata directory of the calling package
+func LoadTestdata(t testing.TB, name string) []byte {
+	t.Helper()
+	content, err := ioutil.ReadFile(filepath.Join(testdataDir, name))
+	if err != nil {
+		t.Fatalf("unable to read testdata %s. error: %s", name, err.Error())
+	}
+	return content
+}
+
+// DecodeAccountFixture - decodes an account response stored in testdata through the decoding of the client
+func DecodeAccountFixture(t testing.TB, name string) *accountlib.AccountData {
+	t.Helper()
+	account, err := accountlib.DecodeAccount(LoadTestdata(t, name))
+	if err != nil {
+		t.Fatalf("unable to decode testdata %s. error: %s", name, err.Error())
+	}
+	return account
+}
+
+// AssertGolden - compares content with a golden file in testdata, byte by byte
+// Setting ACCOUNTLIB_UPDATE_GOLDEN=1 rewrites the golden file with the content instead
+func AssertGolden(t testing.TB, name string, content []byte) {
+	t.Helper()
+	path := filepath.Join(testdataDir, name)
+	if os.Getenv(updateGoldenEnv) != "" {
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("unable to update golden file %s. error: %s", name, err.Error())
+		}
+		return
+	}
+	golden := LoadTestdata(t, name)
+	if !bytes.Equal(golden, content) {
+		t.Errorf("content differs from golden file %s\nwant: %s\ngot:  %s", name, golden, content)
+	}
+}
+
+// AssertCreateRequestGolden - compares the request body the client sends for the create params with a golden file
+func AssertCreateRequestGolden(t testing.TB, name string, createParams accountlib.AccountCreateParams) {
+	t.Helper()
+	body, err := accountlib.MarshalCreateRequest(createParams)
+	if err != nil {
+		t.Fatalf("unable to marshal create params. error: %s", err.Error())
+	}
+	AssertGolden(t, name, body)
+}
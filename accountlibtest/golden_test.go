@@ -0,0 +1,24 @@
+package accountlibtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+)
+
+// TestDecodeAccountFixture - tests an account response fixture is decoded including unknown attributes
+func TestDecodeAccountFixture(t *testing.T) {
+	check := assert.New(t)
+	account := DecodeAccountFixture(t, "account.json")
+
+	check.Equal(account.ID, FixtureAccountID)
+	check.Equal(*account.Attributes.Country, accountlib.CountryCode("GB"))
+	check.Contains(account.Attributes.Extra, "private_identification")
+}
+
+// TestCreateRequestGolden - tests the wire format of the create request of a fixture
+func TestCreateRequestGolden(t *testing.T) {
+	AssertCreateRequestGolden(t, "create_request.golden", ValidGBAccount().CreateParams())
+}
@@ -0,0 +1,61 @@
+package accountlibtest
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"accountlib"
+)
+
+// CreatePayload - returns the exact json the client sends for creating an account with the create params
+func CreatePayload(t testing.TB, createParams accountlib.AccountCreateParams) []byte {
+	t.Helper()
+	payload, err := accountlib.MarshalCreateRequest(createParams)
+	if err != nil {
+		t.Fatalf("unable to marshal create params. error: %s", err.Error())
+	}
+	return payload
+}
+
+// UpdatePayload - returns the exact json the client sends for updating an account with the update params
+func UpdatePayload(t testing.TB, updateParams accountlib.AccountUpdateParams) []byte {
+	t.Helper()
+	payload, err := accountlib.MarshalUpdateRequest(updateParams)
+	if err != nil {
+		t.Fatalf("unable to marshal update params. error: %s", err.Error())
+	}
+	return payload
+}
+
+// AssertCreatePayload - pins the wire contract of the create params, the json the client sends has to equal wantJSON
+// The json is compared by value, whitespace and the order of object keys do not matter, missing and extra fields do
+func AssertCreatePayload(t testing.TB, createParams accountlib.AccountCreateParams, wantJSON string) bool {
+	t.Helper()
+	return assertPayload(t, CreatePayload(t, createParams), wantJSON)
+}
+
+// AssertUpdatePayload - pins the wire contract of the update params, like AssertCreatePayload
+func AssertUpdatePayload(t testing.TB, updateParams accountlib.AccountUpdateParams, wantJSON string) bool {
+	t.Helper()
+	return assertPayload(t, UpdatePayload(t, updateParams), wantJSON)
+}
+
+// assertPayload - compares a payload with the wanted json by value, reporting both indented when they differ
+func assertPayload(t testing.TB, payload []byte, wantJSON string) bool {
+	t.Helper()
+	var want, got interface{}
+	if err := json.Unmarshal([]byte(wantJSON), &want); err != nil {
+		t.Fatalf("invalid wanted json. error: %s", err.Error())
+	}
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("invalid payload. error: %s", err.Error())
+	}
+	if reflect.DeepEqual(want, got) {
+		return true
+	}
+	wantIndented, _ := json.MarshalIndent(want, "", "  ")
+	gotIndented, _ := json.MarshalIndent(got, "", "  ")
+	t.Errorf("payload differs from the wanted json\nwant: %s\ngot:  %s", wantIndented, gotIndented)
+	return false
+}
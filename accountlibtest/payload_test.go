@@ -0,0 +1,44 @@
+package accountlibtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+)
+
+// TestAssertCreatePayload - tests the create payload is compared by value
+func TestAssertCreatePayload(t *testing.T) {
+	check := assert.New(t)
+	createParams := accountlib.AccountCreateParams{
+		ID:             "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		Type:           "accounts",
+		Attributes:     &accountlib.AccountCreateAttributes{Bic: "NWBKGB22", Name: []string{"Jane Doe"}},
+	}
+
+	check.True(AssertCreatePayload(t, createParams, `{"data": {
+		"type": "accounts",
+		"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		"organisation_id": "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		"attributes": {"name": ["Jane Doe"], "bic": "NWBKGB22"}
+	}}`))
+
+	// a renamed field is reported
+	recorder := &testing.T{}
+	check.False(AssertCreatePayload(recorder, createParams, `{"data": {
+		"type": "accounts",
+		"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		"organisation_id": "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		"attributes": {"name": ["Jane Doe"], "bank_identifier_code": "NWBKGB22"}
+	}}`))
+	check.True(recorder.Failed())
+}
+
+// TestAssertUpdatePayload - tests the update payload holds the version of the update params
+func TestAssertUpdatePayload(t *testing.T) {
+	version := int64(2)
+	updateParams := accountlib.AccountUpdateParams{ID: "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", Version: &version}
+	AssertUpdatePayload(t, updateParams, `{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "version": 2}}`)
+}
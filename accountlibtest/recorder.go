@@ -0,0 +1,87 @@
+package accountlibtest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// RecordedRequest - request sent by a client, URL holds the path and query without scheme and host
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Body   []byte
+}
+
+// RequestRecorder - round tripper recording the requests a client sends before passing them on, for snapshots of
+// the wire format. Pass HTTPClient() in the client options
+type RequestRecorder struct {
+	transport http.RoundTripper
+	mutex     sync.Mutex
+	requests  []RecordedRequest
+}
+
+// NewRequestRecorder - returns a recorder passing requests on to the transport, nil uses http.DefaultTransport
+func NewRequestRecorder(transport http.RoundTripper) *RequestRecorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &RequestRecorder{transport: transport}
+}
+
+// HTTPClient - returns an http client sending its requests through the recorder
+func (recorder *RequestRecorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: recorder}
+}
+
+// RoundTrip - records the request and passes it on, the body is restored for the transport
+func (recorder *RequestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	recorder.mutex.Lock()
+	recorder.requests = append(recorder.requests, RecordedRequest{Method: req.Method, URL: req.URL.RequestURI(), Body: body})
+	recorder.mutex.Unlock()
+	return recorder.transport.RoundTrip(req)
+}
+
+// Requests - returns the recorded requests in the order they were sent
+func (recorder *RequestRecorder) Requests() []RecordedRequest {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return append([]RecordedRequest(nil), recorder.requests...)
+}
+
+// Reset - forgets the recorded requests
+func (recorder *RequestRecorder) Reset() {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.requests = nil
+}
+
+// AssertRequestsGolden - compares the method, url and exact body of the requests with a golden file in testdata,
+// catching accidental wire format changes like renamed or omitted fields. Bodies should not hold random values
+// Setting ACCOUNTLIB_UPDATE_GOLDEN=1 rewrites the golden file instead
+func AssertRequestsGolden(t testing.TB, name string, requests []RecordedRequest) {
+	t.Helper()
+	var snapshot bytes.Buffer
+	for i, request := range requests {
+		if i > 0 {
+			snapshot.WriteString("\n")
+		}
+		snapshot.WriteString(request.Method + " " + request.URL + "\n")
+		if len(request.Body) > 0 {
+			snapshot.Write(request.Body)
+			snapshot.WriteString("\n")
+		}
+	}
+	AssertGolden(t, name, snapshot.Bytes())
+}
@@ -0,0 +1,31 @@
+package accountlibtest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRequestRecorder - tests requests are recorded with their body and passed on unchanged
+func TestRequestRecorder(t *testing.T) {
+	check := assert.New(t)
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+	recorder := NewRequestRecorder(nil)
+
+	response, err := recorder.HTTPClient().Post(server.URL+"/v1/organisation/accounts?dry_run=true", "application/json", bytes.NewReader([]byte(`{"data":{}}`)))
+	check.Nil(err)
+	response.Body.Close()
+	check.Equal(`{"data":{}}`, string(received))
+	check.Equal([]RecordedRequest{{Method: http.MethodPost, URL: "/v1/organisation/accounts?dry_run=true", Body: []byte(`{"data":{}}`)}}, recorder.Requests())
+
+	recorder.Reset()
+	check.Empty(recorder.Requests())
+}
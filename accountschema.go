@@ -0,0 +1,64 @@
+package accountlib
+
+import (
+	"accountlib/schema"
+)
+
+// AccountSchema - json schema of the account resource, responses are validated against it with ClientOptions.ValidateResponses
+// It describes the wire format of AccountData, attributes unknown to this library are allowed
+const AccountSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "account",
+  "type": "object",
+  "required": ["id", "organisation_id", "type"],
+  "properties": {
+    "id": {"type": "string", "pattern": "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$"},
+    "organisation_id": {"type": "string", "pattern": "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$"},
+    "type": {"type": "string", "enum": ["accounts"]},
+    "version": {"type": "integer", "minimum": 0},
+    "relationships": {"type": "object", "additionalProperties": {"type": "object"}},
+    "links": {"type": "object", "additionalProperties": {"type": "string"}},
+    "attributes": {
+      "type": "object",
+      "properties": {
+        "account_classification": {"type": "string", "enum": ["Personal", "Business"]},
+        "account_matching_opt_out": {"type": "boolean"},
+        "account_number": {"type": "string"},
+        "alternative_names": {"type": ["array", "null"], "maxItems": 3, "items": {"type": "string", "maxLength": 140}},
+        "bank_id": {"type": "string"},
+        "bank_id_code": {"type": "string"},
+        "base_currency": {"type": "string", "pattern": "^[A-Z]{3}$"},
+        "bic": {"type": "string", "pattern": "^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$"},
+        "country": {"type": "string", "pattern": "^[A-Z]{2}$"},
+        "iban": {"type": "string"},
+        "joint_account": {"type": "boolean"},
+        "name": {"type": ["array", "null"], "maxItems": 4, "items": {"type": "string", "maxLength": 140}},
+        "secondary_identification": {"type": "string", "maxLength": 140},
+        "status": {"type": "string", "enum": ["pending", "confirmed", "failed", "closed"]},
+        "status_reason": {"type": "string"},
+        "switched": {"type": "boolean"},
+        "processing_service": {"type": "string"},
+        "user_defined_information": {"type": "string"},
+        "validation_type": {"type": "string"},
+        "reference_mask": {"type": "string"},
+        "acceptance_qualifier": {"type": "string"}
+      }
+    }
+  }
+}`
+
+// schemas of the responses holding a single account and a page of accounts
+var (
+	accountResponseSchema     = schema.MustCompile([]byte(`{"type": "object", "required": ["data"], "properties": {"data": ` + AccountSchema + `}}`))
+	accountListResponseSchema = schema.MustCompile([]byte(`{"type": "object", "required": ["data"], "properties": {"data": {"type": "array", "items": ` + AccountSchema + `}}}`))
+)
+
+// responseSchema - returns the schema of a single account response
+func (envelope *accountEnvelope) responseSchema() *schema.Schema {
+	return accountResponseSchema
+}
+
+// responseSchema - returns the schema of an account list response
+func (envelope *accountListEnvelope) responseSchema() *schema.Schema {
+	return accountListResponseSchema
+}
@@ -0,0 +1,52 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/schema"
+)
+
+// TestAccountSchema - tests responses of the api match the account schema
+func TestAccountSchema(t *testing.T) {
+	check := assert.New(t)
+	check.Nil(accountResponseSchema.Validate([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		"organisation_id": "35eedc2c-0318-40dc-a090-d6f42e7b2754", "type": "accounts", "version": 0,
+		"attributes": {"country": "GB", "base_currency": "GBP", "bic": "NWBKGB22", "name": ["Jane Doe"],
+		"status": "confirmed", "unknown_attribute": "value"}}}`)))
+	check.Nil(accountListResponseSchema.Validate([]byte(`{"data": [], "links": {"self": "/v1/organisation/accounts"}}`)))
+}
+
+// TestValidateResponses - tests responses are validated against the schema before decoding
+func TestValidateResponses(t *testing.T) {
+	check := assert.New(t)
+	response := `{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "organisation_id": "35eedc2c-0318-40dc-a090-d6f42e7b2754", "type": "accounts", "attributes": {"country": "gb"}}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[size]") != "" {
+			_, _ = w.Write([]byte(`<html><body>Welcome to the gateway</body></html>`))
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	// without validation the response decodes
+	accountData, err := NewClient(&ClientOptions{BaseURL: server.URL}).Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal("gb", string(*accountData.Attributes.Country))
+
+	client := NewClient(&ClientOptions{BaseURL: server.URL, ValidateResponses: true})
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	validationError := &schema.ValidationError{}
+	check.True(errors.As(err, &validationError))
+	check.Equal([]schema.Violation{{Path: "/data/attributes/country", Message: `value "gb" does not match pattern "^[A-Z]{2}$"`}}, validationError.Violations)
+
+	_, err = client.List(context.Background(), ListParams{PageSize: 10})
+	check.True(errors.As(err, &validationError))
+	check.Contains(err.Error(), "received invalid response. error: schema validation failed: /: invalid json: invalid character '<'")
+}
@@ -0,0 +1,73 @@
+package accountlib
+
+import (
+	"strings"
+
+	"accountlib/redact"
+	"accountlib/validate"
+)
+
+// Anonymizer - replaces the personal data of accounts, the names, alternative names, account number, iban and
+// secondary identification, so accounts of production can be loaded into test environments
+// Other attributes, including the extra attributes unknown to this library, are kept
+type Anonymizer struct {
+	tokenizer *redact.Tokenizer
+}
+
+// NewMaskingAnonymizer - returns an anonymizer masking personal data
+// Names and secondary identifications are masked completely, account numbers and ibans keep their last characters
+func NewMaskingAnonymizer() *Anonymizer {
+	return &Anonymizer{}
+}
+
+// NewTokenizingAnonymizer - returns an anonymizer replacing personal data with tokens derived from the key
+// Tokens keep the format of the values, equal values get equal tokens and ibans get valid check digits, so anonymized
+// accounts pass validation. Keep the key secret, tokens of known values can be recomputed with it
+func NewTokenizingAnonymizer(key []byte) *Anonymizer {
+	return &Anonymizer{tokenizer: redact.NewTokenizer(key)}
+}
+
+// Anonymize - returns a copy of the account with its personal data replaced
+func (anonymizer *Anonymizer) Anonymize(account *AccountData) *AccountData {
+	anonymized := account.Clone()
+	if anonymized == nil || anonymized.Attributes == nil {
+		return anonymized
+	}
+	attributes := anonymized.Attributes
+	for i := range attributes.Name {
+		attributes.Name[i] = anonymizer.name(attributes.Name[i])
+	}
+	for i := range attributes.AlternativeNames {
+		attributes.AlternativeNames[i] = anonymizer.name(attributes.AlternativeNames[i])
+	}
+	attributes.SecondaryIdentification = anonymizer.name(attributes.SecondaryIdentification)
+	attributes.AccountNumber = anonymizer.number(attributes.AccountNumber)
+	attributes.Iban = anonymizer.iban(attributes.Iban)
+	return anonymized
+}
+
+// name - anonymizes a name, which is masked completely
+func (anonymizer *Anonymizer) name(value string) string {
+	if anonymizer.tokenizer != nil {
+		return anonymizer.tokenizer.Token(value)
+	}
+	return strings.Repeat("*", len([]rune(value)))
+}
+
+// number - anonymizes an account number, which keeps its last characters when masked
+func (anonymizer *Anonymizer) number(value string) string {
+	if anonymizer.tokenizer != nil {
+		return anonymizer.tokenizer.Token(value)
+	}
+	return redact.Mask(value)
+}
+
+// iban - anonymizes an iban, tokens keep the country code and get the check digits of the tokenized account number
+func (anonymizer *Anonymizer) iban(value string) string {
+	if anonymizer.tokenizer == nil || validate.IBAN(value) != nil {
+		return anonymizer.number(value)
+	}
+	iban := strings.Replace(value, " ", "", -1)
+	bban := anonymizer.tokenizer.Token(iban)[4:]
+	return iban[:2] + validate.IBANCheckDigits(iban[:2], bban) + bban
+}
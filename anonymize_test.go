@@ -0,0 +1,58 @@
+package accountlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/validate"
+)
+
+// anonymizeAccount - returns an account holding personal data
+func anonymizeAccount() *AccountData {
+	return &AccountData{
+		ID: "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		Attributes: &AccountAttributes{
+			AccountNumber:           "31926819",
+			AlternativeNames:        []string{"Sam Holder"},
+			BankID:                  "400300",
+			Iban:                    "GB29NWBK60161331926819",
+			Name:                    []string{"Samantha Holder"},
+			SecondaryIdentification: "A1B2C3D4",
+		},
+	}
+}
+
+// TestAnonymizeMask - tests personal data is masked and the account itself is not changed
+func TestAnonymizeMask(t *testing.T) {
+	check := assert.New(t)
+	account := anonymizeAccount()
+
+	anonymized := NewMaskingAnonymizer().Anonymize(account)
+	check.Equal([]string{"***************"}, anonymized.Attributes.Name)
+	check.Equal([]string{"**********"}, anonymized.Attributes.AlternativeNames)
+	check.Equal("********", anonymized.Attributes.SecondaryIdentification)
+	check.Equal("****6819", anonymized.Attributes.AccountNumber)
+	check.Equal("******************6819", anonymized.Attributes.Iban)
+	check.Equal("400300", anonymized.Attributes.BankID)
+	check.Equal(anonymizeAccount(), account)
+}
+
+// TestAnonymizeTokenize - tests personal data is replaced with deterministic tokens and ibans stay valid
+func TestAnonymizeTokenize(t *testing.T) {
+	check := assert.New(t)
+	anonymizer := NewTokenizingAnonymizer([]byte("key"))
+
+	anonymized := anonymizer.Anonymize(anonymizeAccount())
+	check.Equal(anonymized, anonymizer.Anonymize(anonymizeAccount()))
+	check.Regexp(`^[A-Z][a-z]{7} [A-Z][a-z]{5}$`, anonymized.Attributes.Name[0])
+	check.NotEqual("Samantha Holder", anonymized.Attributes.Name[0])
+	check.Regexp(`^[0-9]{8}$`, anonymized.Attributes.AccountNumber)
+	check.NotEqual("31926819", anonymized.Attributes.AccountNumber)
+	check.Regexp(`^[A-Z][0-9][A-Z][0-9][A-Z][0-9][A-Z][0-9]$`, anonymized.Attributes.SecondaryIdentification)
+	check.Equal("GB", anonymized.Attributes.Iban[:2])
+	check.NotEqual("GB29NWBK60161331926819", anonymized.Attributes.Iban)
+	check.Nil(validate.IBAN(anonymized.Attributes.Iban))
+	check.NotEqual(anonymized, NewTokenizingAnonymizer([]byte("other key")).Anonymize(anonymizeAccount()))
+	check.Nil(anonymizer.Anonymize(nil))
+}
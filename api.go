@@ -0,0 +1,51 @@
+package accountlib
+
+import (
+	"context"
+)
+
+// AccountFetcher - fetches accounts
+type AccountFetcher interface {
+	Fetch(ctx context.Context, accountID string, options ...CallOption) (*AccountData, error)
+}
+
+// AccountCreator - creates accounts
+type AccountCreator interface {
+	Create(ctx context.Context, createParams AccountCreateParams, options ...CallOption) (*AccountData, error)
+}
+
+// AccountUpdater - updates accounts
+type AccountUpdater interface {
+	Update(ctx context.Context, updateParams AccountUpdateParams, options ...CallOption) (*AccountData, error)
+}
+
+// AccountDeleter - deletes accounts
+type AccountDeleter interface {
+	Delete(ctx context.Context, accountID string, version *int64, options ...CallOption) error
+}
+
+// AccountLister - lists accounts
+type AccountLister interface {
+	List(ctx context.Context, listParams ListParams, options ...CallOption) (*AccountList, error)
+}
+
+// AccountsAPI - the account operations of the client, declare dependencies with it for replacing the client in tests
+// Dependencies needing a single operation are better declared with the capability interface of it, like AccountFetcher.
+// *Client and the in-memory accountlibtest.Client implement it
+type AccountsAPI interface {
+	AccountFetcher
+	AccountCreator
+	AccountUpdater
+	AccountDeleter
+	AccountLister
+}
+
+// the client implements the account operations and each of their capability interfaces
+var (
+	_ AccountsAPI    = (*Client)(nil)
+	_ AccountFetcher = (*Client)(nil)
+	_ AccountCreator = (*Client)(nil)
+	_ AccountUpdater = (*Client)(nil)
+	_ AccountDeleter = (*Client)(nil)
+	_ AccountLister  = (*Client)(nil)
+)
@@ -0,0 +1,43 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeFetcher - fake of the only operation needed by accountExists, fetching the accounts it holds
+type fakeFetcher map[string]*AccountData
+
+// Fetch - returns the account with the id, or an error for unknown accounts
+func (fetcher fakeFetcher) Fetch(_ context.Context, accountID string, _ ...CallOption) (*AccountData, error) {
+	if account, ok := fetcher[accountID]; ok {
+		return account.Clone(), nil
+	}
+	return nil, errors.New("record does not exist")
+}
+
+// accountExists - dependency declared with the capability interface of the single operation it needs
+func accountExists(ctx context.Context, fetcher AccountFetcher, accountID string) bool {
+	_, err := fetcher.Fetch(ctx, accountID)
+	return err == nil
+}
+
+// TestAccountFetcher - tests dependencies declared with a capability interface accept small fakes and the client
+func TestAccountFetcher(t *testing.T) {
+	check := assert.New(t)
+	fetcher := fakeFetcher{"7eb322ba-57f6-465c-b600-79f26ac7fdc3": {ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}
+	check.True(accountExists(context.Background(), fetcher, "7eb322ba-57f6-465c-b600-79f26ac7fdc3"))
+	check.False(accountExists(context.Background(), fetcher, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(accountData["7eb322ba-57f6-465c-b600-79f26ac7fdc3"])
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+	check.True(accountExists(context.Background(), client, "7eb322ba-57f6-465c-b600-79f26ac7fdc3"))
+}
@@ -0,0 +1,134 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"accountlib/jobs"
+)
+
+// default number of asynchronous creates running at a time
+const defaultAsyncWorkers = 8
+
+// AccountFuture - result of a create running in the background, see CreateAsync
+// err holds the error of a create which could not be started, like creates of a shut down client
+type AccountFuture struct {
+	handle  *jobs.Handle
+	account *AccountData
+	err     error
+}
+
+// AsyncOption - changes the way CreateAsync runs a create
+type AsyncOption func(options *asyncOptions)
+
+// asyncOptions - holds the options of an asynchronous create
+type asyncOptions struct {
+	callOptions          []CallOption
+	confirmationInterval time.Duration
+}
+
+// WithAsyncCallOptions - applies the call options to the requests of the asynchronous create
+func WithAsyncCallOptions(options ...CallOption) AsyncOption {
+	return func(asyncOptions *asyncOptions) {
+		asyncOptions.callOptions = append(asyncOptions.callOptions, options...)
+	}
+}
+
+// WithConfirmationWait - waits for the created account to leave the pending status, polling it every interval
+func WithConfirmationWait(interval time.Duration) AsyncOption {
+	return func(asyncOptions *asyncOptions) {
+		asyncOptions.confirmationInterval = interval
+	}
+}
+
+// CreateAsync - creates an account in the background, the returned future holds the created account once done
+// Creates run as jobs of the job pool of the client, at most ClientOptions.AsyncWorkers at a time. Cancelling ctx or
+// the future stops the create and the confirmation wait, the future fails with the context error then
+func (client *Client) CreateAsync(ctx context.Context, createParams AccountCreateParams, options ...AsyncOption) *AccountFuture {
+	asyncOptions := &asyncOptions{}
+	for _, option := range options {
+		option(asyncOptions)
+	}
+	future := &AccountFuture{}
+	handle, err := client.jobs.Enqueue(ctx, jobs.Job{
+		Name: OperationCreate + " " + createParams.ID,
+		Run: func(ctx context.Context) (err error) {
+			ctx = admit(ctx)
+			future.account, err = client.Create(ctx, createParams, asyncOptions.callOptions...)
+			if err == nil && asyncOptions.confirmationInterval > 0 {
+				future.account, err = client.WaitForConfirmation(ctx, future.account.ID, asyncOptions.confirmationInterval, asyncOptions.callOptions...)
+			}
+			return err
+		},
+	})
+	future.handle, future.err = handle, err
+	return future
+}
+
+// Done - returns a channel closed once the create is done
+func (future *AccountFuture) Done() <-chan struct{} {
+	if future.handle == nil {
+		return closedChannel
+	}
+	return future.handle.Done()
+}
+
+// Result - waits for the create to be done and returns the created account or the error of the create
+func (future *AccountFuture) Result() (*AccountData, error) {
+	if future.handle == nil {
+		return nil, future.err
+	}
+	if err := future.handle.Err(); err != nil {
+		return nil, err
+	}
+	return future.account, nil
+}
+
+// Cancel - cancels the create, a create which was sent already may still be processed by the api
+func (future *AccountFuture) Cancel() {
+	if future.handle != nil {
+		future.handle.Cancel()
+	}
+}
+
+// closedChannel - done channel of futures which failed before their create was started
+var closedChannel = func() chan struct{} {
+	closed := make(chan struct{})
+	close(closed)
+	return closed
+}()
+
+// WaitForConfirmation - polls an account every interval until its status is no longer pending
+// Accounts without status are not pending. Accounts whose status turned failed are returned along with an error
+func (client *Client) WaitForConfirmation(ctx context.Context, accountID string, interval time.Duration, options ...CallOption) (*AccountData, error) {
+	if interval <= 0 {
+		return nil, errors.New("invalid interval: must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		account, err := client.Fetch(ctx, accountID, options...)
+		if err != nil {
+			return nil, err
+		}
+		status := AccountStatus("")
+		if account.Attributes != nil && account.Attributes.Status != nil {
+			status = *account.Attributes.Status
+		}
+		switch status {
+		case AccountStatusPending:
+		case AccountStatusFailed:
+			return account, fmt.Errorf("account %s failed: %s", accountID, account.Attributes.StatusReason)
+		default:
+			return account, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
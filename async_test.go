@@ -0,0 +1,110 @@
+package accountlib_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/accountlibtest"
+	"accountlib/fakeserver"
+)
+
+// TestCreateAsync - tests the future holds the created account once the create is done
+func TestCreateAsync(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	fixture := accountlibtest.ValidGBAccount()
+
+	future := client.CreateAsync(context.Background(), fixture.CreateParams(), accountlib.WithAsyncCallOptions(accountlib.WithTimeout(time.Second)))
+	<-future.Done()
+	account, err := future.Result()
+	check.Nil(err)
+	check.Equal(fixture.CreateParams().ID, account.ID)
+	check.Len(server.Accounts(), 1)
+
+	account, err = client.CreateAsync(context.Background(), fixture.CreateParams()).Result()
+	check.Nil(account)
+	check.Contains(err.Error(), "request conflict")
+}
+
+// TestCreateAsyncConfirmation - tests the future waits for the account to leave the pending status
+func TestCreateAsyncConfirmation(t *testing.T) {
+	check := assert.New(t)
+	fetches := int32(0)
+	status := func(status string) string {
+		return fmt.Sprintf(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "type": "accounts", "version": 0, "attributes": {"status": %q, "status_reason": "unavailable"}}}`, status)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, status("pending"))
+			return
+		}
+		switch atomic.AddInt32(&fetches, 1) {
+		case 1, 2:
+			fmt.Fprint(w, status("pending"))
+		default:
+			fmt.Fprint(w, status("confirmed"))
+		}
+	}))
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	createParams := accountlib.AccountCreateParams{ID: "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"}
+
+	account, err := client.CreateAsync(context.Background(), createParams, accountlib.WithConfirmationWait(time.Millisecond)).Result()
+	check.Nil(err)
+	check.Equal(accountlib.AccountStatusConfirmed, *account.Attributes.Status)
+	check.Equal(int32(3), atomic.LoadInt32(&fetches))
+}
+
+// TestCreateAsyncCancel - tests cancelling the future stops the confirmation wait
+func TestCreateAsyncCancel(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		fmt.Fprint(w, `{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "type": "accounts", "version": 0, "attributes": {"status": "pending"}}}`)
+	}))
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	createParams := accountlib.AccountCreateParams{ID: "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"}
+
+	future := client.CreateAsync(context.Background(), createParams, accountlib.WithConfirmationWait(time.Hour))
+	select {
+	case <-future.Done():
+		t.Fatal("future done before it was cancelled")
+	case <-time.After(50 * time.Millisecond):
+	}
+	future.Cancel()
+	account, err := future.Result()
+	check.Nil(account)
+	check.True(errors.Is(err, context.Canceled))
+}
+
+// TestWaitForConfirmationFailed - tests accounts which failed are returned with an error
+func TestWaitForConfirmationFailed(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "type": "accounts", "version": 0, "attributes": {"status": "failed", "status_reason": "unavailable"}}}`)
+	}))
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	account, err := client.WaitForConfirmation(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", time.Millisecond)
+	check.EqualError(err, "account ad27e265-9605-4b4b-a0e5-3003ea9cc4dc failed: unavailable")
+	check.Equal(accountlib.AccountStatusFailed, *account.Attributes.Status)
+
+	_, err = client.WaitForConfirmation(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", 0)
+	check.EqualError(err, "invalid interval: must be positive")
+}
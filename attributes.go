@@ -0,0 +1,161 @@
+package accountlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// json field names modelled by the attribute structs
+var (
+	accountAttributesFields       = jsonFieldNames(reflect.TypeOf(AccountAttributes{}))
+	accountCreateAttributesFields = jsonFieldNames(reflect.TypeOf(AccountCreateAttributes{}))
+)
+
+// UnmarshalJSON - decodes account attributes, keeping attributes unknown to this library in Extra
+func (attributes *AccountAttributes) UnmarshalJSON(data []byte) error {
+	type accountAttributes AccountAttributes
+	decoded := accountAttributes{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	extra, err := extraFields(data, accountAttributesFields)
+	if err != nil {
+		return err
+	}
+	*attributes = AccountAttributes(decoded)
+	attributes.Extra = extra
+	return nil
+}
+
+// MarshalJSON - encodes account attributes along with the attributes held in Extra
+func (attributes AccountAttributes) MarshalJSON() ([]byte, error) {
+	type accountAttributes AccountAttributes
+	data, err := json.Marshal(accountAttributes(attributes))
+	if err != nil {
+		return nil, err
+	}
+	return mergeExtraFields(data, attributes.Extra)
+}
+
+// UnmarshalJSON - decodes account create attributes, keeping attributes unknown to this library in Extra
+func (attributes *AccountCreateAttributes) UnmarshalJSON(data []byte) error {
+	type accountCreateAttributes AccountCreateAttributes
+	decoded := accountCreateAttributes{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	extra, err := extraFields(data, accountCreateAttributesFields)
+	if err != nil {
+		return err
+	}
+	*attributes = AccountCreateAttributes(decoded)
+	attributes.Extra = extra
+	return nil
+}
+
+// MarshalJSON - encodes account create attributes along with the attributes held in Extra
+func (attributes AccountCreateAttributes) MarshalJSON() ([]byte, error) {
+	type accountCreateAttributes AccountCreateAttributes
+	data, err := json.Marshal(accountCreateAttributes(attributes))
+	if err != nil {
+		return nil, err
+	}
+	return mergeExtraFields(data, attributes.Extra)
+}
+
+// CreateAttributes - returns the attributes which can be sent in create and update params
+// The status and status reason are set by the api and left out, attributes held in Extra are copied
+func (attributes *AccountAttributes) CreateAttributes() *AccountCreateAttributes {
+	if attributes == nil {
+		return nil
+	}
+	clone := attributes.Clone()
+	return &AccountCreateAttributes{
+		AccountClassification:   clone.AccountClassification,
+		AccountMatchingOptOut:   clone.AccountMatchingOptOut,
+		AccountNumber:           clone.AccountNumber,
+		AlternativeNames:        clone.AlternativeNames,
+		BankID:                  clone.BankID,
+		BankIDCode:              clone.BankIDCode,
+		BaseCurrency:            clone.BaseCurrency,
+		Bic:                     clone.Bic,
+		Country:                 clone.Country,
+		Iban:                    clone.Iban,
+		JointAccount:            clone.JointAccount,
+		Name:                    clone.Name,
+		SecondaryIdentification: clone.SecondaryIdentification,
+		Switched:                clone.Switched,
+		ProcessingService:       clone.ProcessingService,
+		UserDefinedInformation:  clone.UserDefinedInformation,
+		ValidationType:          clone.ValidationType,
+		ReferenceMask:           clone.ReferenceMask,
+		AcceptanceQualifier:     clone.AcceptanceQualifier,
+		Extra:                   clone.Extra,
+	}
+}
+
+// extraFields - returns the fields of a json object which are not part of the known field names
+func extraFields(data []byte, known map[string]bool) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var extra map[string]json.RawMessage
+	for name, value := range fields {
+		if known[name] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[name] = value
+	}
+	return extra, nil
+}
+
+// mergeExtraFields - adds extra fields to an encoded json object, modelled fields take precedence
+func mergeExtraFields(data []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return data, nil
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// jsonFieldNames - returns the json field names of a struct type
+func jsonFieldNames(structType reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < structType.NumField(); i++ {
+		tag := structType.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// unknownAttributesError - returns an error listing unknown attribute names, nil if there are none
+func unknownAttributesError(extra map[string]json.RawMessage) error {
+	if len(extra) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("json: unknown attributes %q", names)
+}
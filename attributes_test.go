@@ -0,0 +1,76 @@
+package accountlib
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAttributesUnmarshalExtra - tests unknown attributes are kept in Extra
+func TestAttributesUnmarshalExtra(t *testing.T) {
+	check := assert.New(t)
+	attributes := AccountAttributes{}
+
+	err := json.Unmarshal([]byte(`{"bank_id":"400300","new_field":{"nested":true}}`), &attributes)
+	check.Equal(err, nil)
+	check.Equal(attributes.BankID, "400300")
+	check.Equal(len(attributes.Extra), 1)
+	check.JSONEq(string(attributes.Extra["new_field"]), `{"nested":true}`)
+}
+
+// TestAttributesUnmarshalWithoutExtra - tests Extra stays nil when all attributes are known
+func TestAttributesUnmarshalWithoutExtra(t *testing.T) {
+	check := assert.New(t)
+	attributes := AccountAttributes{}
+
+	err := json.Unmarshal([]byte(`{"bank_id":"400300"}`), &attributes)
+	check.Equal(err, nil)
+	check.Nil(attributes.Extra)
+}
+
+// TestAttributesRoundTrip - tests unknown attributes survive a decode and encode cycle
+func TestAttributesRoundTrip(t *testing.T) {
+	check := assert.New(t)
+	original := `{"bank_id":"400300","new_field":"value"}`
+	attributes := AccountAttributes{}
+
+	err := json.Unmarshal([]byte(original), &attributes)
+	check.Equal(err, nil)
+	encoded, err := json.Marshal(attributes)
+	check.Equal(err, nil)
+	check.JSONEq(string(encoded), original)
+}
+
+// TestCreateAttributesMarshalExtra - tests extra create attributes are sent without overriding modelled ones
+func TestCreateAttributesMarshalExtra(t *testing.T) {
+	check := assert.New(t)
+	attributes := &AccountCreateAttributes{
+		BankID: "400300",
+		Extra: map[string]json.RawMessage{
+			"bank_id":   json.RawMessage(`"ignored"`),
+			"new_field": json.RawMessage(`"value"`),
+		},
+	}
+
+	encoded, err := json.Marshal(AccountCreateParams{Attributes: attributes})
+	check.Equal(err, nil)
+	check.JSONEq(string(encoded), `{"attributes":{"bank_id":"400300","new_field":"value"}}`)
+}
+
+// TestAttributesCreateAttributes - tests account attributes convert into create attributes without the status
+func TestAttributesCreateAttributes(t *testing.T) {
+	check := assert.New(t)
+	attributes := AccountAttributes{}
+	err := json.Unmarshal([]byte(`{"bank_id":"400300","country":"GB","name":["Jane Doe"],"status":"confirmed","status_reason":"unspecified","new_field":"value"}`), &attributes)
+	check.Equal(err, nil)
+
+	createAttributes := attributes.CreateAttributes()
+	encoded, err := json.Marshal(createAttributes)
+	check.Equal(err, nil)
+	check.JSONEq(string(encoded), `{"bank_id":"400300","country":"GB","name":["Jane Doe"],"new_field":"value"}`)
+
+	createAttributes.Name[0] = "John Doe"
+	check.Equal(attributes.Name[0], "Jane Doe")
+	check.Nil((*AccountAttributes)(nil).CreateAttributes())
+}
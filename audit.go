@@ -0,0 +1,91 @@
+package accountlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// outcomes of audited calls
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditRecord - a mutating call of the client, StatusCode is 0 when no response was received
+// Actor is the actor of the call set with WithActor, or else ClientOptions.AuditActor
+type AuditRecord struct {
+	Time           time.Time `json:"time"`
+	Operation      string    `json:"operation"`
+	AccountID      string    `json:"account_id"`
+	OrganisationID string    `json:"organisation_id,omitempty"`
+	Actor          string    `json:"actor,omitempty"`
+	Outcome        string    `json:"outcome"`
+	StatusCode     int       `json:"status_code,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// Auditor - receives a record of every create, update and delete of the client once the call is done, including
+// failed and rejected calls. Set it in ClientOptions, it is called concurrently by concurrent calls
+type Auditor interface {
+	Audit(record AuditRecord)
+}
+
+// AuditWriter - auditor appending the records as json lines to a writer, open files with os.O_APPEND
+// The first error writing a record is kept and returned by Err, later records are not written
+type AuditWriter struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// NewAuditWriter - returns an auditor writing records to w
+func NewAuditWriter(w io.Writer) *AuditWriter {
+	return &AuditWriter{encoder: json.NewEncoder(w)}
+}
+
+// Audit - writes the record as a single json line
+func (writer *AuditWriter) Audit(record AuditRecord) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if writer.err != nil {
+		return
+	}
+	if err := writer.encoder.Encode(record); err != nil {
+		writer.err = fmt.Errorf("unable to write audit record. error: %w", err)
+	}
+}
+
+// Err - returns the error writing a record, nil when every record was written
+func (writer *AuditWriter) Err() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return writer.err
+}
+
+// audit - passes the record of a mutating call to the auditor of the client
+// It is deferred first thing in the call, so it records the final error after it was wrapped
+func (client *Client) audit(operation, accountID, organisationID string, options []CallOption, statusCode int, err error) {
+	if client.auditor == nil {
+		return
+	}
+	record := AuditRecord{
+		Time:           time.Now().UTC(),
+		Operation:      operation,
+		AccountID:      accountID,
+		OrganisationID: organisationID,
+		Actor:          client.auditActor,
+		Outcome:        AuditSuccess,
+		StatusCode:     statusCode,
+	}
+	if actor := newCallOptions(options).actor; actor != "" {
+		record.Actor = actor
+	}
+	if err != nil {
+		record.Outcome = AuditFailure
+		record.Error = err.Error()
+	}
+	client.auditor.Audit(record)
+}
@@ -0,0 +1,106 @@
+package accountlib_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/fakeserver"
+)
+
+// auditRecords - returns the records written by an audit writer
+func auditRecords(t *testing.T, buffer *bytes.Buffer) []accountlib.AuditRecord {
+	var records []accountlib.AuditRecord
+	scanner := bufio.NewScanner(buffer)
+	for scanner.Scan() {
+		record := accountlib.AuditRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestAudit - tests creates, updates and deletes are audited with their outcome and actor and fetches are not
+func TestAudit(t *testing.T) {
+	check := assert.New(t)
+	accountID := "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	organisationID := "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	server := fakeserver.New()
+	defer server.Close()
+	buffer := &bytes.Buffer{}
+	auditWriter := accountlib.NewAuditWriter(buffer)
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL, Auditor: auditWriter, AuditActor: "migration"})
+	ctx := context.Background()
+
+	_, err := client.Create(ctx, accountlib.AccountCreateParams{ID: accountID, OrganisationID: organisationID, Type: "accounts"}, accountlib.WithActor("jane"))
+	check.Nil(err)
+	_, err = client.Fetch(ctx, accountID)
+	check.Nil(err)
+	version := int64(3)
+	_, err = client.Update(ctx, accountlib.AccountUpdateParams{ID: accountID, OrganisationID: organisationID, Type: "accounts", Version: &version})
+	check.NotNil(err)
+	check.Nil(client.Delete(ctx, accountID, new(int64)))
+	check.Nil(auditWriter.Err())
+
+	records := auditRecords(t, buffer)
+	check.Len(records, 3)
+	check.Equal(accountlib.OperationCreate, records[0].Operation)
+	check.Equal(accountID, records[0].AccountID)
+	check.Equal(organisationID, records[0].OrganisationID)
+	check.Equal("jane", records[0].Actor)
+	check.Equal(accountlib.AuditSuccess, records[0].Outcome)
+	check.Equal(201, records[0].StatusCode)
+	check.False(records[0].Time.IsZero())
+
+	check.Equal(accountlib.OperationUpdate, records[1].Operation)
+	check.Equal("migration", records[1].Actor)
+	check.Equal(accountlib.AuditFailure, records[1].Outcome)
+	check.Equal(409, records[1].StatusCode)
+	check.Contains(records[1].Error, "request conflict")
+
+	check.Equal(accountlib.OperationDelete, records[2].Operation)
+	check.Equal(accountlib.AuditSuccess, records[2].Outcome)
+	check.Equal(204, records[2].StatusCode)
+}
+
+// TestAuditRejectedCall - tests calls rejected before a request is sent are audited as failures
+func TestAuditRejectedCall(t *testing.T) {
+	check := assert.New(t)
+	buffer := &bytes.Buffer{}
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: "http://localhost", ReadOnly: true, Auditor: accountlib.NewAuditWriter(buffer)})
+
+	check.NotNil(client.Delete(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", new(int64)))
+	check.NotNil(client.Delete(context.Background(), "invalid", new(int64)))
+
+	records := auditRecords(t, buffer)
+	check.Len(records, 2)
+	check.Equal(accountlib.AuditFailure, records[0].Outcome)
+	check.Equal(0, records[0].StatusCode)
+	check.Contains(records[0].Error, "client is read only")
+	check.Equal("invalid", records[1].AccountID)
+}
+
+// failingWriter - writer failing every write
+type failingWriter struct{}
+
+// Write - fails the write
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// TestAuditWriterError - tests the error writing a record is kept
+func TestAuditWriterError(t *testing.T) {
+	check := assert.New(t)
+	auditWriter := accountlib.NewAuditWriter(failingWriter{})
+
+	auditWriter.Audit(accountlib.AuditRecord{Operation: accountlib.OperationCreate})
+	check.EqualError(auditWriter.Err(), "unable to write audit record. error: disk full")
+}
@@ -0,0 +1,164 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"accountlib/credentials"
+	"accountlib/httprequest"
+)
+
+// authorization constants
+const (
+	authorizationHeader = "Authorization"
+	tokenExpiryMargin   = 30 * time.Second
+)
+
+// TokenProvider - provides the bearer tokens of a client, like tokens of an oauth2 client credentials flow
+// Token returns the current token, which may be cached. Invalidate drops a cached token after the api rejected it
+// with 401, so the next call of Token returns a fresh one
+type TokenProvider interface {
+	Token(ctx context.Context) (string, error)
+	Invalidate(token string)
+}
+
+// AccessToken - bearer token along with its expiry, a zero expiry never expires
+type AccessToken struct {
+	Token  string    `json:"token"`
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// valid - reports whether the token is set and does not expire within the expiry margin
+func (token AccessToken) valid(now time.Time) bool {
+	return token.Token != "" && (token.Expiry.IsZero() || now.Add(tokenExpiryMargin).Before(token.Expiry))
+}
+
+// TokenSource - fetches a fresh token, like from the token endpoint of an oauth2 server
+type TokenSource func(ctx context.Context) (AccessToken, error)
+
+// CredentialTokenSource - returns a token source reading the token of a credential of the store, without expiry
+// Cached tokens are read again after the api rejected them, so rotated tokens are picked up
+func CredentialTokenSource(store credentials.CredentialStore, name string) TokenSource {
+	return func(ctx context.Context) (AccessToken, error) {
+		token, err := store.Credential(ctx, name)
+		if err != nil {
+			return AccessToken{}, fmt.Errorf("unable to read token credential %s. error: %w", name, err)
+		}
+		return AccessToken{Token: string(token)}, nil
+	}
+}
+
+// CachingTokenProvider - token provider caching the tokens of a token source until shortly before they expire
+// Concurrent callers share a single fetch of the token source. With a token cache, tokens are persisted as well
+type CachingTokenProvider struct {
+	source TokenSource
+	cache  TokenCache
+	mutex  sync.Mutex
+	token  AccessToken
+	now    func() time.Time
+}
+
+// NewCachingTokenProvider - returns a token provider caching the tokens of the token source
+func NewCachingTokenProvider(source TokenSource) *CachingTokenProvider {
+	return &CachingTokenProvider{source: source, now: time.Now}
+}
+
+// NewPersistentTokenProvider - returns a token provider caching the tokens of the token source in memory and in the
+// token cache, valid tokens of the cache are used before fetching a fresh one from the token source
+func NewPersistentTokenProvider(source TokenSource, cache TokenCache) *CachingTokenProvider {
+	return &CachingTokenProvider{source: source, cache: cache, now: time.Now}
+}
+
+// Token - returns the cached token, fetching a fresh one when there is none or it is about to expire
+// Unreadable token caches are treated as empty and tokens which could not be persisted are used anyway,
+// so a broken token cache costs fetches but does not fail requests
+func (provider *CachingTokenProvider) Token(ctx context.Context) (string, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	if provider.token.valid(provider.now()) {
+		return provider.token.Token, nil
+	}
+	if provider.cache != nil {
+		if cached, err := provider.cache.Load(ctx); err == nil && cached.valid(provider.now()) {
+			provider.token = cached
+			return cached.Token, nil
+		}
+	}
+	token, err := provider.source(ctx)
+	if err != nil {
+		return "", err
+	}
+	if token.Token == "" {
+		return "", errors.New("token source returned an empty token")
+	}
+	provider.token = token
+	if provider.cache != nil {
+		_ = provider.cache.Store(ctx, token)
+	}
+	return token.Token, nil
+}
+
+// Invalidate - drops the cached token when it is the given token, tokens refreshed in between are kept
+// The token cache is cleared as well when it still holds the given token
+func (provider *CachingTokenProvider) Invalidate(token string) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	if provider.token.Token == token {
+		provider.token = AccessToken{}
+	}
+	if provider.cache != nil {
+		if cached, err := provider.cache.Load(context.Background()); err == nil && cached.Token == token {
+			_ = provider.cache.Store(context.Background(), AccessToken{})
+		}
+	}
+}
+
+// authorize - sets a bearer token of the token provider on a copy of the request headers, returning the token
+// Requests of clients without token provider and requests with an Authorization header of a call option are left as they are
+func (client *Client) authorize(specs *httprequest.RequestSpecifications) (string, error) {
+	if client.tokenProvider == nil || specs.Headers.Get(authorizationHeader) != "" {
+		return "", nil
+	}
+	return client.setToken(specs)
+}
+
+// setToken - sets the current token of the token provider on a copy of the request headers
+func (client *Client) setToken(specs *httprequest.RequestSpecifications) (string, error) {
+	token, err := client.tokenProvider.Token(specs.Context)
+	if err != nil {
+		return "", &tokenError{err: err}
+	}
+	headers := make(http.Header, len(specs.Headers)+1)
+	for name, values := range specs.Headers {
+		headers[name] = values
+	}
+	headers.Set(authorizationHeader, "Bearer "+token)
+	specs.Headers = headers
+	return token, nil
+}
+
+// tokenError - returned when the token provider fails, the request was not sent with a token
+type tokenError struct {
+	err error
+}
+
+// Error - returns the error message with the error of the token provider
+func (e *tokenError) Error() string {
+	return fmt.Sprintf("unable to get token. error: %s", e.err.Error())
+}
+
+// Unwrap - returns the error of the token provider
+func (e *tokenError) Unwrap() error {
+	return e.err
+}
+
+// refreshToken - invalidates a token rejected by the api and sets a fresh token on the request
+func (client *Client) refreshToken(specs *httprequest.RequestSpecifications, rejected string) error {
+	client.tokenProvider.Invalidate(rejected)
+	_, err := client.setToken(specs)
+	return err
+}
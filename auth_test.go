@@ -0,0 +1,207 @@
+package accountlib
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/credentials"
+	"accountlib/errors"
+	"accountlib/signing"
+)
+
+// TestTokenRefreshOn401 - tests requests rejected with 401 are sent once more with a fresh token
+func TestTokenRefreshOn401(t *testing.T) {
+	check := assert.New(t)
+	var mutex sync.Mutex
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mutex.Unlock()
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`))
+	}))
+	defer server.Close()
+	fetched := 0
+	provider := NewCachingTokenProvider(func(ctx context.Context) (AccessToken, error) {
+		fetched++
+		return AccessToken{Token: "token-" + string(rune('0'+fetched))}, nil
+	})
+	client := NewClient(&ClientOptions{BaseURL: server.URL, TokenProvider: provider, Token: "static"})
+
+	accountData, err := client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Nil(err)
+	check.Equal("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", accountData.ID)
+	check.Equal([]string{"Bearer token-1", "Bearer token-2"}, authorizations)
+
+	// the refreshed token is cached for the next requests
+	_, err = client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Nil(err)
+	check.Equal(2, fetched)
+
+	// requests are retried exactly once, a fresh token rejected again surfaces the 401
+	authorizations = nil
+	provider.Invalidate("token-2")
+	_, err = client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	statusCode, _ := accounterrors.StatusCode(err)
+	check.Equal(http.StatusUnauthorized, statusCode)
+	check.Equal([]string{"Bearer token-3", "Bearer token-4"}, authorizations)
+
+	// authorization headers of call options take precedence over the token provider
+	authorizations = nil
+	_, err = client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", WithHeader("Authorization", "Bearer token-2"))
+	check.Nil(err)
+	check.Equal([]string{"Bearer token-2"}, authorizations)
+}
+
+// TestTokenProviderError - tests requests are not sent when the token provider fails
+func TestTokenProviderError(t *testing.T) {
+	check := assert.New(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	provider := NewCachingTokenProvider(func(ctx context.Context) (AccessToken, error) {
+		return AccessToken{}, errors.New("token endpoint unavailable")
+	})
+	client := NewClient(&ClientOptions{BaseURL: server.URL, TokenProvider: provider})
+
+	_, err := client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Contains(err.Error(), "unable to get token. error: token endpoint unavailable")
+	check.Equal(0, requests)
+}
+
+// TestCachingTokenProvider - tests tokens are cached until shortly before they expire and only matching tokens are invalidated
+func TestCachingTokenProvider(t *testing.T) {
+	check := assert.New(t)
+	now := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+	fetched := 0
+	provider := NewCachingTokenProvider(func(ctx context.Context) (AccessToken, error) {
+		fetched++
+		return AccessToken{Token: "token-" + string(rune('0'+fetched)), Expiry: now.Add(time.Minute)}, nil
+	})
+	provider.now = func() time.Time { return now }
+
+	token, err := provider.Token(context.Background())
+	check.Nil(err)
+	check.Equal("token-1", token)
+	token, _ = provider.Token(context.Background())
+	check.Equal("token-1", token)
+
+	provider.Invalidate("token-0")
+	token, _ = provider.Token(context.Background())
+	check.Equal("token-1", token)
+
+	now = now.Add(31 * time.Second)
+	token, _ = provider.Token(context.Background())
+	check.Equal("token-2", token)
+
+	provider.Invalidate("token-2")
+	token, _ = provider.Token(context.Background())
+	check.Equal("token-3", token)
+
+	empty := NewCachingTokenProvider(func(ctx context.Context) (AccessToken, error) { return AccessToken{}, nil })
+	_, err = empty.Token(context.Background())
+	check.EqualError(err, "token source returned an empty token")
+}
+
+// TestSignedRequests - tests every attempt of a request is signed with the active key of the signer
+func TestSignedRequests(t *testing.T) {
+	check := assert.New(t)
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = append(signatures, r.Header.Get(signing.SignatureHeader))
+		if len(signatures) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`))
+	}))
+	defer server.Close()
+	signer, err := signing.NewSigner(signing.Key{ID: "2021-q1", PrivateKey: ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))})
+	check.Nil(err)
+	client := NewClient(&ClientOptions{BaseURL: server.URL, Signer: signer})
+
+	_, err = client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Nil(err)
+	check.Len(signatures, 2)
+	for _, signature := range signatures {
+		check.Contains(signature, `keyId="2021-q1",algorithm="ed25519"`)
+	}
+}
+
+// TestVerifiedResponses - tests responses with bad signatures fail with ErrBadSignature and are not retried
+func TestVerifiedResponses(t *testing.T) {
+	check := assert.New(t)
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	apiSigner, err := signing.NewSigner(signing.Key{ID: "api-2021", PrivateKey: privateKey})
+	check.Nil(err)
+	body := []byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`)
+	var requests int
+	tamper := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		check.Nil(apiSigner.SignResponse(w.Header(), body))
+		if tamper {
+			_, _ = w.Write([]byte(`{"data": {"id": "00000000-9605-4b4b-a0e5-3003ea9cc4dc"}}`))
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	verifier, err := signing.NewVerifier(map[string]crypto.PublicKey{"api-2021": publicKey}, true)
+	check.Nil(err)
+	client := NewClient(&ClientOptions{BaseURL: server.URL, ResponseVerifier: verifier})
+
+	account, err := client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Nil(err)
+	check.Equal("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", account.ID)
+
+	tamper = true
+	_, err = client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.True(errors.Is(err, accounterrors.ErrBadSignature))
+	var signatureError *accounterrors.SignatureError
+	check.True(errors.As(err, &signatureError))
+	check.Equal("api-2021", signatureError.KeyID)
+	check.Equal(2, requests)
+}
+
+// TestCredentialStoreToken - tests the token credential of the store is sent and read again after a 401
+func TestCredentialStoreToken(t *testing.T) {
+	check := assert.New(t)
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer rotated" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	tokens := []string{"expired", "rotated"}
+	store := credentials.StoreFunc(func(ctx context.Context, name string) ([]byte, error) {
+		check.Equal(credentials.TokenName, name)
+		token := tokens[0]
+		tokens = tokens[1:]
+		return []byte(token), nil
+	})
+	client := NewClient(&ClientOptions{BaseURL: server.URL, CredentialStore: store, Token: "ignored"})
+
+	_, err := client.List(context.Background(), ListParams{})
+	check.Nil(err)
+	check.Equal([]string{"Bearer expired", "Bearer rotated"}, authorizations)
+}
@@ -0,0 +1,173 @@
+package accountlib
+
+// default resource type of accounts
+const defaultAccountType = "accounts"
+
+// AccountBuilder - builds AccountCreateParams without the pointer boilerplate of the params struct
+type AccountBuilder struct {
+	params AccountCreateParams
+}
+
+// NewAccountBuilder - returns a builder defaulting to a generated account id and the accounts type
+func NewAccountBuilder() *AccountBuilder {
+	return newAccountBuilder(UUIDGenerator)
+}
+
+// NewAccountBuilder - returns a builder defaulting to an account id of the id generator of the client
+func (client *Client) NewAccountBuilder() *AccountBuilder {
+	return newAccountBuilder(client.newID)
+}
+
+// newAccountBuilder - returns a builder defaulting to an account id of the generator and the accounts type
+func newAccountBuilder(generator IDGenerator) *AccountBuilder {
+	return &AccountBuilder{
+		params: AccountCreateParams{
+			ID:         generator(),
+			Type:       defaultAccountType,
+			Attributes: &AccountCreateAttributes{},
+		},
+	}
+}
+
+// ID - sets the account id
+func (builder *AccountBuilder) ID(id string) *AccountBuilder {
+	builder.params.ID = id
+	return builder
+}
+
+// OrganisationID - sets the organisation id
+func (builder *AccountBuilder) OrganisationID(organisationID string) *AccountBuilder {
+	builder.params.OrganisationID = organisationID
+	return builder
+}
+
+// Type - sets the resource type
+func (builder *AccountBuilder) Type(resourceType string) *AccountBuilder {
+	builder.params.Type = resourceType
+	return builder
+}
+
+// AccountClassification - sets the account classification
+func (builder *AccountBuilder) AccountClassification(classification AccountClassification) *AccountBuilder {
+	builder.params.Attributes.AccountClassification = &classification
+	return builder
+}
+
+// AccountMatchingOptOut - sets the account matching opt out flag
+func (builder *AccountBuilder) AccountMatchingOptOut(optOut bool) *AccountBuilder {
+	builder.params.Attributes.AccountMatchingOptOut = &optOut
+	return builder
+}
+
+// AccountNumber - sets the account number
+func (builder *AccountBuilder) AccountNumber(accountNumber string) *AccountBuilder {
+	builder.params.Attributes.AccountNumber = accountNumber
+	return builder
+}
+
+// AlternativeNames - sets the alternative names of the account holder
+func (builder *AccountBuilder) AlternativeNames(names ...string) *AccountBuilder {
+	builder.params.Attributes.AlternativeNames = names
+	return builder
+}
+
+// BankID - sets the bank id
+func (builder *AccountBuilder) BankID(bankID string) *AccountBuilder {
+	builder.params.Attributes.BankID = bankID
+	return builder
+}
+
+// BankIDCode - sets the bank id code
+func (builder *AccountBuilder) BankIDCode(bankIDCode string) *AccountBuilder {
+	builder.params.Attributes.BankIDCode = bankIDCode
+	return builder
+}
+
+// BaseCurrency - sets the base currency
+func (builder *AccountBuilder) BaseCurrency(currency CurrencyCode) *AccountBuilder {
+	builder.params.Attributes.BaseCurrency = currency
+	return builder
+}
+
+// Bic - sets the bic
+func (builder *AccountBuilder) Bic(bic string) *AccountBuilder {
+	builder.params.Attributes.Bic = bic
+	return builder
+}
+
+// Country - sets the country
+func (builder *AccountBuilder) Country(country CountryCode) *AccountBuilder {
+	builder.params.Attributes.Country = &country
+	return builder
+}
+
+// Iban - sets the iban
+func (builder *AccountBuilder) Iban(iban string) *AccountBuilder {
+	builder.params.Attributes.Iban = iban
+	return builder
+}
+
+// JointAccount - sets the joint account flag
+func (builder *AccountBuilder) JointAccount(joint bool) *AccountBuilder {
+	builder.params.Attributes.JointAccount = &joint
+	return builder
+}
+
+// Name - sets the names of the account holder
+func (builder *AccountBuilder) Name(names ...string) *AccountBuilder {
+	builder.params.Attributes.Name = names
+	return builder
+}
+
+// SecondaryIdentification - sets the secondary identification
+func (builder *AccountBuilder) SecondaryIdentification(identification string) *AccountBuilder {
+	builder.params.Attributes.SecondaryIdentification = identification
+	return builder
+}
+
+// Switched - sets the switched flag
+func (builder *AccountBuilder) Switched(switched bool) *AccountBuilder {
+	builder.params.Attributes.Switched = &switched
+	return builder
+}
+
+// ProcessingService - sets the processing service
+func (builder *AccountBuilder) ProcessingService(service string) *AccountBuilder {
+	builder.params.Attributes.ProcessingService = service
+	return builder
+}
+
+// UserDefinedInformation - sets the user defined information
+func (builder *AccountBuilder) UserDefinedInformation(information string) *AccountBuilder {
+	builder.params.Attributes.UserDefinedInformation = information
+	return builder
+}
+
+// ValidationType - sets the validation type
+func (builder *AccountBuilder) ValidationType(validationType string) *AccountBuilder {
+	builder.params.Attributes.ValidationType = validationType
+	return builder
+}
+
+// ReferenceMask - sets the reference mask
+func (builder *AccountBuilder) ReferenceMask(mask string) *AccountBuilder {
+	builder.params.Attributes.ReferenceMask = mask
+	return builder
+}
+
+// AcceptanceQualifier - sets the acceptance qualifier
+func (builder *AccountBuilder) AcceptanceQualifier(qualifier string) *AccountBuilder {
+	builder.params.Attributes.AcceptanceQualifier = qualifier
+	return builder
+}
+
+// MasterAccount - sets the master account of a virtual account
+func (builder *AccountBuilder) MasterAccount(accountID string) *AccountBuilder {
+	builder.params.Relationships = &AccountRelationships{MasterAccount: NewMasterAccountRelationship(accountID)}
+	return builder
+}
+
+// Build - returns the create params, the builder can be reused afterwards without affecting them
+func (builder *AccountBuilder) Build() AccountCreateParams {
+	return *builder.params.Clone()
+}
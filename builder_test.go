@@ -0,0 +1,58 @@
+package accountlib
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAccountBuilderDefaults - tests the builder fills the account id and type
+func TestAccountBuilderDefaults(t *testing.T) {
+	check := assert.New(t)
+	params := NewAccountBuilder().Build()
+
+	_, err := uuid.Parse(params.ID)
+	check.Equal(err, nil)
+	check.Equal(params.Type, "accounts")
+	check.NotNil(params.Attributes)
+}
+
+// TestClientAccountBuilder - tests builders of the client use the id generator of the client
+func TestClientAccountBuilder(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(&ClientOptions{IDGenerator: func() string { return "7eb322ba-57f6-465c-b600-79f26ac7fdc3" }})
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", client.NewAccountBuilder().Build().ID)
+
+	_, err := uuid.Parse(NewClient(nil).NewAccountBuilder().Build().ID)
+	check.Nil(err)
+}
+
+// TestAccountBuilderFields - tests the builder sets pointer and slice fields
+func TestAccountBuilderFields(t *testing.T) {
+	check := assert.New(t)
+	params := NewAccountBuilder().
+		ID("7eb322ba-57f6-465c-b600-79f26ac7fdc3").
+		Country("GB").
+		BankID("400300").
+		JointAccount(true).
+		Name("Samantha Holder").
+		Build()
+
+	check.Equal(params.ID, "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Equal(*params.Attributes.Country, CountryCode("GB"))
+	check.Equal(params.Attributes.BankID, "400300")
+	check.Equal(*params.Attributes.JointAccount, true)
+	check.Equal(params.Attributes.Name, []string{"Samantha Holder"})
+}
+
+// TestAccountBuilderReuse - tests built params are not affected by later builder calls
+func TestAccountBuilderReuse(t *testing.T) {
+	check := assert.New(t)
+	builder := NewAccountBuilder().Country("GB")
+	first := builder.Build()
+	second := builder.Country("FR").Build()
+
+	check.Equal(*first.Attributes.Country, CountryCode("GB"))
+	check.Equal(*second.Attributes.Country, CountryCode("FR"))
+}
@@ -0,0 +1,110 @@
+package accountlib
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"accountlib/httprequest"
+)
+
+// CallOption - overrides a client default for a single call, e.g. client.Fetch(ctx, id, accountlib.WithTimeout(2*time.Second))
+type CallOption func(options *callOptions)
+
+// callOptions - holds the overrides of a single call
+type callOptions struct {
+	timeout     time.Duration
+	retries     *int
+	retryPolicy *httprequest.RetryPolicy
+	headers     http.Header
+	actor       string
+}
+
+// WithTimeout - limits the call including its retries to the timeout, the deadline of the context of the call still applies
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(options *callOptions) {
+		options.timeout = timeout
+	}
+}
+
+// WithRetries - sets the number of retries of the call, WithRetries(0) and negative retries send the request exactly once
+func WithRetries(retries int) CallOption {
+	return func(options *callOptions) {
+		options.retries = &retries
+	}
+}
+
+// WithRetryPolicy - sets the retry policy of the call, its non zero fields override the retry defaults of the client
+func WithRetryPolicy(policy httprequest.RetryPolicy) CallOption {
+	return func(options *callOptions) {
+		options.retryPolicy = &policy
+	}
+}
+
+// WithHeader - sets a header of the call, replacing a default header of the client with the same name
+func WithHeader(name, value string) CallOption {
+	return func(options *callOptions) {
+		if options.headers == nil {
+			options.headers = make(http.Header)
+		}
+		options.headers.Add(name, value)
+	}
+}
+
+// WithActor - sets the actor recorded by the auditor of the client for the call, like the user of an internal tool
+func WithActor(actor string) CallOption {
+	return func(options *callOptions) {
+		options.actor = actor
+	}
+}
+
+// newCallOptions - returns the overrides of the call options
+func newCallOptions(options []CallOption) *callOptions {
+	callOptions := &callOptions{}
+	for _, option := range options {
+		option(callOptions)
+	}
+	return callOptions
+}
+
+// applyCallOptions - applies the call options to the specifications of a request
+// The returned function releases the resources of the timeout and must be called once the request is done
+func applyCallOptions(specs *httprequest.RequestSpecifications, options []CallOption) context.CancelFunc {
+	callOptions := newCallOptions(options)
+	if callOptions.retries != nil {
+		// negative retries mean no retries, they would make the retry count of the specifications invalid
+		retries := *callOptions.retries
+		if retries < 0 {
+			retries = 0
+		}
+		specs.DisableRetries = retries == 0
+		specs.RetryCount = retries + 1
+	}
+	if callOptions.retryPolicy != nil {
+		specs.RetryPolicy = callOptions.retryPolicy
+		if callOptions.retryPolicy.Attempts > 0 {
+			specs.DisableRetries = callOptions.retryPolicy.Attempts == 1
+		}
+	}
+	if len(callOptions.headers) > 0 {
+		// the headers of the client are shared by its calls, so they are copied before merging
+		headers := make(http.Header, len(specs.Headers)+len(callOptions.headers))
+		for name, values := range specs.Headers {
+			headers[name] = values
+		}
+		for name, values := range callOptions.headers {
+			headers[name] = values
+		}
+		specs.Headers = headers
+	}
+	if callOptions.timeout <= 0 {
+		return func() {}
+	}
+	ctx := specs.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var cancel context.CancelFunc
+	specs.Context, cancel = context.WithTimeout(ctx, callOptions.timeout)
+	return cancel
+}
@@ -0,0 +1,127 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/errors"
+	"accountlib/httprequest"
+)
+
+// TestWithRetries - tests the retries of a call override the retry count of the client
+func TestWithRetries(t *testing.T) {
+	check := assert.New(t)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, RetryCount: 3})
+
+	_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", WithRetries(0))
+	check.Contains(err.Error(), "service unavailable")
+	check.Equal(int32(1), atomic.SwapInt32(&requests, 0))
+
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", WithRetries(-2))
+	check.Contains(err.Error(), "service unavailable")
+	check.Equal(int32(1), atomic.SwapInt32(&requests, 0))
+
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", WithRetries(1))
+	check.Contains(err.Error(), "service unavailable")
+	check.Equal(int32(2), atomic.SwapInt32(&requests, 0))
+
+	// the client default applies to calls without options
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.NotNil(err)
+	check.Equal(int32(3), atomic.SwapInt32(&requests, 0))
+}
+
+// TestWithRetryPolicy - tests the retry policy of a call overrides the retry defaults of the client
+func TestWithRetryPolicy(t *testing.T) {
+	check := assert.New(t)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, DisableRetries: true})
+
+	policy := httprequest.RetryPolicy{Attempts: 4, BackOff: time.Millisecond, RetryIf: func(statusCode int, err error) bool {
+		return statusCode == http.StatusBadGateway
+	}}
+	_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", WithRetryPolicy(policy))
+	exhausted := &accounterrors.RetriesExhaustedError{}
+	check.True(errors.As(err, &exhausted))
+	check.Equal(int32(4), atomic.SwapInt32(&requests, 0))
+
+	// the client default applies to calls without options
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Contains(err.Error(), "bad gateway")
+	check.Equal(int32(1), atomic.SwapInt32(&requests, 0))
+}
+
+// TestWithTimeout - tests the timeout of a call limits the call without changing the client
+func TestWithTimeout(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	start := time.Now()
+	_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", WithTimeout(20*time.Millisecond), WithRetries(0))
+	check.True(errors.Is(err, accounterrors.ErrTimeout))
+	check.True(time.Since(start) < 100*time.Millisecond)
+
+	accountData, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", accountData.ID)
+}
+
+// TestWithHeader - tests the headers of a call are merged with the default headers of the client
+func TestWithHeader(t *testing.T) {
+	check := assert.New(t)
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{
+		BaseURL:        server.URL,
+		DefaultHeaders: http.Header{"x-team": []string{"payments"}, "X-Api-Key": []string{"gateway-key"}},
+		Token:          "secret",
+	})
+
+	_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal("payments", headers.Get("X-Team"))
+	check.Equal("gateway-key", headers.Get("X-Api-Key"))
+	check.Equal("Bearer secret", headers.Get("Authorization"))
+
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", WithHeader("x-team", "risk"), WithHeader("X-Request-Id", "42"))
+	check.Nil(err)
+	check.Equal([]string{"risk"}, headers.Values("X-Team"))
+	check.Equal("gateway-key", headers.Get("X-Api-Key"))
+	check.Equal("42", headers.Get("X-Request-Id"))
+
+	// the headers of a call do not leak into the next call
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal("payments", headers.Get("X-Team"))
+	check.Empty(headers.Get("X-Request-Id"))
+}
@@ -0,0 +1,117 @@
+package chaos
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Operation - accounts api operation latencies are configured for
+type Operation string
+
+// operations recognised from the request method and path
+const (
+	OperationFetch  Operation = "fetch"
+	OperationCreate Operation = "create"
+	OperationDelete Operation = "delete"
+	OperationList   Operation = "list"
+	// OperationAny - applies to operations without a latency of their own
+	OperationAny Operation = "*"
+)
+
+// accounts collection path suffix, used to tell list from fetch requests
+const accountsCollectionSuffix = "/accounts"
+
+// LatencyDistribution - samples artificial latencies
+type LatencyDistribution interface {
+	Sample(random *rand.Rand) time.Duration
+}
+
+// FixedLatency - delays every request by the same duration
+type FixedLatency time.Duration
+
+// Sample - returns the fixed latency
+func (latency FixedLatency) Sample(*rand.Rand) time.Duration {
+	return time.Duration(latency)
+}
+
+// UniformLatency - delays requests by a duration uniformly distributed between Min and Max
+type UniformLatency struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Sample - returns a uniformly distributed latency
+func (latency UniformLatency) Sample(random *rand.Rand) time.Duration {
+	if latency.Max <= latency.Min {
+		return latency.Min
+	}
+	return latency.Min + time.Duration(random.Int63n(int64(latency.Max-latency.Min)))
+}
+
+// ParetoLatency - delays requests by a pareto distributed duration, modelling long tail latencies
+// Scale is the minimum latency, a smaller Shape gives a longer tail, Max caps the latency when set
+type ParetoLatency struct {
+	Scale time.Duration
+	Shape float64
+	Max   time.Duration
+}
+
+// Sample - returns a pareto distributed latency
+func (latency ParetoLatency) Sample(random *rand.Rand) time.Duration {
+	if latency.Shape <= 0 {
+		return latency.Scale
+	}
+	// inverse transform sampling, 1 - Float64 lies in (0, 1]
+	sample := time.Duration(float64(latency.Scale) / math.Pow(1-random.Float64(), 1/latency.Shape))
+	if latency.Max > 0 && (sample > latency.Max || sample < 0) {
+		return latency.Max
+	}
+	return sample
+}
+
+// OperationOf - returns the accounts api operation of a request
+func OperationOf(req *http.Request) Operation {
+	switch req.Method {
+	case http.MethodPost:
+		return OperationCreate
+	case http.MethodDelete:
+		return OperationDelete
+	}
+	if strings.HasSuffix(strings.TrimSuffix(req.URL.Path, "/"), accountsCollectionSuffix) {
+		return OperationList
+	}
+	return OperationFetch
+}
+
+// latencyFor - samples the latency configured for the operation of the request
+func (transport *Transport) latencyFor(req *http.Request) time.Duration {
+	distribution, ok := transport.config.Latency[OperationOf(req)]
+	if !ok {
+		distribution, ok = transport.config.Latency[OperationAny]
+	}
+	if !ok {
+		return 0
+	}
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	return distribution.Sample(transport.random)
+}
+
+// delay - waits for the latency of the request, returning early when the request is cancelled
+func (transport *Transport) delay(req *http.Request) error {
+	latency := transport.latencyFor(req)
+	if latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
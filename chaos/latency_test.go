@@ -0,0 +1,76 @@
+package chaos
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLatencyDistributions - tests sampled latencies stay within their bounds
+func TestLatencyDistributions(t *testing.T) {
+	check := assert.New(t)
+	random := rand.New(rand.NewSource(1))
+
+	check.Equal(FixedLatency(time.Second).Sample(random), time.Second)
+	for i := 0; i < 100; i++ {
+		uniform := UniformLatency{Min: 10 * time.Millisecond, Max: 20 * time.Millisecond}.Sample(random)
+		check.True(uniform >= 10*time.Millisecond && uniform < 20*time.Millisecond)
+		pareto := ParetoLatency{Scale: 10 * time.Millisecond, Shape: 1.5, Max: time.Second}.Sample(random)
+		check.True(pareto >= 10*time.Millisecond && pareto <= time.Second)
+	}
+}
+
+// TestOperationOf - tests operations are recognised from requests
+func TestOperationOf(t *testing.T) {
+	check := assert.New(t)
+	request := func(method, url string) *http.Request {
+		req, _ := http.NewRequest(method, url, nil)
+		return req
+	}
+	check.Equal(OperationOf(request(http.MethodGet, "http://localhost/v1/organisation/accounts")), OperationList)
+	check.Equal(OperationOf(request(http.MethodGet, "http://localhost/v1/organisation/accounts/1")), OperationFetch)
+	check.Equal(OperationOf(request(http.MethodPost, "http://localhost/v1/organisation/accounts")), OperationCreate)
+	check.Equal(OperationOf(request(http.MethodDelete, "http://localhost/v1/organisation/accounts/1")), OperationDelete)
+}
+
+// TestTransportLatencyPerOperation - tests latency is injected for the configured operation only
+func TestTransportLatencyPerOperation(t *testing.T) {
+	check := assert.New(t)
+	server := newTestServer()
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(nil, Config{
+		Latency: map[Operation]LatencyDistribution{OperationList: FixedLatency(50 * time.Millisecond)},
+	})}
+
+	start := time.Now()
+	resp, err := client.Get(server.URL + "/v1/organisation/accounts/1")
+	check.Equal(err, nil)
+	resp.Body.Close()
+	check.True(time.Since(start) < 50*time.Millisecond)
+
+	start = time.Now()
+	resp, err = client.Get(server.URL + "/v1/organisation/accounts")
+	check.Equal(err, nil)
+	resp.Body.Close()
+	check.True(time.Since(start) >= 50*time.Millisecond)
+}
+
+// TestTransportLatencyCancelled - tests a cancelled request stops waiting for the latency
+func TestTransportLatencyCancelled(t *testing.T) {
+	check := assert.New(t)
+	server := newTestServer()
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(nil, Config{
+		Latency: map[Operation]LatencyDistribution{OperationAny: FixedLatency(time.Minute)},
+	})}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	_, err := client.Do(req)
+	check.Contains(err.Error(), "context deadline exceeded")
+}
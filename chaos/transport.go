@@ -0,0 +1,176 @@
+package chaos
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault - kind of failure injected by the transport
+type Fault int
+
+// faults which can be injected
+const (
+	// FaultConnectionReset - fails the request with a connection reset before it reaches the server
+	FaultConnectionReset Fault = iota
+	// FaultServerError - answers with a server error status without reaching the server
+	FaultServerError
+	// FaultMalformedJSON - replaces the response body of the server with malformed json
+	FaultMalformedJSON
+	// FaultTruncatedBody - cuts the response body of the server in half
+	FaultTruncatedBody
+)
+
+// fault injection defaults
+const (
+	defaultServerErrorStatus = http.StatusServiceUnavailable
+	malformedJSON            = `{"data": {"id": `
+)
+
+// allFaults - faults injected when the config does not select any
+var allFaults = []Fault{FaultConnectionReset, FaultServerError, FaultMalformedJSON, FaultTruncatedBody}
+
+// Config - controls which faults are injected and how often
+// Rate is the probability between 0 and 1 of a request being affected, Faults are picked at random
+// A server error starts a burst of BurstLength consecutive server errors, Seed makes the faults reproducible
+// Latency delays requests per operation before they are sent, independently of the fault rate
+type Config struct {
+	Rate              float64
+	Faults            []Fault
+	BurstLength       int
+	ServerErrorStatus int
+	Seed              int64
+	Latency           map[Operation]LatencyDistribution
+}
+
+// Transport - http.RoundTripper wrapping another transport and injecting faults
+// Use it as the transport of the http client passed in ClientOptions
+type Transport struct {
+	base   http.RoundTripper
+	config Config
+
+	mutex          sync.Mutex
+	random         *rand.Rand
+	burstRemaining int
+}
+
+// NewTransport - returns a fault injecting transport, a nil base uses http.DefaultTransport
+func NewTransport(base http.RoundTripper, config Config) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if len(config.Faults) == 0 {
+		config.Faults = allFaults
+	}
+	if config.BurstLength < 1 {
+		config.BurstLength = 1
+	}
+	if config.ServerErrorStatus == 0 {
+		config.ServerErrorStatus = defaultServerErrorStatus
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Transport{
+		base:   base,
+		config: config,
+		random: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// RoundTrip - sends the request through the base transport, injecting latency and faults as configured
+func (transport *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := transport.delay(req); err != nil {
+		closeBody(req)
+		return nil, err
+	}
+
+	fault, inject := transport.nextFault()
+	if !inject {
+		return transport.base.RoundTrip(req)
+	}
+
+	switch fault {
+	case FaultConnectionReset:
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	case FaultServerError:
+		closeBody(req)
+		return syntheticResponse(req, transport.config.ServerErrorStatus, `{"error_message":"injected fault"}`), nil
+	}
+
+	resp, err := transport.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if fault == FaultMalformedJSON {
+		resp.Body = ioutil.NopCloser(bytes.NewBufferString(malformedJSON))
+		resp.ContentLength = int64(len(malformedJSON))
+		resp.Header.Del("Content-Length")
+	} else {
+		resp.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body[:len(body)/2]), errorReader{io.ErrUnexpectedEOF}))
+	}
+	return resp, nil
+}
+
+// nextFault - decides whether the next request gets a fault and which one
+func (transport *Transport) nextFault() (Fault, bool) {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	if transport.burstRemaining > 0 {
+		transport.burstRemaining--
+		return FaultServerError, true
+	}
+	if transport.random.Float64() >= transport.config.Rate {
+		return 0, false
+	}
+	fault := transport.config.Faults[transport.random.Intn(len(transport.config.Faults))]
+	if fault == FaultServerError {
+		transport.burstRemaining = transport.config.BurstLength - 1
+	}
+	return fault, true
+}
+
+// syntheticResponse - returns a response which did not come from the server
+func syntheticResponse(req *http.Request, statusCode int, body string) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(statusCode),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// closeBody - closes the request body of a request which is not sent, as required from round trippers
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// errorReader - reader failing with the given error
+type errorReader struct {
+	err error
+}
+
+// Read - returns the error of the reader
+func (reader errorReader) Read([]byte) (int, error) {
+	return 0, reader.err
+}
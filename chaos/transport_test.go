@@ -0,0 +1,88 @@
+package chaos
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer - returns a server answering with a fixed json body
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`))
+	}))
+}
+
+// TestTransportNoFaults - tests requests pass through with a zero rate
+func TestTransportNoFaults(t *testing.T) {
+	check := assert.New(t)
+	server := newTestServer()
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(nil, Config{Rate: 0})}
+
+	resp, err := client.Get(server.URL)
+	check.Equal(err, nil)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	check.Equal(string(body), `{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`)
+}
+
+// TestTransportConnectionReset - tests connection resets are returned as errors
+func TestTransportConnectionReset(t *testing.T) {
+	check := assert.New(t)
+	server := newTestServer()
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(nil, Config{Rate: 1, Faults: []Fault{FaultConnectionReset}})}
+
+	_, err := client.Get(server.URL)
+	check.True(errors.Is(err, syscall.ECONNRESET))
+}
+
+// TestTransportServerErrorBurst - tests a server error burst fails consecutive requests
+func TestTransportServerErrorBurst(t *testing.T) {
+	check := assert.New(t)
+	server := newTestServer()
+	defer server.Close()
+	transport := NewTransport(nil, Config{Rate: 1, Faults: []Fault{FaultServerError}, BurstLength: 3, Seed: 1})
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		check.Equal(err, nil)
+		resp.Body.Close()
+		check.Equal(resp.StatusCode, http.StatusServiceUnavailable)
+		// only the burst keeps failing once the rate drops
+		transport.config.Rate = 0
+	}
+	resp, err := client.Get(server.URL)
+	check.Equal(err, nil)
+	resp.Body.Close()
+	check.Equal(resp.StatusCode, http.StatusOK)
+}
+
+// TestTransportBodyFaults - tests malformed and truncated response bodies
+func TestTransportBodyFaults(t *testing.T) {
+	check := assert.New(t)
+	server := newTestServer()
+	defer server.Close()
+
+	malformed := &http.Client{Transport: NewTransport(nil, Config{Rate: 1, Faults: []Fault{FaultMalformedJSON}})}
+	resp, err := malformed.Get(server.URL)
+	check.Equal(err, nil)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	check.Equal(string(body), malformedJSON)
+
+	truncated := &http.Client{Transport: NewTransport(nil, Config{Rate: 1, Faults: []Fault{FaultTruncatedBody}})}
+	resp, err = truncated.Get(server.URL)
+	check.Equal(err, nil)
+	body, err = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	check.Equal(string(body), `{"data": {"id": "7eb322ba-57`)
+	check.Contains(err.Error(), "unexpected EOF")
+}
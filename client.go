@@ -1,30 +1,154 @@
 package accountlib
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"accountlib/credentials"
 	"accountlib/errors"
 	"accountlib/httprequest"
+	"accountlib/jobs"
+	"accountlib/schema"
+	"accountlib/signing"
 )
 
 // account api constants
 const (
-	accountBaseURL = "http://localhost:8080"
-	accountPath    = "v1/organisation/accounts"
+	accountBaseURL        = "http://localhost:8080"
+	accountPath           = "organisation/accounts"
+	defaultAPIPathVersion = "v1"
+	organisationIDFilter  = "organisation_id"
+	totalCountHeader      = "X-Total-Count"
 )
 
 // Client - holds account client information
 type Client struct {
-	handler httprequest.RequestHandlerIface
+	handler            httprequest.RequestSender
+	httpClient         *http.Client
+	baseURL            string
+	apiPath            string
+	organisationID     string
+	readOnly           bool
+	strictDecoding     bool
+	validateResponses  bool
+	validateBeforeSend bool
+	retryCount         int
+	disableRetries     bool
+	retryNonIdempotent bool
+	retryIf            func(statusCode int, err error) bool
+	headers            http.Header
+	fetchGroup         *fetchGroup
+	maxListAllRecords  int
+	prefetchPages      bool
+	conflictResolver   ConflictResolver
+	auditor            Auditor
+	auditActor         string
+	offlineQueue       QueueStore
+	jobs               *jobs.Pool
+	throttle           *throttle
+	requestObserver    httprequest.RequestObserver
+	lifecycle          *lifecycle
+	idGenerator        IDGenerator
+	tokenProvider      TokenProvider
+	statusErrors       *accounterrors.StatusErrors
+	stats              *clientStats
+	metrics            Metrics
+	clockSkew          *clockSkew
 }
 
 // ClientOptions - options passed while creating a new client
 // Users can control connection pooling by passing a custom http client
+// Without custom http client, proxies are taken from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables
+// unless DisableProxy is set, and TLSConfig replaces the tls settings, like for client certificates or private CAs
+// BaseURL points the client to a different api instance, it defaults to http://localhost:8080. Base urls like
+// unix:///var/run/accounts.sock send the requests over the unix socket
+// APIPrefix is inserted between the base url and the api version, e.g. /api for proxied deployments serving /api/v1/...
+// APIPathVersion selects the version segment of the api paths, it defaults to v1
+// OrganisationID is used by Create and List when the call does not name an organisation, other organisations are rejected
+// ReadOnly rejects requests changing data with accounterrors.ErrReadOnlyClient before they are sent
+// StrictDecoding rejects responses containing fields unknown to this library, which helps detecting schema drift early
+// ValidateResponses validates account responses against AccountSchema before decoding them, failing with a *schema.ValidationError
+// ValidateBeforeSend validates create params locally before sending them to the api
+// Timeout overrides the timeout of the http client, RetryCount the number of attempts per request,
+// a zero RetryCount uses the default of 3 attempts and DisableRetries sends every request exactly once.
+// RetryIf decides which failed attempts are retried, by default temporary errors and 408, 503 and 504
+// DefaultHeaders are sent with every request, headers of a call set with WithHeader take precedence over them.
+// Token is sent as bearer token with every request. TokenProvider provides the bearer tokens instead, requests rejected
+// with 401 are sent once more with a fresh token of the provider. CredentialStore provides the token credential
+// instead of Token, when there is no TokenProvider
+// Signer signs every request with its active key, keys are rotated with Signer.UseKey without recreating the client
+// ResponseVerifier verifies the signatures of responses, responses failing verification return accounterrors.ErrBadSignature
+// Metrics receives the retries of the client, they are counted in Stats as well
+// ClockSkewThreshold is the skew between the Date header of responses and the local clock reported to OnClockSkew and
+// to Metrics implementing ClockSkewMetrics, it defaults to 30 seconds. MaxClockSkew fails gets whose response shows
+// a larger skew with accounterrors.ErrClockSkew, mutations applied by the api are not failed and their skew is reported
+// only. By default skew only is reported
+// DeduplicateFetches collapses concurrent fetches of the same account without call options into a single request
+// ConflictResolver decides how creates and updates failing with a conflict continue, by default they fail
+// Auditor records every create, update and delete, with AuditActor as actor of calls without WithActor
+// OfflineQueue keeps creates, updates and deletes failing after every retry for replaying them with Drain
+// RateLimit spaces the requests of the client to at most RateLimit requests per second, halving the rate on every 429.
+// Requests are paused whenever the api answers 429 with Retry-After or runs out of rate limit, and 429s are resent
+// RequestObserver is notified of the start, the attempts and the end of every request, for metrics and tracing
+// AsyncWorkers limits the asynchronous creates running at a time, it defaults to 8
+// IDGenerator generates the account ids of builders of the client and the ids of queued mutations, random uuids by default
+// ErrorMessages override or extend the messages of errors by status code for this client, like for 451 or codes of gateways
+// MaxErrorBodySize limits the bytes of response bodies included in error messages, it defaults to 1024
+// MaxListAllRecords limits the accounts returned by ListAll, it defaults to 10000. PrefetchPages lists the next page
+// of ListAll and iterators in the background while the current page is processed
+// Creates and updates are not retried after timeouts and reset connections, since the api may have processed them
+// already. RetryNonIdempotent retries them anyway, requests carrying an Idempotency-Key header are always retried
 type ClientOptions struct {
-	HTTPClient *http.Client
+	HTTPClient         *http.Client
+	DisableProxy       bool
+	TLSConfig          *tls.Config
+	BaseURL            string
+	APIPrefix          string
+	APIPathVersion     string
+	OrganisationID     string
+	ReadOnly           bool
+	StrictDecoding     bool
+	ValidateResponses  bool
+	ValidateBeforeSend bool
+	Timeout            time.Duration
+	RetryCount         int
+	DisableRetries     bool
+	RetryNonIdempotent bool
+	RetryIf            func(statusCode int, err error) bool
+	DefaultHeaders     http.Header
+	Token              string
+	TokenProvider      TokenProvider
+	CredentialStore    credentials.CredentialStore
+	Signer             *signing.Signer
+	ResponseVerifier   *signing.Verifier
+	Metrics            Metrics
+	ClockSkewThreshold time.Duration
+	MaxClockSkew       time.Duration
+	OnClockSkew        func(skew time.Duration)
+	DeduplicateFetches bool
+	MaxListAllRecords  int
+	PrefetchPages      bool
+	ConflictResolver   ConflictResolver
+	Auditor            Auditor
+	AuditActor         string
+	OfflineQueue       QueueStore
+	AsyncWorkers       int
+	RateLimit          float64
+	RequestObserver    httprequest.RequestObserver
+	IDGenerator        IDGenerator
+	ErrorMessages      map[int]string
+	MaxErrorBodySize   int
 }
 
 // AccountCreateParams - holds fields for account creation
@@ -34,30 +158,46 @@ type AccountCreateParams struct {
 	ID             string                   `json:"id,omitempty"`
 	OrganisationID string                   `json:"organisation_id,omitempty"`
 	Type           string                   `json:"type,omitempty"`
+
+	// Relationships - related resources of the account, like the master account of a virtual account
+	Relationships *AccountRelationships `json:"relationships,omitempty"`
 }
 
 // AccountCreateAttributes - holds account attributes for account creation
 // This struct is similar to AccountResponseAttributes but excludes some unnecessary fields for creation
 type AccountCreateAttributes struct {
-	AccountClassification   *string  `json:"account_classification,omitempty"`
-	AccountMatchingOptOut   *bool    `json:"account_matching_opt_out,omitempty"`
-	AccountNumber           string   `json:"account_number,omitempty"`
-	AlternativeNames        []string `json:"alternative_names,omitempty"`
-	BankID                  string   `json:"bank_id,omitempty"`
-	BankIDCode              string   `json:"bank_id_code,omitempty"`
-	BaseCurrency            string   `json:"base_currency,omitempty"`
-	Bic                     string   `json:"bic,omitempty"`
-	Country                 *string  `json:"country,omitempty"`
-	Iban                    string   `json:"iban,omitempty"`
-	JointAccount            *bool    `json:"joint_account,omitempty"`
-	Name                    []string `json:"name,omitempty"`
-	SecondaryIdentification string   `json:"secondary_identification,omitempty"`
-	Switched                *bool    `json:"switched,omitempty"`
-	ProcessingService       string   `json:"processing_service,omitempty"`
-	UserDefinedInformation  string   `json:"user_defined_information,omitempty"`
-	ValidationType          string   `json:"validation_type,omitempty"`
-	ReferenceMask           string   `json:"reference_mask,omitempty"`
-	AcceptanceQualifier     string   `json:"acceptance_qualifier,omitempty"`
+	AccountClassification   *AccountClassification `json:"account_classification,omitempty"`
+	AccountMatchingOptOut   *bool                  `json:"account_matching_opt_out,omitempty"`
+	AccountNumber           string                 `json:"account_number,omitempty"`
+	AlternativeNames        []string               `json:"alternative_names,omitempty"`
+	BankID                  string                 `json:"bank_id,omitempty"`
+	BankIDCode              string                 `json:"bank_id_code,omitempty"`
+	BaseCurrency            CurrencyCode           `json:"base_currency,omitempty"`
+	Bic                     string                 `json:"bic,omitempty"`
+	Country                 *CountryCode           `json:"country,omitempty"`
+	Iban                    string                 `json:"iban,omitempty"`
+	JointAccount            *bool                  `json:"joint_account,omitempty"`
+	Name                    []string               `json:"name,omitempty"`
+	SecondaryIdentification string                 `json:"secondary_identification,omitempty"`
+	Switched                *bool                  `json:"switched,omitempty"`
+	ProcessingService       string                 `json:"processing_service,omitempty"`
+	UserDefinedInformation  string                 `json:"user_defined_information,omitempty"`
+	ValidationType          string                 `json:"validation_type,omitempty"`
+	ReferenceMask           string                 `json:"reference_mask,omitempty"`
+	AcceptanceQualifier     string                 `json:"acceptance_qualifier,omitempty"`
+
+	// Extra - holds attributes which are not modelled by this struct, they are sent along with the modelled ones
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AccountUpdateParams - holds fields for updating an account
+// Version must match the current version of the account, attributes are replaced by the given ones
+type AccountUpdateParams struct {
+	Attributes     *AccountCreateAttributes `json:"attributes,omitempty"`
+	ID             string                   `json:"id,omitempty"`
+	OrganisationID string                   `json:"organisation_id,omitempty"`
+	Type           string                   `json:"type,omitempty"`
+	Version        *int64                   `json:"version,omitempty"`
 }
 
 // AccountData - holds complete account response
@@ -67,134 +207,357 @@ type AccountData struct {
 	OrganisationID string             `json:"organisation_id,omitempty"`
 	Type           string             `json:"type,omitempty"`
 	Version        *int64             `json:"version,omitempty"`
+
+	// Relationships - holds the related resources by relationship name, like the master account of a virtual account
+	Relationships map[string]Relationship `json:"relationships,omitempty"`
+	// Links - holds the links of the account, for fetched, created and updated accounts the links of the response
+	Links *Links `json:"links,omitempty"`
+	// CreatedOn and ModifiedOn - timestamps of the account set by the api, nil when the api does not return them
+	CreatedOn  *time.Time `json:"created_on,omitempty"`
+	ModifiedOn *time.Time `json:"modified_on,omitempty"`
+
+	// unknown - names of fields unknown to this library, rejected by strict decoding
+	unknown []string
 }
 
 // AccountAttributes - holds account attribute response
 type AccountAttributes struct {
-	AccountClassification   *string  `json:"account_classification,omitempty"`
-	AccountMatchingOptOut   *bool    `json:"account_matching_opt_out,omitempty"`
-	AccountNumber           string   `json:"account_number,omitempty"`
-	AlternativeNames        []string `json:"alternative_names,omitempty"`
-	BankID                  string   `json:"bank_id,omitempty"`
-	BankIDCode              string   `json:"bank_id_code,omitempty"`
-	BaseCurrency            string   `json:"base_currency,omitempty"`
-	Bic                     string   `json:"bic,omitempty"`
-	Country                 *string  `json:"country,omitempty"`
-	Iban                    string   `json:"iban,omitempty"`
-	JointAccount            *bool    `json:"joint_account,omitempty"`
-	Name                    []string `json:"name,omitempty"`
-	SecondaryIdentification string   `json:"secondary_identification,omitempty"`
-	Status                  *string  `json:"status,omitempty"`
-	StatusReason            string   `json:"status_reason,omitempty"`
-	Switched                *bool    `json:"switched,omitempty"`
-	ProcessingService       string   `json:"processing_service,omitempty"`
-	UserDefinedInformation  string   `json:"user_defined_information,omitempty"`
-	ValidationType          string   `json:"validation_type,omitempty"`
-	ReferenceMask           string   `json:"reference_mask,omitempty"`
-	AcceptanceQualifier     string   `json:"acceptance_qualifier,omitempty"`
+	AccountClassification   *AccountClassification `json:"account_classification,omitempty"`
+	AccountMatchingOptOut   *bool                  `json:"account_matching_opt_out,omitempty"`
+	AccountNumber           string                 `json:"account_number,omitempty"`
+	AlternativeNames        []string               `json:"alternative_names,omitempty"`
+	BankID                  string                 `json:"bank_id,omitempty"`
+	BankIDCode              string                 `json:"bank_id_code,omitempty"`
+	BaseCurrency            CurrencyCode           `json:"base_currency,omitempty"`
+	Bic                     string                 `json:"bic,omitempty"`
+	Country                 *CountryCode           `json:"country,omitempty"`
+	Iban                    string                 `json:"iban,omitempty"`
+	JointAccount            *bool                  `json:"joint_account,omitempty"`
+	Name                    []string               `json:"name,omitempty"`
+	SecondaryIdentification string                 `json:"secondary_identification,omitempty"`
+	Status                  *AccountStatus         `json:"status,omitempty"`
+	StatusReason            string                 `json:"status_reason,omitempty"`
+	Switched                *bool                  `json:"switched,omitempty"`
+	ProcessingService       string                 `json:"processing_service,omitempty"`
+	UserDefinedInformation  string                 `json:"user_defined_information,omitempty"`
+	ValidationType          string                 `json:"validation_type,omitempty"`
+	ReferenceMask           string                 `json:"reference_mask,omitempty"`
+	AcceptanceQualifier     string                 `json:"acceptance_qualifier,omitempty"`
+
+	// Extra - holds attributes returned by the api which are not modelled by this struct
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// ListParams - controls paging and filtering of account lists
+// PageNumber starts at 0, a zero PageSize uses the page size of the api
+// Filter holds filter values by attribute name, e.g. "country": "GB" is sent as filter[country]=GB
+type ListParams struct {
+	PageNumber int
+	PageSize   int
+	Filter     map[string]string
+}
+
+// AccountList - holds a page of accounts and the links to the other pages
+// TotalCount is the number of accounts matching the filters over all pages, nil when the api does not report it
+type AccountList struct {
+	Data       []AccountData
+	Links      *Links
+	TotalCount *int64
+}
+
+// accountEnvelope - holds the json:api envelope of a single account response
+type accountEnvelope struct {
+	Data  *AccountData `json:"data"`
+	Links *Links       `json:"links,omitempty"`
+}
+
+// account - returns the account of the response, holding the links of the response unless it has links of its own
+func (envelope *accountEnvelope) account() *AccountData {
+	if envelope.Data != nil && envelope.Data.Links == nil {
+		envelope.Data.Links = envelope.Links
+	}
+	return envelope.Data
+}
+
+// unknownFields - returns an error if the account holds fields or attributes unknown to this library
+func (envelope *accountEnvelope) unknownFields() error {
+	if envelope.Data == nil {
+		return nil
+	}
+	return envelope.Data.unknownFields()
+}
+
+// accountListEnvelope - holds the json:api envelope of an account list response
+type accountListEnvelope struct {
+	Data  []AccountData `json:"data"`
+	Links *Links        `json:"links,omitempty"`
+	Meta  *listMeta     `json:"meta,omitempty"`
+}
+
+// listMeta - holds the meta object of a list response, apis report the total count as total or count
+type listMeta struct {
+	Total *int64 `json:"total,omitempty"`
+	Count *int64 `json:"count,omitempty"`
+}
+
+// totalCount - returns the total count of the meta object, or else of the X-Total-Count header
+func (envelope *accountListEnvelope) totalCount(headers http.Header) *int64 {
+	if envelope.Meta != nil && envelope.Meta.Total != nil {
+		return envelope.Meta.Total
+	}
+	if envelope.Meta != nil && envelope.Meta.Count != nil {
+		return envelope.Meta.Count
+	}
+	if total, err := strconv.ParseInt(headers.Get(totalCountHeader), 10, 64); err == nil && total >= 0 {
+		return &total
+	}
+	return nil
+}
+
+// unknownFields - returns an error if any account holds fields or attributes unknown to this library
+func (envelope *accountListEnvelope) unknownFields() error {
+	for i := range envelope.Data {
+		if err := envelope.Data[i].unknownFields(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // NewClient - creates a new account client
 func NewClient(options *ClientOptions) (client *Client) {
 	var httpClient *http.Client
-	client = &Client{}
+	asyncWorkers := defaultAsyncWorkers
+	rateLimit := 0.0
+	var clockSkewThreshold, maxClockSkew time.Duration
+	var onClockSkew func(skew time.Duration)
+	client = &Client{
+		baseURL: accountBaseURL,
+		apiPath: defaultAPIPathVersion,
+		stats:   &clientStats{operations: make(map[string]*OperationStats)},
+	}
 
 	// prepare http client
 	if options != nil {
 		httpClient = options.HTTPClient
+		if options.BaseURL != "" {
+			client.baseURL = strings.TrimSuffix(options.BaseURL, "/")
+		}
+		client.apiPath = apiPath(options.APIPrefix, options.APIPathVersion)
+		client.organisationID = options.OrganisationID
+		client.readOnly = options.ReadOnly
+		client.strictDecoding = options.StrictDecoding
+		client.validateResponses = options.ValidateResponses
+		client.validateBeforeSend = options.ValidateBeforeSend
+		client.retryCount = options.RetryCount
+		client.disableRetries = options.DisableRetries
+		client.retryNonIdempotent = options.RetryNonIdempotent
+		client.retryIf = options.RetryIf
+		client.metrics = options.Metrics
+		client.maxListAllRecords = options.MaxListAllRecords
+		client.prefetchPages = options.PrefetchPages
+		client.conflictResolver = options.ConflictResolver
+		client.auditor = options.Auditor
+		client.auditActor = options.AuditActor
+		client.offlineQueue = options.OfflineQueue
+		rateLimit = options.RateLimit
+		clockSkewThreshold, maxClockSkew, onClockSkew = options.ClockSkewThreshold, options.MaxClockSkew, options.OnClockSkew
+		client.requestObserver = options.RequestObserver
+		client.idGenerator = options.IDGenerator
+		client.statusErrors = accounterrors.NewStatusErrors(options.ErrorMessages)
+		client.statusErrors.MaxBodySize = options.MaxErrorBodySize
+		if options.AsyncWorkers > 0 {
+			asyncWorkers = options.AsyncWorkers
+		}
+		if options.DeduplicateFetches {
+			client.fetchGroup = newFetchGroup()
+		}
+		client.headers = make(http.Header, len(options.DefaultHeaders)+1)
+		for name, values := range options.DefaultHeaders {
+			client.headers[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+		client.tokenProvider = options.TokenProvider
+		if client.tokenProvider == nil && options.CredentialStore != nil {
+			client.tokenProvider = NewCachingTokenProvider(CredentialTokenSource(options.CredentialStore, credentials.TokenName))
+		}
+		if options.Token != "" && client.tokenProvider == nil {
+			client.headers.Set(authorizationHeader, "Bearer "+options.Token)
+		}
+	}
+	handler := httprequest.NewRequestHandler(httpClient)
+	if httpClient == nil && options != nil {
+		if transport, ok := handler.HTTPClient.Transport.(*http.Transport); ok {
+			if options.DisableProxy {
+				transport.Proxy = nil
+			}
+			if options.TLSConfig != nil {
+				transport.TLSClientConfig = options.TLSConfig.Clone()
+			}
+		}
+	}
+	if socketPath, ok := unixSocketPath(client.baseURL); ok {
+		client.baseURL = unixSocketHost
+		handler.HTTPClient = unixSocketClient(handler.HTTPClient, socketPath)
+	}
+	if options != nil && options.Timeout > 0 {
+		// the http client of the options is copied, so the timeout does not leak into other users of it
+		timeoutClient := *handler.HTTPClient
+		timeoutClient.Timeout = options.Timeout
+		handler.HTTPClient = &timeoutClient
+	}
+	if options != nil && options.ResponseVerifier != nil {
+		verifiedClient := *handler.HTTPClient
+		verifiedClient.Transport = options.ResponseVerifier.Transport(verifiedClient.Transport)
+		handler.HTTPClient = &verifiedClient
 	}
-	client.handler = httprequest.NewRequestHandler(httpClient)
+	if options != nil && options.Signer != nil {
+		// requests are signed by the transport, so every attempt carries a fresh date and signature
+		signedClient := *handler.HTTPClient
+		signedClient.Transport = options.Signer.Transport(signedClient.Transport)
+		handler.HTTPClient = &signedClient
+	}
+	client.handler = handler
+	client.httpClient = handler.HTTPClient
+	client.jobs = jobs.NewPool(asyncWorkers)
+	client.throttle = newThrottle(rateLimit)
+	client.clockSkew = newClockSkew(clockSkewThreshold, maxClockSkew, onClockSkew, client.metrics)
+	client.lifecycle = newLifecycle()
 
 	return client
 }
 
 // Fetch - returns the account details based on account id
-func (client *Client) Fetch(accountID string) (accountData *AccountData, err error) {
+func (client *Client) Fetch(ctx context.Context, accountID string, options ...CallOption) (accountData *AccountData, err error) {
+	if client.fetchGroup != nil && len(options) == 0 {
+		return client.sharedFetch(ctx, accountID)
+	}
+	accountData, _, err = client.fetch(ctx, accountID, options)
+	return
+}
+
+// fetch - returns the account details and the status code of the response based on account id
+func (client *Client) fetch(ctx context.Context, accountID string, options []CallOption) (accountData *AccountData, statusCode int, err error) {
 	// validate account id
-	if accountID == "" {
-		err = errors.New("invalid account id")
+	if err = validateID("account id", accountID); err != nil {
 		return
 	}
+	return client.fetchURL(ctx, client.accountsURL(accountID, nil), options)
+}
 
+// fetchURL - returns the account details and the status code of the response of the account at the request url
+func (client *Client) fetchURL(ctx context.Context, requestURL string, options []CallOption) (accountData *AccountData, statusCode int, err error) {
 	// prepare request specifications
-	url := fmt.Sprintf("%s/%s/%s", accountBaseURL, accountPath, accountID)
-	requestSpecifications := &httprequest.RequestSpecifications{
-		HTTPMethod: http.MethodGet,
-		URL:        url,
-	}
+	requestSpecifications := client.requestSpecifications(ctx, OperationFetch, http.MethodGet, requestURL, nil)
+	defer wrapError(&err, requestSpecifications, time.Now())
 
 	// make request
-	statusCode, response, _, err := client.handler.MakeRequest(requestSpecifications)
+	statusCode, response, _, err := client.do(requestSpecifications, options)
 	if err != nil {
 		return
 	}
 
 	// handle status code, response
 	if statusCode == http.StatusOK {
-		dataResponse := make(map[string]AccountData)
-		err = json.Unmarshal(response, &dataResponse)
+		dataResponse := accountEnvelope{}
+		err = client.decodeResponse(response, &dataResponse)
 		if err != nil {
-			err = fmt.Errorf("received invalid response. error: %s", err.Error())
+			err = fmt.Errorf("received invalid response. error: %w", err)
 			return
 		}
-		if accountData, ok := dataResponse["data"]; ok {
-			return &accountData, nil
-		}
+		return dataResponse.account(), statusCode, nil
 	} else {
-		err = accounterrors.HandleErrorStatusCode(statusCode, response)
+		err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
 	}
 
 	return
 }
 
 // Create - creates an account based on create params
-func (client *Client) Create(createParams AccountCreateParams) (accountData *AccountData, err error) {
+func (client *Client) Create(ctx context.Context, createParams AccountCreateParams, options ...CallOption) (*AccountData, error) {
+	accountData, statusCode, err := client.create(ctx, createParams, options)
+	if statusCode == http.StatusConflict && client.conflictResolver != nil {
+		local := AccountUpdateParams{Attributes: createParams.Attributes, ID: createParams.ID, OrganisationID: createParams.OrganisationID, Type: createParams.Type}
+		return client.resolveConflict(ctx, OperationCreate, local, err, options)
+	}
+	if err != nil {
+		err = client.enqueue(QueuedMutation{Operation: OperationCreate, Create: &createParams}, err)
+	}
+	return accountData, err
+}
+
+// create - creates an account based on create params and returns the status code of the response
+func (client *Client) create(ctx context.Context, createParams AccountCreateParams, options []CallOption) (accountData *AccountData, statusCode int, err error) {
+	defer func() {
+		client.audit(OperationCreate, createParams.ID, createParams.OrganisationID, options, statusCode, err)
+	}()
+
+	// validate account id, organisation id and create params
+	if err = validateID("account id", createParams.ID); err != nil {
+		return
+	}
+	if createParams.OrganisationID, err = client.resolveOrganisationID(createParams.OrganisationID); err != nil {
+		return
+	}
+	if err = validateID("organisation id", createParams.OrganisationID); err != nil {
+		return
+	}
+	if client.validateBeforeSend {
+		if err = createParams.Validate(); err != nil {
+			return
+		}
+	}
+
 	// marshal create params
-	dataMap := make(map[string]AccountCreateParams)
-	dataMap["data"] = createParams
-	params, err := json.Marshal(dataMap)
+	params, err := MarshalCreateRequest(createParams)
 	if err != nil {
 		err = fmt.Errorf("unable to marshal create params, error: %s", err.Error())
 		return
 	}
 
 	// prepare request specifications
-	url := fmt.Sprintf("%s/%s", accountBaseURL, accountPath)
-	requestSpecifications := &httprequest.RequestSpecifications{
-		HTTPMethod: http.MethodPost,
-		URL:        url,
-		Params:     params,
-	}
+	requestSpecifications := client.requestSpecifications(ctx, OperationCreate, http.MethodPost, client.accountsURL("", nil), params)
+	defer wrapError(&err, requestSpecifications, time.Now())
 
 	// make request
-	statusCode, response, _, err := client.handler.MakeRequest(requestSpecifications)
+	statusCode, response, _, err := client.do(requestSpecifications, options)
 	if err != nil {
 		return
 	}
 
 	// handle status code, response
 	if statusCode == http.StatusCreated {
-		dataResponse := make(map[string]AccountData)
-		err = json.Unmarshal(response, &dataResponse)
+		dataResponse := accountEnvelope{}
+		err = client.decodeResponse(response, &dataResponse)
 		if err != nil {
-			err = fmt.Errorf("resource created, but received invalid response. error: %s", err.Error())
+			err = fmt.Errorf("resource created, but received invalid response. error: %w", err)
 			return
 		}
-		if accountData, ok := dataResponse["data"]; ok {
-			return &accountData, nil
-		}
+		return dataResponse.account(), statusCode, nil
 	} else {
-		err = accounterrors.HandleErrorStatusCode(statusCode, response)
+		err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
 	}
 
 	return
 }
 
 // Delete  - deletes an account based on account id and version
-func (client *Client) Delete(accountID string, version *int64) (err error) {
+func (client *Client) Delete(ctx context.Context, accountID string, version *int64, options ...CallOption) error {
+	err := client.delete(ctx, accountID, version, options)
+	if err != nil {
+		err = client.enqueue(QueuedMutation{Operation: OperationDelete, AccountID: accountID, Version: version}, err)
+	}
+	return err
+}
+
+// delete - deletes an account based on account id and version
+func (client *Client) delete(ctx context.Context, accountID string, version *int64, options []CallOption) (err error) {
+	statusCode := 0
+	defer func() {
+		client.audit(OperationDelete, accountID, "", options, statusCode, err)
+	}()
+
 	// validate account id, version
-	if accountID == "" {
-		err = errors.New("invalid account id")
+	if err = validateID("account id", accountID); err != nil {
 		return
 	}
 	if version == nil {
@@ -203,22 +566,375 @@ func (client *Client) Delete(accountID string, version *int64) (err error) {
 	}
 
 	// prepare request specifications
-	url := fmt.Sprintf("%s/%s/%s?version=%d", accountBaseURL, accountPath, accountID, *version)
-	requestSpecifications := &httprequest.RequestSpecifications{
-		HTTPMethod: http.MethodDelete,
-		URL:        url,
-	}
+	query := url.Values{"version": []string{strconv.FormatInt(*version, 10)}}
+	requestSpecifications := client.requestSpecifications(ctx, OperationDelete, http.MethodDelete, client.accountsURL(accountID, query), nil)
+	defer wrapError(&err, requestSpecifications, time.Now())
 
 	// make request
-	statusCode, response, _, err := client.handler.MakeRequest(requestSpecifications)
+	statusCode, response, _, err := client.do(requestSpecifications, options)
 	if err != nil {
 		return
 	}
 
 	// handle status code, response
 	if statusCode != http.StatusNoContent {
-		err = accounterrors.HandleErrorStatusCode(statusCode, response)
+		err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
 	}
 
 	return
 }
+
+// Update - updates an account based on update params, the version of the params must match the current version
+func (client *Client) Update(ctx context.Context, updateParams AccountUpdateParams, options ...CallOption) (*AccountData, error) {
+	accountData, statusCode, err := client.update(ctx, updateParams, options)
+	if statusCode == http.StatusConflict && client.conflictResolver != nil {
+		return client.resolveConflict(ctx, OperationUpdate, updateParams, err, options)
+	}
+	if err != nil {
+		err = client.enqueue(QueuedMutation{Operation: OperationUpdate, Update: &updateParams}, err)
+	}
+	return accountData, err
+}
+
+// update - updates an account based on update params and returns the status code of the response
+func (client *Client) update(ctx context.Context, updateParams AccountUpdateParams, options []CallOption) (accountData *AccountData, statusCode int, err error) {
+	defer func() {
+		client.audit(OperationUpdate, updateParams.ID, updateParams.OrganisationID, options, statusCode, err)
+	}()
+
+	// validate account id, version
+	if err = validateID("account id", updateParams.ID); err != nil {
+		return
+	}
+	if updateParams.Version == nil {
+		err = errors.New("invalid version")
+		return
+	}
+
+	// marshal update params
+	params, err := MarshalUpdateRequest(updateParams)
+	if err != nil {
+		err = fmt.Errorf("unable to marshal update params, error: %s", err.Error())
+		return
+	}
+
+	// prepare request specifications
+	requestSpecifications := client.requestSpecifications(ctx, OperationUpdate, http.MethodPatch, client.accountsURL(updateParams.ID, nil), params)
+	defer wrapError(&err, requestSpecifications, time.Now())
+
+	// make request
+	statusCode, response, _, err := client.do(requestSpecifications, options)
+	if err != nil {
+		return
+	}
+
+	// handle status code, response
+	if statusCode == http.StatusOK {
+		dataResponse := accountEnvelope{}
+		err = client.decodeResponse(response, &dataResponse)
+		if err != nil {
+			err = fmt.Errorf("resource updated, but received invalid response. error: %w", err)
+			return
+		}
+		return dataResponse.account(), statusCode, nil
+	}
+	err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
+
+	return
+}
+
+// List - returns a page of accounts based on list params
+func (client *Client) List(ctx context.Context, listParams ListParams, options ...CallOption) (accountList *AccountList, err error) {
+	// filter by the organisation of the client
+	if client.organisationID != "" {
+		organisationID, err := client.resolveOrganisationID(listParams.Filter[organisationIDFilter])
+		if err != nil {
+			return nil, err
+		}
+		filter := map[string]string{organisationIDFilter: organisationID}
+		for name, value := range listParams.Filter {
+			if name != organisationIDFilter {
+				filter[name] = value
+			}
+		}
+		listParams.Filter = filter
+	}
+
+	// prepare request specifications
+	requestSpecifications := client.requestSpecifications(ctx, OperationList, http.MethodGet, client.accountsURL("", listParams.query()), nil)
+	defer wrapError(&err, requestSpecifications, time.Now())
+
+	// make request
+	statusCode, response, headers, err := client.do(requestSpecifications, options)
+	if err != nil {
+		return
+	}
+
+	// handle status code, response
+	if statusCode == http.StatusOK {
+		dataResponse := accountListEnvelope{}
+		err = client.decodeResponse(response, &dataResponse)
+		if err != nil {
+			err = fmt.Errorf("received invalid response. error: %w", err)
+			return
+		}
+		return &AccountList{Data: dataResponse.Data, Links: dataResponse.Links, TotalCount: dataResponse.totalCount(headers)}, nil
+	}
+	err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
+
+	return
+}
+
+// query - returns the list params as query values
+func (listParams ListParams) query() url.Values {
+	query := url.Values{}
+	if listParams.PageNumber > 0 || listParams.PageSize > 0 {
+		query.Set("page[number]", strconv.Itoa(listParams.PageNumber))
+	}
+	if listParams.PageSize > 0 {
+		query.Set("page[size]", strconv.Itoa(listParams.PageSize))
+	}
+	for name, value := range listParams.Filter {
+		query.Set("filter["+name+"]", value)
+	}
+	return query
+}
+
+// resolveOrganisationID - returns the organisation id of a call, defaulting to the organisation id of the client
+// An organisation id different from the one of the client is rejected
+func (client *Client) resolveOrganisationID(organisationID string) (string, error) {
+	switch {
+	case client.organisationID == "":
+		return organisationID, nil
+	case organisationID == "":
+		return client.organisationID, nil
+	case !strings.EqualFold(organisationID, client.organisationID):
+		return "", fmt.Errorf("invalid organisation id: %q does not match the organisation id %q of the client", organisationID, client.organisationID)
+	}
+	return organisationID, nil
+}
+
+// requestSpecifications - returns the specifications of a request with the retry count and headers of the client
+// The attempts of the request are recorded in the stats of the operation
+func (client *Client) requestSpecifications(ctx context.Context, operation, method, requestURL string, params []byte) *httprequest.RequestSpecifications {
+	return &httprequest.RequestSpecifications{
+		Context:            ctx,
+		Operation:          operation,
+		HTTPMethod:         method,
+		URL:                requestURL,
+		Params:             params,
+		RetryCount:         client.retryCount,
+		DisableRetries:     client.disableRetries,
+		RetryNonIdempotent: client.retryNonIdempotent,
+		RetryIf:            client.retryIf,
+		Headers:            client.headers,
+		Observer:           client.requestObserver,
+		OnAttempt: func(attempt httprequest.Attempt) {
+			client.recordAttempt(operation, attempt)
+		},
+	}
+}
+
+// do - makes a request with the call options applied, returning a *accounterrors.RetriesExhaustedError when every attempt
+// failed with a retryable outcome. Read only clients only send get requests, requests are paced by the throttle of the client
+// and counted as in flight for Shutdown. Requests rejected with 401 are sent once more with a fresh token of the token provider
+func (client *Client) do(specs *httprequest.RequestSpecifications, options []CallOption) (statusCode int, response []byte, headers http.Header, err error) {
+	if client.readOnly && specs.HTTPMethod != http.MethodGet {
+		return 0, nil, nil, accounterrors.ErrReadOnlyClient
+	}
+	if !client.lifecycle.enter(specs.Context) {
+		return 0, nil, nil, accounterrors.ErrClientShutdown
+	}
+	defer client.lifecycle.leave()
+	cancel := applyCallOptions(specs, options)
+	defer cancel()
+
+	// attempts holds every attempt of the call, sendAttempts the attempts of the last retry loop of the handler, which
+	// starts over when the request is resent after a 429 or 401
+	var attempts, sendAttempts []accounterrors.Attempt
+	onAttempt := specs.OnAttempt
+	specs.OnAttempt = func(attempt httprequest.Attempt) {
+		if attempt.Number == 1 {
+			sendAttempts = nil
+		}
+		attempts = append(attempts, accounterrors.Attempt{StatusCode: attempt.StatusCode, Err: attempt.Err})
+		sendAttempts = append(sendAttempts, accounterrors.Attempt{StatusCode: attempt.StatusCode, Err: attempt.Err})
+		if onAttempt != nil {
+			onAttempt(attempt)
+		}
+	}
+	start := time.Now()
+	defer func() {
+		client.recordRequest(specs.Operation, len(attempts), len(specs.Params), statusCode, len(response), err, time.Since(start))
+	}()
+
+	token, err := client.authorize(specs)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	statusCode, response, headers, err = client.send(specs)
+	// tokens may expire or be revoked while they are cached, requests rejected with 401 are sent once more with a fresh token
+	if statusCode == http.StatusUnauthorized && token != "" {
+		if err = client.refreshToken(specs, token); err != nil {
+			return 0, nil, nil, err
+		}
+		statusCode, response, headers, err = client.send(specs)
+	}
+	if len(sendAttempts) == 0 || len(sendAttempts) < specs.RetryCount {
+		return
+	}
+	last := sendAttempts[len(sendAttempts)-1]
+	if !specs.Retryable(last.StatusCode, last.Err) {
+		return
+	}
+	if err == nil {
+		err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
+	}
+	return statusCode, response, headers, &accounterrors.RetriesExhaustedError{Attempts: sendAttempts, Err: err}
+}
+
+// send - sends a request, requests answered with 429 were not processed, they are resent once the throttle lets them through
+// The Date header of every response is checked for clock skew, only responses of gets fail with the skew
+func (client *Client) send(specs *httprequest.RequestSpecifications) (statusCode int, response []byte, headers http.Header, err error) {
+	for throttled := 0; ; throttled++ {
+		if err = client.throttle.wait(specs.Context); err != nil {
+			return 0, nil, nil, err
+		}
+		var sent *httprequest.Response
+		sent, err = client.handler.Send(specs)
+		statusCode, response, headers = sent.StatusCode, sent.Body, sent.Headers
+		client.throttle.observe(statusCode, headers)
+		// mutations answered by the api were applied already, failing them would make callers repeat them
+		if skewErr := client.clockSkew.observe(headers); skewErr != nil && err == nil && safeMethod(specs.HTTPMethod) {
+			return statusCode, response, headers, skewErr
+		}
+		if statusCode != http.StatusTooManyRequests || specs.DisableRetries || throttled >= maxThrottledRetries {
+			return
+		}
+	}
+}
+
+// wrapError - wraps a non nil error with the operation, method and sanitized url of the request and the time elapsed since start
+// It is deferred with the named error result of an operation once the request is prepared
+func wrapError(err *error, specs *httprequest.RequestSpecifications, start time.Time) {
+	if *err == nil {
+		return
+	}
+	*err = &accounterrors.OperationError{
+		Operation: specs.Operation,
+		Method:    specs.HTTPMethod,
+		URL:       sanitizeURL(specs.URL),
+		Elapsed:   time.Since(start),
+		Err:       *err,
+	}
+}
+
+// sanitizeURL - removes user info and query values from a url
+func sanitizeURL(requestURL string) string {
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		return ""
+	}
+	parsed.User = nil
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	return parsed.String()
+}
+
+// accountsURL - returns the url of the accounts collection, or of a single account when an account id is given
+// Path parameters and query values are escaped
+func (client *Client) accountsURL(accountID string, query url.Values) string {
+	requestURL := client.apiURL(accountPath)
+	if accountID != "" {
+		requestURL += "/" + url.PathEscape(accountID)
+	}
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	return requestURL
+}
+
+// apiURL - returns the url of an api path, below the base url, prefix and version of the client
+func (client *Client) apiURL(path string) string {
+	return fmt.Sprintf("%s/%s/%s", client.baseURL, client.apiPath, path)
+}
+
+// apiPath - joins the prefix and the version of the api paths, an empty version uses the default version
+func apiPath(prefix, version string) string {
+	version = strings.Trim(version, "/")
+	if version == "" {
+		version = defaultAPIPathVersion
+	}
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		return prefix + "/" + version
+	}
+	return version
+}
+
+// validateID - checks that an id is a well formed uuid in the canonical hyphenated form
+func validateID(field, id string) error {
+	if !canonicalUUID(id) {
+		return &accounterrors.InvalidIDError{Field: field, Value: id}
+	}
+	return nil
+}
+
+// canonicalUUID - reports whether a value is a uuid in the canonical 36 character hyphenated form, ids are sent as
+// they are, so forms accepted by uuid.Parse like {...}, urn:uuid:... or 32 hex digits without hyphens are rejected
+func canonicalUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+// MarshalCreateRequest - returns the request body the client sends for creating an account
+func MarshalCreateRequest(createParams AccountCreateParams) ([]byte, error) {
+	dataMap := make(map[string]AccountCreateParams)
+	dataMap["data"] = createParams
+	return json.Marshal(dataMap)
+}
+
+// MarshalUpdateRequest - returns the request body the client sends for updating an account
+func MarshalUpdateRequest(updateParams AccountUpdateParams) ([]byte, error) {
+	return json.Marshal(map[string]AccountUpdateParams{"data": updateParams})
+}
+
+// DecodeAccount - decodes an account response body the same way the client does in its default lenient mode
+func DecodeAccount(response []byte) (*AccountData, error) {
+	dataResponse := accountEnvelope{}
+	if err := decodeJSON(response, &dataResponse, false); err != nil {
+		return nil, err
+	}
+	return dataResponse.account(), nil
+}
+
+// decodeResponse - decodes a json response, rejecting unknown fields when strict decoding is enabled
+// Responses with a schema are validated against it first when response validation is enabled
+func (client *Client) decodeResponse(response []byte, v interface{}) error {
+	if envelope, ok := v.(interface{ responseSchema() *schema.Schema }); ok && client.validateResponses {
+		if err := envelope.responseSchema().Validate(response); err != nil {
+			return err
+		}
+	}
+	return decodeJSON(response, v, client.strictDecoding)
+}
+
+// decodeJSON - decodes json into v, rejecting unknown fields in strict mode
+func decodeJSON(response []byte, v interface{}, strict bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(response))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	err := decoder.Decode(v)
+	if err != nil || !strict {
+		return err
+	}
+
+	// attributes decode leniently into Extra, so they are checked separately
+	if response, ok := v.(interface{ unknownFields() error }); ok {
+		return response.unknownFields()
+	}
+	return nil
+}
@@ -1,15 +1,23 @@
 package accountlib
 
 import (
+	"context"
 	"errors"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"accountlib/errors"
 	"accountlib/httprequest"
 )
 
@@ -17,6 +25,8 @@ var (
 	accountData = map[string][]byte{
 		"7eb322ba-57f6-465c-b600-79f26ac7fdc3": []byte(`{"data": {"id":"7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`),
 		"cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb": []byte(`{"data": {"id":"cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb"}`),
+		"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc": []byte(`{"data": {"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc","unknown_field":"value"}}`),
+		"5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a": []byte(`{"data": {"id":"5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a","attributes":{"unknown_attribute":"value"}}}`),
 	}
 )
 
@@ -42,20 +52,34 @@ func TestClientTestSuite(t *testing.T) {
 	suite.Run(t, new(ClientTestSuite))
 }
 
-// MakeRequest - function for mocking client MakeRequest
-func (r *requestHandlerMock) MakeRequest(specs *httprequest.RequestSpecifications) (statusCode int, body []byte, headers http.Header, err error) {
+// Send - function for mocking client Send
+func (r *requestHandlerMock) Send(specs *httprequest.RequestSpecifications) (*httprequest.Response, error) {
+	statusCode, body, headers, err := r.makeRequest(specs)
+	return &httprequest.Response{StatusCode: statusCode, Body: body, Headers: headers, Attempts: 1}, err
+}
+
+// makeRequest - dispatches mocked requests by http method
+func (r *requestHandlerMock) makeRequest(specs *httprequest.RequestSpecifications) (statusCode int, body []byte, headers http.Header, err error) {
 	if specs.HTTPMethod == http.MethodGet {
 		return r.handleGetRequests(specs.URL)
 	} else if specs.HTTPMethod == http.MethodPost {
 		return r.handlePostRequests(specs.Params)
 	} else if specs.HTTPMethod == http.MethodDelete {
 		return r.handleDeleteRequests(specs.URL)
+	} else if specs.HTTPMethod == http.MethodPatch {
+		return r.handlePatchRequests(specs.URL)
 	}
 	return 0, nil, nil, errors.New("invalid http method")
 }
 
 // handleGetRequests - helper for handling mocked GET requests
 func (r *requestHandlerMock) handleGetRequests(url string) (statusCode int, body []byte, headers http.Header, err error) {
+	if strings.HasSuffix(url, "/accounts") || strings.Contains(url, "/accounts?") {
+		if strings.Contains(url, "filter%5Bcountry%5D=XX") {
+			return http.StatusOK, []byte(`{"data": [`), nil, nil
+		}
+		return http.StatusOK, []byte(`{"data": [{"id":"7eb322ba-57f6-465c-b600-79f26ac7fdc3"}]}`), nil, nil
+	}
 	urlInfo := strings.Split(url, "/")
 	accountID := urlInfo[len(urlInfo)-1]
 	if responseBody, ok := accountData[accountID]; ok {
@@ -86,6 +110,16 @@ func (r *requestHandlerMock) handleDeleteRequests(url string) (statusCode int, b
 	return http.StatusNotFound, nil, nil, nil
 }
 
+// handlePatchRequests - helper for handling mocked PATCH requests
+func (r *requestHandlerMock) handlePatchRequests(url string) (statusCode int, body []byte, headers http.Header, err error) {
+	urlInfo := strings.Split(url, "/")
+	accountID := urlInfo[len(urlInfo)-1]
+	if responseBody, ok := accountData[accountID]; ok {
+		return http.StatusOK, responseBody, nil, nil
+	}
+	return http.StatusNotFound, nil, nil, nil
+}
+
 // TestNewClientWithOptions - tests account client object creation with options
 func TestNewClientWithOptions(t *testing.T) {
 	check := assert.New(t)
@@ -115,33 +149,95 @@ func TestNewClientWithOutOptions(t *testing.T) {
 	})
 }
 
+// TestNewClientDisableProxy - tests proxies of the environment are only ignored when disabled
+func TestNewClientDisableProxy(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(nil)
+	check.NotNil(client.httpClient.Transport.(*http.Transport).Proxy)
+
+	client = NewClient(&ClientOptions{DisableProxy: true})
+	check.Nil(client.httpClient.Transport.(*http.Transport).Proxy)
+}
+
+// TestAccountsURLEscaping - tests path parameters and query values are escaped
+func TestAccountsURLEscaping(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(nil)
+	check.Equal(client.accountsURL("", nil), "http://localhost:8080/v1/organisation/accounts")
+	check.Equal(client.accountsURL("a/b?c d", url.Values{"version": []string{"1&2"}}),
+		"http://localhost:8080/v1/organisation/accounts/a%2Fb%3Fc%20d?version=1%262")
+}
+
+// TestAccountsURLCustomBaseURL - tests the base url option
+func TestAccountsURLCustomBaseURL(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(&ClientOptions{BaseURL: "http://accounts.internal/"})
+	check.Equal(client.accountsURL("", nil), "http://accounts.internal/v1/organisation/accounts")
+}
+
+// TestAccountsURLAPIPath - tests the api prefix and path version options
+func TestAccountsURLAPIPath(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(&ClientOptions{BaseURL: "http://gateway.internal", APIPrefix: "/api/"})
+	check.Equal("http://gateway.internal/api/v1/organisation/accounts", client.accountsURL("", nil))
+	check.Equal("http://gateway.internal/api/v1/health", client.apiURL(healthPath))
+
+	client = NewClient(&ClientOptions{APIPathVersion: "v2-beta"})
+	check.Equal("http://localhost:8080/v2-beta/organisation/accounts", client.accountsURL("", nil))
+	check.Equal("http://localhost:8080/v2-beta/subscriptions", client.subscriptionsURL("", nil))
+
+	client = NewClient(&ClientOptions{APIPrefix: "proxy/accounts", APIPathVersion: "/v3/"})
+	check.Equal("http://localhost:8080/proxy/accounts/v3/events", client.apiURL(eventsPath))
+}
+
 // TestFetchAccountSuccessStatusCode - tests an account fetch with successful status code
 func (s *ClientTestSuite) TestFetchAccountSuccessStatusCode() {
 	check := assert.New(s.T())
 	accountID := "7eb322ba-57f6-465c-b600-79f26ac7fdc3"
 
 	// fetch account
-	accountData, _ := s.client.Fetch(accountID)
+	accountData, _ := s.client.Fetch(context.Background(), accountID)
 	check.Equal(accountData.ID, accountID)
 }
 
 // TestFetchAccountFailureStatusCode - tests an account fetch with failure status code
 func (s *ClientTestSuite) TestFetchAccountFailureStatusCode() {
 	check := assert.New(s.T())
-	incorrectAccountID := "57f6-465c"
+	unknownAccountID := "0d209d7f-d07a-4542-947f-5885fddddae2"
 
 	// fetch account
-	accountData, err := s.client.Fetch(incorrectAccountID)
+	accountData, err := s.client.Fetch(context.Background(), unknownAccountID)
 	check.Equal(accountData, (*AccountData)(nil))
 	check.Contains(err.Error(), "resource not found")
 }
 
+// TestFetchAccountMalformedAccount - tests an account fetch with an account id which is not a uuid
+func (s *ClientTestSuite) TestFetchAccountMalformedAccount() {
+	check := assert.New(s.T())
+
+	// fetch account
+	accountData, err := s.client.Fetch(context.Background(), "57f6-465c")
+	check.Equal(accountData, (*AccountData)(nil))
+	check.True(errors.Is(err, accounterrors.ErrInvalidID))
+	check.Contains(err.Error(), `invalid account id: "57f6-465c"`)
+
+	// forms of uuids other than the canonical hyphenated one are rejected, since ids are sent as they are
+	for _, accountID := range []string{
+		"{ad27e265-9605-4b4b-a0e5-3003ea9cc4dc}",
+		"urn:uuid:ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		"ad27e26596054b4ba0e53003ea9cc4dc",
+	} {
+		_, err = s.client.Fetch(context.Background(), accountID)
+		check.True(errors.Is(err, accounterrors.ErrInvalidID), accountID)
+	}
+}
+
 // TestFetchAccountEmptyAccount - tests an account fetch with empty account id
 func (s *ClientTestSuite) TestFetchAccountEmptyAccount() {
 	check := assert.New(s.T())
 
 	// fetch account
-	accountData, err := s.client.Fetch("")
+	accountData, err := s.client.Fetch(context.Background(), "")
 	check.Equal(accountData, (*AccountData)(nil))
 	check.Contains(err.Error(), "invalid account id")
 }
@@ -152,11 +248,77 @@ func (s *ClientTestSuite) TestFetchAccountInvalidResponse() {
 	accountID := "cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb"
 
 	// fetch account
-	accountData, err := s.client.Fetch(accountID)
+	accountData, err := s.client.Fetch(context.Background(), accountID)
 	check.Equal(accountData, (*AccountData)(nil))
 	check.Contains(err.Error(), "received invalid response")
 }
 
+// TestFetchAccountUnknownFieldLenient - tests an account fetch with unknown response fields in default mode
+func (s *ClientTestSuite) TestFetchAccountUnknownFieldLenient() {
+	check := assert.New(s.T())
+	accountID := "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+
+	// fetch account
+	accountData, err := s.client.Fetch(context.Background(), accountID)
+	check.Equal(err, nil)
+	check.Equal(accountData.ID, accountID)
+}
+
+// TestFetchAccountUnknownFieldStrict - tests an account fetch with unknown response fields in strict mode
+func (s *ClientTestSuite) TestFetchAccountUnknownFieldStrict() {
+	check := assert.New(s.T())
+	accountID := "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	client := NewClient(&ClientOptions{StrictDecoding: true})
+	client.handler = &requestHandlerMock{}
+
+	// fetch account
+	accountData, err := client.Fetch(context.Background(), accountID)
+	check.Equal(accountData, (*AccountData)(nil))
+	check.Contains(err.Error(), "unknown field")
+}
+
+// TestFetchAccountUnknownAttributeStrict - tests an account fetch with unknown attributes in strict mode
+func (s *ClientTestSuite) TestFetchAccountUnknownAttributeStrict() {
+	check := assert.New(s.T())
+	accountID := "5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a"
+	client := NewClient(&ClientOptions{StrictDecoding: true})
+	client.handler = &requestHandlerMock{}
+
+	// fetch account
+	accountData, err := client.Fetch(context.Background(), accountID)
+	check.Equal(accountData, (*AccountData)(nil))
+	check.Contains(err.Error(), "unknown_attribute")
+}
+
+// TestListAccountsSuccessStatusCode - tests an account list with successful status code
+func (s *ClientTestSuite) TestListAccountsSuccessStatusCode() {
+	check := assert.New(s.T())
+
+	// list accounts
+	accountList, err := s.client.List(context.Background(), ListParams{})
+	check.Equal(err, nil)
+	check.Len(accountList.Data, 1)
+	check.Equal(accountList.Data[0].ID, "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+}
+
+// TestListAccountsInvalidResponse - tests an account list with invalid response
+func (s *ClientTestSuite) TestListAccountsInvalidResponse() {
+	check := assert.New(s.T())
+
+	// list accounts
+	accountList, err := s.client.List(context.Background(), ListParams{Filter: map[string]string{"country": "XX"}})
+	check.Equal(accountList, (*AccountList)(nil))
+	check.Contains(err.Error(), "received invalid response")
+}
+
+// TestListParamsQuery - tests list params are converted into paging and filter query values
+func TestListParamsQuery(t *testing.T) {
+	check := assert.New(t)
+	check.Equal(ListParams{}.query().Encode(), "")
+	check.Equal(ListParams{PageNumber: 2, PageSize: 10, Filter: map[string]string{"country": "GB"}}.query().Encode(),
+		"filter%5Bcountry%5D=GB&page%5Bnumber%5D=2&page%5Bsize%5D=10")
+}
+
 // TestCreateAccountSuccessStatusCode - tests an account creation with successful status code
 func (s *ClientTestSuite) TestCreateAccountSuccessStatusCode() {
 	check := assert.New(s.T())
@@ -164,7 +326,7 @@ func (s *ClientTestSuite) TestCreateAccountSuccessStatusCode() {
 	orgID := "35eedc2c-0318-40dc-a090-d6f42e7b2754"
 
 	// create account
-	accountData, _ := s.client.Create(AccountCreateParams{
+	accountData, _ := s.client.Create(context.Background(), AccountCreateParams{
 		ID:             accountID,
 		OrganisationID: orgID,
 	})
@@ -174,11 +336,11 @@ func (s *ClientTestSuite) TestCreateAccountSuccessStatusCode() {
 // TestCreateAccountFailureStatusCode - tests an account creation with failure status code
 func (s *ClientTestSuite) TestCreateAccountFailureStatusCode() {
 	check := assert.New(s.T())
-	conflictAccountID := "57f6-465c"
+	conflictAccountID := "0d209d7f-d07a-4542-947f-5885fddddae2"
 	orgID := "35eedc2c-0318-40dc-a090-d6f42e7b2754"
 
 	// create account
-	accountData, err := s.client.Create(AccountCreateParams{
+	accountData, err := s.client.Create(context.Background(), AccountCreateParams{
 		ID:             conflictAccountID,
 		OrganisationID: orgID,
 	})
@@ -186,6 +348,20 @@ func (s *ClientTestSuite) TestCreateAccountFailureStatusCode() {
 	check.Contains(err.Error(), "request conflict")
 }
 
+// TestCreateAccountMalformedOrganisation - tests an account creation with an organisation id which is not a uuid
+func (s *ClientTestSuite) TestCreateAccountMalformedOrganisation() {
+	check := assert.New(s.T())
+
+	// create account
+	accountData, err := s.client.Create(context.Background(), AccountCreateParams{
+		ID:             "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		OrganisationID: "35eedc2c",
+	})
+	check.Equal(accountData, (*AccountData)(nil))
+	check.True(errors.Is(err, accounterrors.ErrInvalidID))
+	check.Contains(err.Error(), "invalid organisation id")
+}
+
 // TestCreateAccountInvalidResponse - tests an account creation with invalid response
 func (s *ClientTestSuite) TestCreateAccountInvalidResponse() {
 	check := assert.New(s.T())
@@ -193,7 +369,7 @@ func (s *ClientTestSuite) TestCreateAccountInvalidResponse() {
 	orgID := "35eedc2c-0318-40dc-a090-d6f42e7b2754"
 
 	// create account
-	accountData, err := s.client.Create(AccountCreateParams{
+	accountData, err := s.client.Create(context.Background(), AccountCreateParams{
 		ID:             accountID,
 		OrganisationID: orgID,
 	})
@@ -201,6 +377,21 @@ func (s *ClientTestSuite) TestCreateAccountInvalidResponse() {
 	check.Contains(err.Error(), "resource created, but received invalid response")
 }
 
+// TestCreateAccountValidateBeforeSend - tests create params are validated locally when enabled
+func (s *ClientTestSuite) TestCreateAccountValidateBeforeSend() {
+	check := assert.New(s.T())
+	client := NewClient(&ClientOptions{ValidateBeforeSend: true})
+	client.handler = &requestHandlerMock{}
+
+	// create account
+	accountData, err := client.Create(context.Background(), AccountCreateParams{
+		ID:             "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		OrganisationID: "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+	})
+	check.Equal(accountData, (*AccountData)(nil))
+	check.Contains(err.Error(), "invalid create params: attributes are required")
+}
+
 // TestDeleteAccountSuccessStatusCode - tests an account deletion with successful status code
 func (s *ClientTestSuite) TestDeleteAccountSuccessStatusCode() {
 	check := assert.New(s.T())
@@ -208,18 +399,18 @@ func (s *ClientTestSuite) TestDeleteAccountSuccessStatusCode() {
 	version := int64(0)
 
 	// delete account
-	err := s.client.Delete(accountID, &version)
+	err := s.client.Delete(context.Background(), accountID, &version)
 	check.Equal(err, nil)
 }
 
 // TestDeleteAccountFailureStatusCode - tests an account deletion with failure status code
 func (s *ClientTestSuite) TestDeleteAccountFailureStatusCode() {
 	check := assert.New(s.T())
-	incorrectAccountID := "57f6-465c"
+	unknownAccountID := "0d209d7f-d07a-4542-947f-5885fddddae2"
 	version := int64(0)
 
 	// delete account
-	err := s.client.Delete(incorrectAccountID, &version)
+	err := s.client.Delete(context.Background(), unknownAccountID, &version)
 	check.Contains(err.Error(), "resource not found")
 }
 
@@ -229,7 +420,7 @@ func (s *ClientTestSuite) TestDeleteAccountEmptyAccount() {
 	version := int64(0)
 
 	// delete account
-	err := s.client.Delete("", &version)
+	err := s.client.Delete(context.Background(), "", &version)
 	check.Contains(err.Error(), "invalid account id")
 }
 
@@ -239,6 +430,376 @@ func (s *ClientTestSuite) TestDeleteAccountNilVersion() {
 	accountID := "cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb"
 
 	// delete account
-	err := s.client.Delete(accountID, nil)
+	err := s.client.Delete(context.Background(), accountID, nil)
+	check.Contains(err.Error(), "invalid version")
+}
+
+// TestUpdateAccountSuccessStatusCode - tests an account update with successful status code
+func (s *ClientTestSuite) TestUpdateAccountSuccessStatusCode() {
+	check := assert.New(s.T())
+	accountID := "7eb322ba-57f6-465c-b600-79f26ac7fdc3"
+	version := int64(0)
+
+	// update account
+	accountData, err := s.client.Update(context.Background(), AccountUpdateParams{ID: accountID, Version: &version})
+	check.Equal(err, nil)
+	check.Equal(accountData.ID, accountID)
+}
+
+// TestUpdateAccountFailureStatusCode - tests an account update with failure status code
+func (s *ClientTestSuite) TestUpdateAccountFailureStatusCode() {
+	check := assert.New(s.T())
+	unknownAccountID := "0d209d7f-d07a-4542-947f-5885fddddae2"
+	version := int64(0)
+
+	// update account
+	_, err := s.client.Update(context.Background(), AccountUpdateParams{ID: unknownAccountID, Version: &version})
+	check.Contains(err.Error(), "resource not found")
+}
+
+// TestUpdateAccountInvalidResponse - tests an account update with invalid response
+func (s *ClientTestSuite) TestUpdateAccountInvalidResponse() {
+	check := assert.New(s.T())
+	accountID := "cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb"
+	version := int64(0)
+
+	// update account
+	_, err := s.client.Update(context.Background(), AccountUpdateParams{ID: accountID, Version: &version})
+	check.Contains(err.Error(), "resource updated, but received invalid response")
+}
+
+// TestUpdateAccountNilVersion - tests an account update with nil version
+func (s *ClientTestSuite) TestUpdateAccountNilVersion() {
+	check := assert.New(s.T())
+	accountID := "7eb322ba-57f6-465c-b600-79f26ac7fdc3"
+
+	// update account
+	_, err := s.client.Update(context.Background(), AccountUpdateParams{ID: accountID})
 	check.Contains(err.Error(), "invalid version")
 }
+
+// TestOperationError - tests request errors carry the operation, method, sanitized url and elapsed time
+func TestOperationError(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: strings.Replace(server.URL, "http://", "http://user:password@", 1)})
+
+	err := client.Delete(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", new(int64))
+	operationError := &accounterrors.OperationError{}
+	check.True(errors.As(err, &operationError))
+	check.Equal(OperationDelete, operationError.Operation)
+	check.Equal(http.MethodDelete, operationError.Method)
+	check.Equal(server.URL+"/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3", operationError.URL)
+	check.True(operationError.Elapsed > 0)
+	check.Equal("resource not found: ", operationError.Err.Error())
+	check.True(strings.HasPrefix(err.Error(), "delete DELETE "+operationError.URL+" failed after "))
+
+	// validation errors are returned before a request is made
+	err = client.Delete(context.Background(), "account", new(int64))
+	check.False(errors.As(err, &operationError))
+}
+
+// TestDeduplicateFetches - tests concurrent fetches of an account are collapsed into a single request
+func TestDeduplicateFetches(t *testing.T) {
+	check := assert.New(t)
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_, _ = w.Write(accountData["7eb322ba-57f6-465c-b600-79f26ac7fdc3"])
+	}))
+	defer server.Close()
+
+	fetchConcurrently := func(client *Client, options ...CallOption) {
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		accounts := make(map[*AccountData]bool)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				account, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", options...)
+				check.Nil(err)
+				check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", account.ID)
+				// every caller gets its own copy, modifying it does not affect the others
+				account.Attributes = nil
+				mutex.Lock()
+				accounts[account] = true
+				mutex.Unlock()
+			}()
+		}
+		// give every fetch the time to join the request before it completes
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		check.Len(accounts, 5)
+		release = make(chan struct{})
+	}
+
+	fetchConcurrently(NewClient(&ClientOptions{BaseURL: server.URL, DeduplicateFetches: true}))
+	check.Equal(int32(1), atomic.SwapInt32(&requests, 0))
+
+	// fetches with call options and clients without the option are not deduplicated
+	fetchConcurrently(NewClient(&ClientOptions{BaseURL: server.URL, DeduplicateFetches: true}), WithRetries(0))
+	check.Equal(int32(5), atomic.SwapInt32(&requests, 0))
+	fetchConcurrently(NewClient(&ClientOptions{BaseURL: server.URL}))
+	check.Equal(int32(5), atomic.SwapInt32(&requests, 0))
+}
+
+// TestDeduplicateFetchesContext - tests a waiting fetch returns when its context is done
+func TestDeduplicateFetchesContext(t *testing.T) {
+	check := assert.New(t)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write(accountData["7eb322ba-57f6-465c-b600-79f26ac7fdc3"])
+	}))
+	defer server.Close()
+	defer close(release)
+	client := NewClient(&ClientOptions{BaseURL: server.URL, DeduplicateFetches: true})
+
+	go func() {
+		_, _ = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	account, err := client.Fetch(ctx, "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(account)
+	check.True(errors.Is(err, context.DeadlineExceeded))
+}
+
+// TestDeduplicateFetchesCancelled - tests a fetch joined by other callers keeps running when its first caller gives up
+func TestDeduplicateFetchesCancelled(t *testing.T) {
+	check := assert.New(t)
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		_, _ = w.Write(accountData["7eb322ba-57f6-465c-b600-79f26ac7fdc3"])
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, DeduplicateFetches: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	first := make(chan error)
+	go func() {
+		_, err := client.Fetch(ctx, "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+		first <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	second := make(chan *AccountData)
+	go func() {
+		account, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+		check.Nil(err)
+		second <- account
+	}()
+
+	check.True(errors.Is(<-first, context.DeadlineExceeded))
+	close(release)
+	account := <-second
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", account.ID)
+	check.Equal(int32(1), atomic.LoadInt32(&requests))
+	check.Equal(int64(1), client.Stats().Operations[OperationFetch].CacheHits)
+}
+
+// TestDeduplicateFetchesAbandoned - tests a fetch is cancelled once every caller gave up and later fetches start afresh
+func TestDeduplicateFetchesAbandoned(t *testing.T) {
+	check := assert.New(t)
+	cancelled := make(chan struct{})
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			<-r.Context().Done()
+			close(cancelled)
+			return
+		}
+		_, _ = w.Write(accountData["7eb322ba-57f6-465c-b600-79f26ac7fdc3"])
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, DeduplicateFetches: true, DisableRetries: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.Fetch(ctx, "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.True(errors.Is(err, context.DeadlineExceeded))
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		check.Fail("abandoned fetch was not cancelled")
+	}
+	account, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", account.ID)
+}
+
+// TestDefaultOrganisationID - tests the organisation id of the client is used by create and list
+func TestDefaultOrganisationID(t *testing.T) {
+	check := assert.New(t)
+	organisationID := "35eedc2c-0318-40dc-a090-d6f42e7b2754"
+	var body string
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		if r.Method == http.MethodPost {
+			data, _ := ioutil.ReadAll(r.Body)
+			body = string(data)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(data)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, OrganisationID: organisationID})
+
+	// create
+	accountData, err := client.Create(context.Background(), AccountCreateParams{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3"})
+	check.Nil(err)
+	check.Equal(organisationID, accountData.OrganisationID)
+	check.Contains(body, `"organisation_id":"`+organisationID+`"`)
+	_, err = client.Create(context.Background(), AccountCreateParams{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3", OrganisationID: strings.ToUpper(organisationID)})
+	check.Nil(err)
+	_, err = client.Create(context.Background(), AccountCreateParams{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3", OrganisationID: "cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb"})
+	check.EqualError(err, `invalid organisation id: "cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb" does not match the organisation id "35eedc2c-0318-40dc-a090-d6f42e7b2754" of the client`)
+
+	// list
+	filter := map[string]string{"country": "GB"}
+	_, err = client.List(context.Background(), ListParams{Filter: filter})
+	check.Nil(err)
+	check.Equal(organisationID, query.Get("filter[organisation_id]"))
+	check.Equal("GB", query.Get("filter[country]"))
+	check.Equal(map[string]string{"country": "GB"}, filter)
+	_, err = client.List(context.Background(), ListParams{Filter: map[string]string{"organisation_id": "cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb"}})
+	check.NotNil(err)
+
+	// clients without an organisation id leave the params alone
+	_, err = NewClient(&ClientOptions{BaseURL: server.URL}).List(context.Background(), ListParams{})
+	check.Nil(err)
+	check.Empty(query.Get("filter[organisation_id]"))
+}
+
+// TestReadOnlyClient - tests read only clients reject changes without sending them
+func TestReadOnlyClient(t *testing.T) {
+	check := assert.New(t)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write(accountData["7eb322ba-57f6-465c-b600-79f26ac7fdc3"])
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, ReadOnly: true})
+	accountID := "7eb322ba-57f6-465c-b600-79f26ac7fdc3"
+	organisationID := "35eedc2c-0318-40dc-a090-d6f42e7b2754"
+	version := int64(0)
+
+	_, err := client.Create(context.Background(), AccountCreateParams{ID: accountID, OrganisationID: organisationID})
+	check.True(errors.Is(err, accounterrors.ErrReadOnlyClient))
+	_, err = client.Update(context.Background(), AccountUpdateParams{ID: accountID, Version: &version})
+	check.True(errors.Is(err, accounterrors.ErrReadOnlyClient))
+	err = client.Delete(context.Background(), accountID, &version)
+	check.True(errors.Is(err, accounterrors.ErrReadOnlyClient))
+	err = client.DeleteSubscription(context.Background(), accountID, &version)
+	check.True(errors.Is(err, accounterrors.ErrReadOnlyClient))
+	check.Equal(int32(0), atomic.LoadInt32(&requests))
+
+	account, err := client.Fetch(context.Background(), accountID)
+	check.Nil(err)
+	check.Equal(accountID, account.ID)
+	check.Equal(int32(1), atomic.LoadInt32(&requests))
+}
+
+// TestListTotalCount - tests the total count is read from the meta object or the X-Total-Count header
+func TestListTotalCount(t *testing.T) {
+	check := assert.New(t)
+	var body, header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header != "" {
+			w.Header().Set("X-Total-Count", header)
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, StrictDecoding: true})
+
+	for _, test := range []struct {
+		body, header string
+		totalCount   *int64
+	}{
+		{body: `{"data": [], "meta": {"total": 42}}`, header: "7", totalCount: int64Pointer(42)},
+		{body: `{"data": [], "meta": {"count": 41}}`, totalCount: int64Pointer(41)},
+		{body: `{"data": []}`, header: "7", totalCount: int64Pointer(7)},
+		{body: `{"data": []}`, header: "many"},
+		{body: `{"data": []}`},
+	} {
+		body, header = test.body, test.header
+		accountList, err := client.List(context.Background(), ListParams{})
+		check.Nil(err)
+		check.Equal(test.totalCount, accountList.TotalCount, test.body)
+	}
+}
+
+// operationObserver - request observer recording the operations of ended requests
+type operationObserver struct {
+	operations []string
+}
+
+// OnRequestStart - returns the context unchanged
+func (observer *operationObserver) OnRequestStart(ctx context.Context, request httprequest.RequestInfo) context.Context {
+	return ctx
+}
+
+// OnAttempt - ignores the attempt
+func (observer *operationObserver) OnAttempt(context.Context, httprequest.RequestInfo, httprequest.Attempt) {
+}
+
+// OnRequestEnd - records the operation and status code of the request
+func (observer *operationObserver) OnRequestEnd(ctx context.Context, request httprequest.RequestInfo, result httprequest.RequestResult) {
+	observer.operations = append(observer.operations, request.Operation+" "+strconv.Itoa(result.StatusCode))
+}
+
+// TestRequestObserver - tests the request observer of the client is notified of every request
+func TestRequestObserver(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`))
+	}))
+	defer server.Close()
+	observer := &operationObserver{}
+	client := NewClient(&ClientOptions{BaseURL: server.URL, RequestObserver: observer})
+
+	_, err := client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Nil(err)
+	check.Nil(client.Delete(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", new(int64)))
+	check.Equal([]string{"fetch 200", "delete 204"}, observer.operations)
+}
+
+// int64Pointer - returns a pointer to the value
+func int64Pointer(value int64) *int64 {
+	return &value
+}
+
+// TestClientErrorMessages - tests the error messages of a client do not affect other clients
+func TestClientErrorMessages(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnavailableForLegalReasons)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, ErrorMessages: map[int]string{http.StatusUnavailableForLegalReasons: "blocked in this region"}})
+	otherClient := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	_, err := client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Contains(err.Error(), "blocked in this region")
+	_, err = otherClient.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Contains(err.Error(), "internal error")
+}
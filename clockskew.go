@@ -0,0 +1,104 @@
+package accountlib
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"accountlib/errors"
+)
+
+// dateHeader - header carrying the clock of the api
+const dateHeader = "Date"
+
+// defaultClockSkewThreshold - skew reported to OnClockSkew and the metrics unless ClockSkewThreshold is set
+const defaultClockSkewThreshold = 30 * time.Second
+
+// ClockSkewMetrics - optionally implemented by Metrics, ObserveClockSkew is called with the skew measured from every
+// response exceeding the clock skew threshold or the maximum clock skew, a positive skew means the clock of the api is
+// ahead of the local clock
+type ClockSkewMetrics interface {
+	ObserveClockSkew(skew time.Duration)
+}
+
+// clockSkew - measures the skew between the local clock and the Date headers of the api
+// Skew beyond the threshold or max is reported, skew beyond max fails gets when max is set
+type clockSkew struct {
+	mutex     sync.Mutex
+	threshold time.Duration
+	max       time.Duration
+	onSkew    func(skew time.Duration)
+	metrics   ClockSkewMetrics
+	skew      time.Duration
+	measured  bool
+	now       func() time.Time
+}
+
+// newClockSkew - returns a clock skew tracker, a zero threshold uses the default threshold
+func newClockSkew(threshold time.Duration, max time.Duration, onSkew func(skew time.Duration), metrics Metrics) *clockSkew {
+	if threshold <= 0 {
+		threshold = defaultClockSkewThreshold
+	}
+	clock := &clockSkew{threshold: threshold, max: max, onSkew: onSkew, now: time.Now}
+	clock.metrics, _ = metrics.(ClockSkewMetrics)
+	return clock
+}
+
+// observe - measures the skew of the Date header of a response, returning a *accounterrors.ClockSkewError when it
+// exceeds max. Skew beyond the threshold or max is reported, max is checked independently of the threshold
+// Responses without a valid Date header are ignored. The Date header has a resolution of a second, so skews below
+// a second are not told apart from the time the response took
+func (clock *clockSkew) observe(headers http.Header) error {
+	date, err := http.ParseTime(headers.Get(dateHeader))
+	if err != nil {
+		return nil
+	}
+	skew := date.Sub(clock.now()).Truncate(time.Second)
+	clock.mutex.Lock()
+	clock.skew, clock.measured = skew, true
+	clock.mutex.Unlock()
+
+	exceeded := clock.max > 0 && absDuration(skew) > clock.max
+	if !exceeded && absDuration(skew) <= clock.threshold {
+		return nil
+	}
+	if clock.onSkew != nil {
+		clock.onSkew(skew)
+	}
+	if clock.metrics != nil {
+		clock.metrics.ObserveClockSkew(skew)
+	}
+	if exceeded {
+		return &accounterrors.ClockSkewError{Skew: skew, Max: clock.max}
+	}
+	return nil
+}
+
+// last - returns the skew measured from the last response carrying a Date header
+func (clock *clockSkew) last() (time.Duration, bool) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.skew, clock.measured
+}
+
+// ClockSkew - returns the skew between the clock of the api and the local clock measured from the Date header of the
+// last response, a positive skew means the clock of the api is ahead. It reports false before any response was measured
+func (client *Client) ClockSkew() (time.Duration, bool) {
+	if client.clockSkew == nil {
+		return 0, false
+	}
+	return client.clockSkew.last()
+}
+
+// safeMethod - reports whether a request of the method does not change data, so failing its response is harmless
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// absDuration - returns the absolute value of a duration
+func absDuration(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+	return duration
+}
@@ -0,0 +1,114 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/errors"
+)
+
+// skewRecorder - records the clock skew passed to the metrics
+type skewRecorder struct {
+	metricsRecorder
+	skews []time.Duration
+}
+
+// ObserveClockSkew - records a clock skew
+func (recorder *skewRecorder) ObserveClockSkew(skew time.Duration) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.skews = append(recorder.skews, skew)
+}
+
+// TestClockSkew - tests the skew of Date headers is measured, reported beyond the threshold and fails requests beyond the maximum
+func TestClockSkew(t *testing.T) {
+	check := assert.New(t)
+	skew := 10 * time.Second
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	metrics := &skewRecorder{}
+	var warnings []time.Duration
+	client := NewClient(&ClientOptions{
+		BaseURL:      server.URL,
+		Metrics:      metrics,
+		MaxClockSkew: 5 * time.Minute,
+		OnClockSkew:  func(skew time.Duration) { warnings = append(warnings, skew) },
+	})
+	_, measured := client.ClockSkew()
+	check.False(measured)
+
+	_, err := client.List(context.Background(), ListParams{})
+	check.Nil(err)
+	measuredSkew, measured := client.ClockSkew()
+	check.True(measured)
+	check.InDelta(float64(skew), float64(measuredSkew), float64(2*time.Second))
+	check.Empty(warnings)
+
+	skew = -2 * time.Minute
+	_, err = client.List(context.Background(), ListParams{})
+	check.Nil(err)
+	check.Len(warnings, 1)
+	check.Equal(warnings, metrics.skews)
+	check.True(warnings[0] < -time.Minute)
+
+	skew = time.Hour
+	_, err = client.List(context.Background(), ListParams{})
+	check.True(errors.Is(err, accounterrors.ErrClockSkew))
+	check.False(accounterrors.IsRetryable(err))
+	var skewError *accounterrors.ClockSkewError
+	check.True(errors.As(err, &skewError))
+	check.Equal(5*time.Minute, skewError.Max)
+	check.Len(warnings, 2)
+}
+
+// TestClockSkewBelowThreshold - tests a maximum skew below the reporting threshold fails gets but not applied mutations
+func TestClockSkewBelowThreshold(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(20*time.Second).UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+	var warnings []time.Duration
+	client := NewClient(&ClientOptions{
+		BaseURL:      server.URL,
+		MaxClockSkew: 10 * time.Second,
+		OnClockSkew:  func(skew time.Duration) { warnings = append(warnings, skew) },
+	})
+
+	_, err := client.List(context.Background(), ListParams{})
+	check.True(errors.Is(err, accounterrors.ErrClockSkew))
+
+	account, err := client.Create(context.Background(), AccountCreateParams{
+		ID:             "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		Type:           "accounts",
+	})
+	check.Nil(err)
+	check.Equal("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", account.ID)
+	check.Len(warnings, 2)
+}
+
+// TestClockSkewWithoutDate - tests responses without a valid Date header are not measured
+func TestClockSkewWithoutDate(t *testing.T) {
+	check := assert.New(t)
+	clock := newClockSkew(0, time.Second, nil, nil)
+	check.Nil(clock.observe(http.Header{"Date": []string{"yesterday"}}))
+	_, measured := clock.last()
+	check.False(measured)
+	check.Equal(defaultClockSkewThreshold, clock.threshold)
+}
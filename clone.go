@@ -0,0 +1,144 @@
+package accountlib
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Clone - returns a deep copy of the account data
+func (data *AccountData) Clone() *AccountData {
+	if data == nil {
+		return nil
+	}
+	clone := *data
+	clone.Attributes = data.Attributes.Clone()
+	clone.Version = cloneInt64(data.Version)
+	clone.Relationships = cloneRelationships(data.Relationships)
+	clone.Links = data.Links.Clone()
+	clone.CreatedOn = cloneTime(data.CreatedOn)
+	clone.ModifiedOn = cloneTime(data.ModifiedOn)
+	clone.unknown = cloneStrings(data.unknown)
+	return &clone
+}
+
+// Clone - returns a deep copy of the account attributes
+func (attributes *AccountAttributes) Clone() *AccountAttributes {
+	if attributes == nil {
+		return nil
+	}
+	clone := *attributes
+	clone.AccountClassification = cloneClassification(attributes.AccountClassification)
+	clone.AccountMatchingOptOut = cloneBool(attributes.AccountMatchingOptOut)
+	clone.AlternativeNames = cloneStrings(attributes.AlternativeNames)
+	clone.Country = cloneCountry(attributes.Country)
+	clone.JointAccount = cloneBool(attributes.JointAccount)
+	clone.Name = cloneStrings(attributes.Name)
+	clone.Status = cloneStatus(attributes.Status)
+	clone.Switched = cloneBool(attributes.Switched)
+	clone.Extra = cloneRawMessages(attributes.Extra)
+	return &clone
+}
+
+// Clone - returns a deep copy of the account create params
+func (params *AccountCreateParams) Clone() *AccountCreateParams {
+	if params == nil {
+		return nil
+	}
+	clone := *params
+	clone.Attributes = params.Attributes.Clone()
+	clone.Relationships = params.Relationships.Clone()
+	return &clone
+}
+
+// Clone - returns a deep copy of the account create attributes
+func (attributes *AccountCreateAttributes) Clone() *AccountCreateAttributes {
+	if attributes == nil {
+		return nil
+	}
+	clone := *attributes
+	clone.AccountClassification = cloneClassification(attributes.AccountClassification)
+	clone.AccountMatchingOptOut = cloneBool(attributes.AccountMatchingOptOut)
+	clone.AlternativeNames = cloneStrings(attributes.AlternativeNames)
+	clone.Country = cloneCountry(attributes.Country)
+	clone.JointAccount = cloneBool(attributes.JointAccount)
+	clone.Name = cloneStrings(attributes.Name)
+	clone.Switched = cloneBool(attributes.Switched)
+	clone.Extra = cloneRawMessages(attributes.Extra)
+	return &clone
+}
+
+// cloneClassification - copies an account classification pointer
+func cloneClassification(value *AccountClassification) *AccountClassification {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// cloneCountry - copies a country code pointer
+func cloneCountry(value *CountryCode) *CountryCode {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// cloneStatus - copies an account status pointer
+func cloneStatus(value *AccountStatus) *AccountStatus {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// cloneBool - copies a bool pointer
+func cloneBool(value *bool) *bool {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// cloneInt64 - copies an int64 pointer
+func cloneInt64(value *int64) *int64 {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// cloneTime - copies a time pointer
+func cloneTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// cloneStrings - copies a string slice
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	clone := make([]string, len(values))
+	copy(clone, values)
+	return clone
+}
+
+// cloneRawMessages - copies a map of raw json values
+func cloneRawMessages(values map[string]json.RawMessage) map[string]json.RawMessage {
+	if values == nil {
+		return nil
+	}
+	clone := make(map[string]json.RawMessage, len(values))
+	for key, value := range values {
+		clone[key] = append(json.RawMessage(nil), value...)
+	}
+	return clone
+}
@@ -0,0 +1,67 @@
+package accountlib
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAccountDataClone - tests a cloned account shares no pointers or slices with the original
+func TestAccountDataClone(t *testing.T) {
+	check := assert.New(t)
+	country := CountryCode("GB")
+	version := int64(1)
+	original := &AccountData{
+		ID:      "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		Version: &version,
+		Attributes: &AccountAttributes{
+			Country: &country,
+			Name:    []string{"Samantha Holder"},
+			Extra:   map[string]json.RawMessage{"new_field": json.RawMessage(`"value"`)},
+		},
+	}
+
+	clone := original.Clone()
+	check.Equal(clone, original)
+
+	// mutate the clone
+	*clone.Version = 2
+	*clone.Attributes.Country = "FR"
+	clone.Attributes.Name[0] = "Sam Holder"
+	clone.Attributes.Extra["new_field"][1] = 'X'
+	check.Equal(*original.Version, int64(1))
+	check.Equal(*original.Attributes.Country, CountryCode("GB"))
+	check.Equal(original.Attributes.Name[0], "Samantha Holder")
+	check.Equal(string(original.Attributes.Extra["new_field"]), `"value"`)
+}
+
+// TestAccountCreateParamsClone - tests a cloned create params shares no pointers or slices with the original
+func TestAccountCreateParamsClone(t *testing.T) {
+	check := assert.New(t)
+	switched := true
+	original := &AccountCreateParams{
+		ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		Attributes: &AccountCreateAttributes{
+			Switched:         &switched,
+			AlternativeNames: []string{"Sam Holder"},
+		},
+	}
+
+	clone := original.Clone()
+	check.Equal(clone, original)
+
+	// mutate the clone
+	*clone.Attributes.Switched = false
+	clone.Attributes.AlternativeNames[0] = "Samantha"
+	check.Equal(*original.Attributes.Switched, true)
+	check.Equal(original.Attributes.AlternativeNames[0], "Sam Holder")
+}
+
+// TestCloneNil - tests cloning nil values
+func TestCloneNil(t *testing.T) {
+	check := assert.New(t)
+	check.Nil((*AccountData)(nil).Clone())
+	check.Nil((*AccountCreateParams)(nil).Clone())
+	check.Nil((&AccountData{}).Clone().Attributes)
+}
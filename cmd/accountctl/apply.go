@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"accountlib/reconcile"
+)
+
+// accountsFile - file holding the desired accounts, accounts take the organisation id of the file unless they set one
+type accountsFile struct {
+	OrganisationID string                  `json:"organisation_id"`
+	Accounts       []reconcile.AccountSpec `json:"accounts"`
+}
+
+// runApply - converges the accounts of the organisations in a file to the accounts defined in it
+// Accounts missing remotely are created, differing ones updated and remote accounts missing from the file deleted
+func runApply(env *environment, args []string) error {
+	flags, connection := newFlagSet(env, "apply")
+	file := flags.String("f", "", "yaml or json file with the desired accounts, - reads stdin")
+	dryRun := flags.Bool("dry-run", false, "print the plan without applying it")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *file == "" || flags.NArg() != 0 {
+		return usageError("expected -f <file> and no arguments")
+	}
+
+	desired, err := readAccountsFile(env, *file)
+	if err != nil {
+		return err
+	}
+	client, err := connection.client()
+	if err != nil {
+		return err
+	}
+
+	// the plan is printed before anything is applied
+	actions, err := reconcile.Plan(context.Background(), client, desired, reconcile.Options{})
+	if err != nil {
+		return err
+	}
+	for _, action := range actions {
+		writeAction(env.stdout, action)
+	}
+	fmt.Fprintf(env.stdout, "plan: %d to create, %d to update, %d to delete\n",
+		countActions(actions, reconcile.ActionCreate), countActions(actions, reconcile.ActionUpdate), countActions(actions, reconcile.ActionDelete))
+	if *dryRun {
+		return nil
+	}
+
+	result, err := reconcile.Apply(context.Background(), client, actions, reconcile.Options{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "applied %d changes\n", len(result.Applied))
+	return nil
+}
+
+// readAccountsFile - reads the desired accounts from a yaml or json file, or from stdin when the file is -
+func readAccountsFile(env *environment, file string) ([]reconcile.AccountSpec, error) {
+	var reader io.Reader = env.stdin
+	if file != "-" {
+		opened, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer opened.Close()
+		reader = opened
+	}
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s. error: %s", file, err.Error())
+	}
+
+	// yaml is a superset of json, the document is converted to json so the json field names of the library apply
+	var document interface{}
+	if err = yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("invalid accounts in %s. error: %s", file, err.Error())
+	}
+	converted, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid accounts in %s. error: %s", file, err.Error())
+	}
+	accounts := accountsFile{}
+	if err = json.Unmarshal(converted, &accounts); err != nil {
+		return nil, fmt.Errorf("invalid accounts in %s. error: %s", file, err.Error())
+	}
+
+	for i := range accounts.Accounts {
+		if accounts.Accounts[i].OrganisationID == "" {
+			accounts.Accounts[i].OrganisationID = accounts.OrganisationID
+		}
+	}
+	return accounts.Accounts, nil
+}
+
+// writeAction - writes an action of the plan, updates list the changed fields
+func writeAction(w io.Writer, action reconcile.Action) {
+	symbols := map[reconcile.ActionKind]string{reconcile.ActionCreate: "+", reconcile.ActionUpdate: "~", reconcile.ActionDelete: "-"}
+	fmt.Fprintf(w, "%s %s %s\n", symbols[action.Kind], action.Kind, action.AccountID)
+	for _, change := range action.Changes {
+		fmt.Fprintf(w, "    %s: %s -> %s\n", change.Path, formatValue(change.Old), formatValue(change.New))
+	}
+}
+
+// formatValue - formats a changed value for the plan
+func formatValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "(unset)"
+	case json.RawMessage:
+		return string(typed)
+	case []string:
+		return "[" + strings.Join(typed, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// countActions - returns the number of actions of a kind
+func countActions(actions []reconcile.Action, kind reconcile.ActionKind) int {
+	count := 0
+	for _, action := range actions {
+		if action.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/accountlibtest"
+	"accountlib/fakeserver"
+)
+
+// account ids used by the apply tests
+const (
+	unchangedAccountID = "7eb322ba-57f6-465c-b600-79f26ac7fdc3"
+	changedAccountID   = "5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a"
+	removedAccountID   = "0d209d7f-d07a-4542-947f-5885fddddae2"
+	addedAccountID     = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+// desiredAccounts - accounts file keeping one account, changing the bic of one and adding one
+const desiredAccounts = `
+organisation_id: eb0bd6f5-c3f5-44b2-b677-acd23cdde73c
+accounts:
+  - id: 7eb322ba-57f6-465c-b600-79f26ac7fdc3
+    type: accounts
+    attributes:
+      country: GB
+      base_currency: GBP
+      bank_id: "400300"
+      bank_id_code: GBDSC
+      bic: NWBKGB22
+      name: [Samantha Holder]
+      alternative_names: [Sam Holder]
+  - id: 5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a
+    type: accounts
+    attributes:
+      country: GB
+      base_currency: GBP
+      bank_id: "400300"
+      bank_id_code: GBDSC
+      bic: BARCGB22
+      name: [Samantha Holder]
+      alternative_names: [Sam Holder]
+  - id: ad27e265-9605-4b4b-a0e5-3003ea9cc4dc
+    attributes:
+      country: FR
+`
+
+// applyServer - returns a fake server holding the unchanged, changed and removed accounts
+func applyServer() *fakeserver.Server {
+	server := fakeserver.New()
+	for _, id := range []string{unchangedAccountID, changedAccountID, removedAccountID} {
+		server.AddAccount(accountlibtest.ValidGBAccount().WithID(id).AccountData())
+	}
+	return server
+}
+
+// TestApplyDryRun - tests the plan is printed without changing the remote accounts
+func TestApplyDryRun(t *testing.T) {
+	check := assert.New(t)
+	server := applyServer()
+	defer server.Close()
+
+	env, stdout, stderr := testEnvironment(desiredAccounts, map[string]string{accountlib.EnvBaseURL: server.URL})
+	check.Equal(exitOK, run([]string{"apply", "-f", "-", "--dry-run"}, env), stderr.String())
+	check.Equal("+ create "+addedAccountID+"\n"+
+		"~ update "+changedAccountID+"\n"+
+		"    attributes.bic: NWBKGB22 -> BARCGB22\n"+
+		"- delete "+removedAccountID+"\n"+
+		"plan: 1 to create, 1 to update, 1 to delete\n", stdout.String())
+	check.Len(server.Accounts(), 3)
+}
+
+// TestApplyConverges - tests applying creates, updates and deletes accounts and a second apply has nothing to do
+func TestApplyConverges(t *testing.T) {
+	check := assert.New(t)
+	server := applyServer()
+	defer server.Close()
+
+	env, stdout, stderr := testEnvironment(desiredAccounts, map[string]string{accountlib.EnvBaseURL: server.URL})
+	check.Equal(exitOK, run([]string{"apply", "-f", "-"}, env), stderr.String())
+	check.Contains(stdout.String(), "applied 3 changes\n")
+
+	accounts := server.Accounts()
+	check.Len(accounts, 3)
+	check.Equal([]string{changedAccountID, unchangedAccountID, addedAccountID}, []string{accounts[0].ID, accounts[1].ID, accounts[2].ID})
+	check.Equal("BARCGB22", accounts[0].Attributes.Bic)
+	check.Equal(int64(1), *accounts[0].Version)
+	check.Equal(accountlibtest.FixtureOrganisationID, accounts[2].OrganisationID)
+
+	env, stdout, _ = testEnvironment(desiredAccounts, map[string]string{accountlib.EnvBaseURL: server.URL})
+	check.Equal(exitOK, run([]string{"apply", "-f", "-"}, env))
+	check.Equal("plan: 0 to create, 0 to update, 0 to delete\napplied 0 changes\n", stdout.String())
+}
+
+// TestApplyInvalidFile - tests invalid account files are rejected before any request is sent
+func TestApplyInvalidFile(t *testing.T) {
+	check := assert.New(t)
+	for content, message := range map[string]string{
+		"accounts: [":                          "invalid accounts",
+		"accounts:\n  - id: " + addedAccountID: "needs an id and an organisation id",
+		"organisation_id: " + accountlibtest.FixtureOrganisationID + "\naccounts:\n  - id: " + addedAccountID + "\n  - id: " + addedAccountID: "defined twice",
+	} {
+		env, _, stderr := testEnvironment(content, map[string]string{accountlib.EnvBaseURL: "http://127.0.0.1:1"})
+		check.Equal(exitError, run([]string{"apply", "-f", "-"}, env))
+		check.Contains(stderr.String(), message)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"accountlib"
+)
+
+// runCreate - creates an account from a json file holding the create params, with or without the data envelope
+func runCreate(env *environment, args []string) error {
+	flags, connection := newFlagSet(env, "create")
+	output := outputFlag(flags)
+	file := flags.String("f", "", "json file with the account to create, - reads stdin")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *file == "" || flags.NArg() != 0 {
+		return usageError("expected -f <file> and no arguments")
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	createParams, err := readCreateParams(env, *file)
+	if err != nil {
+		return err
+	}
+	client, err := connection.client()
+	if err != nil {
+		return err
+	}
+	accountData, err := client.Create(context.Background(), createParams)
+	if err != nil {
+		return err
+	}
+	return writeAccounts(env.stdout, *output, connection.recorder.lastBody(), accountData)
+}
+
+// runFetch - fetches an account by id
+func runFetch(env *environment, args []string) error {
+	flags, connection := newFlagSet(env, "fetch")
+	output := outputFlag(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return usageError("expected exactly one account id")
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	client, err := connection.client()
+	if err != nil {
+		return err
+	}
+	accountData, err := client.Fetch(context.Background(), flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return writeAccounts(env.stdout, *output, connection.recorder.lastBody(), accountData)
+}
+
+// runDelete - deletes an account by id and version
+func runDelete(env *environment, args []string) error {
+	flags, connection := newFlagSet(env, "delete")
+	version := flags.Int64("version", -1, "version of the account to delete")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return usageError("expected exactly one account id")
+	}
+	if *version < 0 {
+		return usageError("expected --version")
+	}
+
+	client, err := connection.client()
+	if err != nil {
+		return err
+	}
+	if err = client.Delete(context.Background(), flags.Arg(0), version); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "account %s deleted\n", flags.Arg(0))
+	return nil
+}
+
+// runList - lists a page of accounts
+func runList(env *environment, args []string) error {
+	flags, connection := newFlagSet(env, "list")
+	output := outputFlag(flags)
+	listParams := accountlib.ListParams{Filter: make(map[string]string)}
+	flags.IntVar(&listParams.PageNumber, "page-number", 0, "page number, starting at 0")
+	flags.IntVar(&listParams.PageSize, "page-size", 0, "page size, 0 uses the page size of the api")
+	flags.Var(filterFlag(listParams.Filter), "filter", "filter as name=value, e.g. country=GB, can be repeated")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return usageError("expected no arguments")
+	}
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	client, err := connection.client()
+	if err != nil {
+		return err
+	}
+	accountList, err := client.List(context.Background(), listParams)
+	if err != nil {
+		return err
+	}
+	accounts := make([]*accountlib.AccountData, len(accountList.Data))
+	for i := range accountList.Data {
+		accounts[i] = &accountList.Data[i]
+	}
+	return writeAccounts(env.stdout, *output, connection.recorder.lastBody(), accounts...)
+}
+
+// filterFlag - repeatable name=value flag collecting list filters
+type filterFlag map[string]string
+
+// String - returns the filters as comma separated name=value pairs
+func (filters filterFlag) String() string {
+	pairs := make([]string, 0, len(filters))
+	for name, value := range filters {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set - adds a name=value filter
+func (filters filterFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("invalid filter %q, expected name=value", value)
+	}
+	filters[parts[0]] = parts[1]
+	return nil
+}
+
+// readCreateParams - reads create params from a file, or from stdin when the file is -
+func readCreateParams(env *environment, file string) (accountlib.AccountCreateParams, error) {
+	var createParams accountlib.AccountCreateParams
+	var reader io.Reader = env.stdin
+	if file != "-" {
+		opened, err := os.Open(file)
+		if err != nil {
+			return createParams, err
+		}
+		defer opened.Close()
+		reader = opened
+	}
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return createParams, fmt.Errorf("unable to read %s. error: %s", file, err.Error())
+	}
+
+	// accept the request body the api expects as well as the bare params
+	envelope := struct {
+		Data *accountlib.AccountCreateParams `json:"data"`
+	}{}
+	if err = json.Unmarshal(content, &envelope); err != nil {
+		return createParams, fmt.Errorf("invalid account in %s. error: %s", file, err.Error())
+	}
+	if envelope.Data != nil {
+		return *envelope.Data, nil
+	}
+	if err = json.Unmarshal(content, &createParams); err != nil {
+		return createParams, fmt.Errorf("invalid account in %s. error: %s", file, err.Error())
+	}
+	return createParams, nil
+}
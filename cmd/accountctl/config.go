@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"time"
+
+	"accountlib"
+)
+
+// default request timeout of the cli
+const defaultTimeout = 10 * time.Second
+
+// envConfig - environment variable holding the path of the config file
+const envConfig = "ACCOUNTLIB_CONFIG"
+
+// errHelp - returned when the help of a command was requested
+var errHelp = errors.New("help requested")
+
+// connectionFlags - flags shared by all commands for connecting to the api
+// Unset flags fall back to the ACCOUNTLIB_* environment variables read by the library
+type connectionFlags struct {
+	getenv     func(string) string
+	stderr     io.Writer
+	config     string
+	profile    string
+	baseURL    string
+	token      string
+	timeout    string
+	retryCount int
+
+	recorder *responseRecorder
+}
+
+// newFlagSet - returns the flag set of a command with the connection flags registered
+func newFlagSet(env *environment, name string) (*flag.FlagSet, *connectionFlags) {
+	flags := flag.NewFlagSet("accountctl "+name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	connection := &connectionFlags{getenv: env.getenv, stderr: env.stderr}
+	flags.StringVar(&connection.config, "config", "", "yaml config file, overridden by the environment and flags (env "+envConfig+")")
+	flags.StringVar(&connection.profile, "profile", "", "profile of the config file (env "+accountlib.EnvProfile+")")
+	flags.StringVar(&connection.baseURL, "base-url", "", "base url of the accounts api (env "+accountlib.EnvBaseURL+")")
+	flags.StringVar(&connection.token, "token", "", "bearer token sent with every request (env "+accountlib.EnvToken+")")
+	flags.StringVar(&connection.timeout, "timeout", "", "request timeout, e.g. 5s (env "+accountlib.EnvTimeout+", default 10s)")
+	flags.IntVar(&connection.retryCount, "retry-count", 0, "attempts per request (env "+accountlib.EnvRetryCount+")")
+	return flags, connection
+}
+
+// parseFlags - parses the arguments of a command, invalid flags are reported as usage errors
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err == flag.ErrHelp {
+		return errHelp
+	}
+	if err != nil {
+		return usageError(err.Error())
+	}
+	return nil
+}
+
+// client - returns an account client configured from the config file, the environment and the connection flags
+// A debug log level in the config file logs every request to stderr
+func (connection *connectionFlags) client() (*accountlib.Client, error) {
+	config := &accountlib.Config{}
+	if path := connection.configPath(); path != "" {
+		loaded, err := accountlib.LoadConfig(path)
+		if err != nil {
+			return nil, usageError(err.Error())
+		}
+		profile := connection.profile
+		if profile == "" {
+			profile = connection.getenv(accountlib.EnvProfile)
+		}
+		if config, err = loaded.Profile(profile); err != nil {
+			return nil, usageError(err.Error())
+		}
+	} else if connection.profile != "" {
+		return nil, usageError("--profile needs a config file")
+	}
+	options, err := config.ClientOptions(connection.getenv)
+	if err != nil {
+		return nil, usageError(err.Error())
+	}
+	if options.Timeout == 0 {
+		options.Timeout = defaultTimeout
+	}
+	if connection.baseURL != "" {
+		options.BaseURL = connection.baseURL
+	}
+	if connection.token != "" {
+		options.Token = connection.token
+	}
+	if connection.retryCount != 0 {
+		options.RetryCount = connection.retryCount
+	}
+	if connection.timeout != "" {
+		if options.Timeout, err = accountlib.ParseTimeout(connection.timeout); err != nil {
+			return nil, usageError("invalid timeout: " + err.Error())
+		}
+	}
+
+	// the transport of the config, which holds its tls settings, is wrapped for recording responses
+	connection.recorder = &responseRecorder{base: http.DefaultTransport}
+	if options.HTTPClient != nil && options.HTTPClient.Transport != nil {
+		connection.recorder.base = options.HTTPClient.Transport
+	}
+	if config.Logging.Level == "debug" {
+		connection.recorder.log = connection.stderr
+	}
+	options.HTTPClient = &http.Client{Transport: connection.recorder}
+	return accountlib.NewClient(options), nil
+}
+
+// configPath - returns the path of the config file from the flag or the environment
+func (connection *connectionFlags) configPath() string {
+	if connection.config != "" {
+		return connection.config
+	}
+	return connection.getenv(envConfig)
+}
@@ -0,0 +1,114 @@
+// accountctl - command line client for the accounts api
+//
+// Usage:
+//
+//	accountctl <command> [flags] [arguments]
+//
+// Commands:
+//
+//	create -f <file>           creates an account from a json file, "-" reads stdin
+//	fetch <account id>         fetches an account
+//	delete --version <n> <id>  deletes an account
+//	list                       lists accounts, see accountctl list -h for paging and filters
+//	apply -f <file>            converges accounts to the ones defined in a yaml or json file
+//
+// Every command accepts --config, --profile, --base-url, --token, --timeout and --retry-count. Flags take precedence over
+// the ACCOUNTLIB_* environment variables, which take precedence over the config file.
+// create, fetch and list accept --output json|yaml|table|wide, json passes the api response through.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// command - runs a subcommand with its arguments
+type command func(env *environment, args []string) error
+
+// commands - subcommands by name
+var commands = map[string]command{
+	"create": runCreate,
+	"fetch":  runFetch,
+	"delete": runDelete,
+	"list":   runList,
+	"apply":  runApply,
+}
+
+// environment - holds the io and environment lookups of a cli run
+type environment struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], &environment{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}))
+}
+
+// run - runs the cli and returns its exit code
+func run(args []string, env *environment) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(env.stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(env.stderr, "accountctl: unknown command %q\n", args[0])
+		usage(env.stderr)
+		return exitUsage
+	}
+	err := cmd(env, args[1:])
+	switch err.(type) {
+	case nil:
+		return exitOK
+	case usageError:
+		fmt.Fprintf(env.stderr, "accountctl %s: %s\n", args[0], err.Error())
+		return exitUsage
+	}
+	if err == errHelp {
+		return exitOK
+	}
+	fmt.Fprintf(env.stderr, "accountctl %s: %s\n", args[0], err.Error())
+	return exitError
+}
+
+// usage - prints the usage of the cli
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: accountctl <command> [flags] [arguments]
+
+Commands:
+  create -f <file>           creates an account from a json file, "-" reads stdin
+  fetch <account id>         fetches an account
+  delete --version <n> <id>  deletes an account
+  list                       lists accounts
+  apply -f <file>            converges accounts to the ones defined in a yaml or json file, --dry-run prints the plan
+
+Run accountctl <command> -h for the flags of a command.
+`)
+}
+
+// usageError - error caused by invalid arguments
+type usageError string
+
+// Error - returns the error message
+func (err usageError) Error() string {
+	return string(err)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/accountlibtest"
+	"accountlib/fakeserver"
+)
+
+// testEnvironment - returns an environment with captured output and the given environment variables
+func testEnvironment(stdin string, variables map[string]string) (*environment, *bytes.Buffer, *bytes.Buffer) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	return &environment{
+		stdin:  strings.NewReader(stdin),
+		stdout: stdout,
+		stderr: stderr,
+		getenv: func(name string) string { return variables[name] },
+	}, stdout, stderr
+}
+
+// TestRunUsage - tests missing and unknown commands
+func TestRunUsage(t *testing.T) {
+	check := assert.New(t)
+	env, _, stderr := testEnvironment("", nil)
+	check.Equal(exitUsage, run(nil, env))
+	check.Contains(stderr.String(), "Usage: accountctl")
+
+	env, _, stderr = testEnvironment("", nil)
+	check.Equal(exitUsage, run([]string{"rename"}, env))
+	check.Contains(stderr.String(), `unknown command "rename"`)
+
+	env, _, _ = testEnvironment("", nil)
+	check.Equal(exitOK, run([]string{"fetch", "-h"}, env))
+}
+
+// TestRunInvalidArguments - tests arguments are checked before any request is sent
+func TestRunInvalidArguments(t *testing.T) {
+	check := assert.New(t)
+	for _, args := range [][]string{
+		{"fetch"},
+		{"delete", accountlibtest.FixtureAccountID},
+		{"create"},
+		{"list", "--filter", "country"},
+		{"fetch", "--timeout", "soon", accountlibtest.FixtureAccountID},
+		{"fetch", "--output", "xml", accountlibtest.FixtureAccountID},
+	} {
+		env, _, stderr := testEnvironment("", nil)
+		check.Equal(exitUsage, run(args, env), args)
+		check.NotEmpty(stderr.String(), args)
+	}
+}
+
+// TestRunInvalidEnvironment - tests invalid environment variables are reported as usage errors
+func TestRunInvalidEnvironment(t *testing.T) {
+	check := assert.New(t)
+	env, _, stderr := testEnvironment("", map[string]string{accountlib.EnvRetryCount: "often"})
+	check.Equal(exitUsage, run([]string{"fetch", accountlibtest.FixtureAccountID}, env))
+	check.Contains(stderr.String(), "invalid "+accountlib.EnvRetryCount)
+}
+
+// TestRunLifecycle - tests create, fetch, list and delete against the fake server
+func TestRunLifecycle(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	variables := map[string]string{accountlib.EnvBaseURL: server.URL}
+	body, _ := accountlib.MarshalCreateRequest(accountlibtest.ValidGBAccount().CreateParams())
+
+	env, stdout, stderr := testEnvironment(string(body), variables)
+	check.Equal(exitOK, run([]string{"create", "-f", "-"}, env), stderr.String())
+	created, err := accountlib.DecodeAccount(stdout.Bytes())
+	check.Nil(err)
+	check.Equal(accountlibtest.FixtureAccountID, created.ID)
+
+	env, stdout, _ = testEnvironment("", variables)
+	check.Equal(exitOK, run([]string{"fetch", accountlibtest.FixtureAccountID}, env))
+	check.Contains(stdout.String(), `"organisation_id":"`+accountlibtest.FixtureOrganisationID+`"`)
+
+	env, stdout, _ = testEnvironment("", variables)
+	check.Equal(exitOK, run([]string{"list", "--filter", "country=GB", "--page-size", "10"}, env))
+	listed := struct{ Data []accountlib.AccountData }{}
+	check.Nil(json.Unmarshal(stdout.Bytes(), &listed))
+	check.Len(listed.Data, 1)
+
+	env, _, stderr = testEnvironment("", variables)
+	check.Equal(exitError, run([]string{"delete", "--version", "3", accountlibtest.FixtureAccountID}, env))
+	check.Contains(stderr.String(), "request conflict")
+
+	env, stdout, _ = testEnvironment("", variables)
+	check.Equal(exitOK, run([]string{"delete", "--version", "0", accountlibtest.FixtureAccountID}, env))
+	check.Contains(stdout.String(), "deleted")
+	check.Empty(server.Accounts())
+}
+
+// TestRunToken - tests the token is sent as bearer token and flags take precedence over the environment
+func TestRunToken(t *testing.T) {
+	check := assert.New(t)
+	authorization := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	env, _, _ := testEnvironment("", map[string]string{accountlib.EnvBaseURL: server.URL, accountlib.EnvToken: "from-env"})
+	check.Equal(exitOK, run([]string{"list"}, env))
+	check.Equal("Bearer from-env", authorization)
+
+	env, _, _ = testEnvironment("", map[string]string{accountlib.EnvBaseURL: server.URL, accountlib.EnvToken: "from-env"})
+	check.Equal(exitOK, run([]string{"list", "--token", "from-flag"}, env))
+	check.Equal("Bearer from-flag", authorization)
+}
+
+// TestRunOutputFormats - tests the raw json, yaml, table and wide outputs
+func TestRunOutputFormats(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	server.AddAccount(accountlibtest.ValidGBAccount().AccountData())
+	variables := map[string]string{accountlib.EnvBaseURL: server.URL}
+
+	env, stdout, _ := testEnvironment("", variables)
+	check.Equal(exitOK, run([]string{"fetch", accountlibtest.FixtureAccountID}, env))
+	check.True(strings.HasPrefix(stdout.String(), `{"data":{`))
+	check.Contains(stdout.String(), `"links":{"self":`)
+
+	env, stdout, _ = testEnvironment("", variables)
+	check.Equal(exitOK, run([]string{"fetch", "-o", "yaml", accountlibtest.FixtureAccountID}, env))
+	check.Contains(stdout.String(), "data:\n  attributes:\n")
+	check.Contains(stdout.String(), "  id: "+accountlibtest.FixtureAccountID+"\n")
+
+	env, stdout, _ = testEnvironment("", variables)
+	check.Equal(exitOK, run([]string{"list", "--output", "table"}, env))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	check.Len(lines, 2)
+	check.Equal([]string{"ID", "ORGANISATION", "ID", "COUNTRY", "STATUS", "VERSION"}, strings.Fields(lines[0]))
+	check.Equal([]string{accountlibtest.FixtureAccountID, accountlibtest.FixtureOrganisationID, "GB", "confirmed", "0"}, strings.Fields(lines[1]))
+
+	env, stdout, _ = testEnvironment("", variables)
+	check.Equal(exitOK, run([]string{"list", "--output", "wide"}, env))
+	lines = strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	check.Contains(lines[0], "BANK ID CODE")
+	check.Contains(lines[1], "GBDSC")
+	check.Contains(lines[1], "Samantha Holder")
+}
+
+// TestRunConfigFile - tests the config file is read and the environment and flags override it
+func TestRunConfigFile(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	server.AddAccount(accountlibtest.ValidGBAccount().AccountData())
+	dir, err := ioutil.TempDir("", "accountctl")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "accountlib.yaml")
+	check.Nil(ioutil.WriteFile(path, []byte("base_url: "+server.URL+"\nlogging:\n  level: debug\n"), 0600))
+
+	env, _, stderr := testEnvironment("", map[string]string{envConfig: path})
+	check.Equal(exitOK, run([]string{"fetch", accountlibtest.FixtureAccountID}, env), stderr.String())
+	check.Contains(stderr.String(), "GET "+server.URL+"/v1/organisation/accounts/"+accountlibtest.FixtureAccountID+" 200 ")
+
+	env, _, stderr = testEnvironment("", nil)
+	check.Equal(exitError, run([]string{"fetch", "--config", path, "--base-url", "http://127.0.0.1:1", "--retry-count", "1", accountlibtest.FixtureAccountID}, env))
+	check.Contains(stderr.String(), "127.0.0.1:1")
+
+	env, _, stderr = testEnvironment("", nil)
+	check.Equal(exitUsage, run([]string{"fetch", "--config", filepath.Join(dir, "missing.yaml"), accountlibtest.FixtureAccountID}, env))
+	check.Contains(stderr.String(), "unable to load config")
+}
+
+// TestRunProfiles - tests the profile is selected by flag, environment or the default profile of the config
+func TestRunProfiles(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	server.AddAccount(accountlibtest.ValidGBAccount().AccountData())
+	dir, err := ioutil.TempDir("", "accountctl")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "accountlib.yaml")
+	check.Nil(ioutil.WriteFile(path, []byte("retry:\n  count: 1\ndefault_profile: broken\nprofiles:\n"+
+		"  broken:\n    base_url: http://127.0.0.1:1\n  local:\n    base_url: "+server.URL+"\n"), 0600))
+
+	env, _, _ := testEnvironment("", map[string]string{envConfig: path})
+	check.Equal(exitError, run([]string{"fetch", accountlibtest.FixtureAccountID}, env))
+
+	env, _, stderr := testEnvironment("", map[string]string{envConfig: path})
+	check.Equal(exitOK, run([]string{"fetch", "--profile", "local", accountlibtest.FixtureAccountID}, env), stderr.String())
+
+	env, _, stderr = testEnvironment("", map[string]string{envConfig: path, accountlib.EnvProfile: "local"})
+	check.Equal(exitOK, run([]string{"fetch", accountlibtest.FixtureAccountID}, env), stderr.String())
+
+	env, _, stderr = testEnvironment("", map[string]string{envConfig: path})
+	check.Equal(exitUsage, run([]string{"fetch", "--profile", "staging", accountlibtest.FixtureAccountID}, env))
+	check.Contains(stderr.String(), `unknown profile "staging"`)
+
+	env, _, stderr = testEnvironment("", nil)
+	check.Equal(exitUsage, run([]string{"fetch", "--profile", "local", accountlibtest.FixtureAccountID}, env))
+	check.Contains(stderr.String(), "--profile needs a config file")
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"accountlib"
+)
+
+// output formats
+const (
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+	outputWide  = "wide"
+)
+
+// column - column of the table output
+type column struct {
+	header string
+	value  func(account *accountlib.AccountData) string
+}
+
+// tableColumns - columns of the table output, the set is stable so scripts can rely on it
+var tableColumns = []column{
+	{"ID", func(account *accountlib.AccountData) string { return account.ID }},
+	{"ORGANISATION ID", func(account *accountlib.AccountData) string { return account.OrganisationID }},
+	{"COUNTRY", func(account *accountlib.AccountData) string {
+		if account.Attributes == nil || account.Attributes.Country == nil {
+			return ""
+		}
+		return string(*account.Attributes.Country)
+	}},
+	{"STATUS", func(account *accountlib.AccountData) string {
+		if account.Attributes == nil || account.Attributes.Status == nil {
+			return ""
+		}
+		return string(*account.Attributes.Status)
+	}},
+	{"VERSION", func(account *accountlib.AccountData) string {
+		if account.Version == nil {
+			return ""
+		}
+		return strconv.FormatInt(*account.Version, 10)
+	}},
+}
+
+// wideColumns - columns added to the table columns by the wide output
+var wideColumns = []column{
+	{"BANK ID", func(account *accountlib.AccountData) string { return attributes(account).BankID }},
+	{"BANK ID CODE", func(account *accountlib.AccountData) string { return attributes(account).BankIDCode }},
+	{"BIC", func(account *accountlib.AccountData) string { return attributes(account).Bic }},
+	{"CURRENCY", func(account *accountlib.AccountData) string { return string(attributes(account).BaseCurrency) }},
+	{"CLASSIFICATION", func(account *accountlib.AccountData) string {
+		if attributes(account).AccountClassification == nil {
+			return ""
+		}
+		return string(*attributes(account).AccountClassification)
+	}},
+	{"NAME", func(account *accountlib.AccountData) string { return strings.Join(attributes(account).Name, " ") }},
+}
+
+// attributes - returns the attributes of an account, empty attributes if it has none
+func attributes(account *accountlib.AccountData) *accountlib.AccountAttributes {
+	if account.Attributes == nil {
+		return &accountlib.AccountAttributes{}
+	}
+	return account.Attributes
+}
+
+// outputFlag - registers the output flag of a command along with its -o shorthand
+func outputFlag(flags *flag.FlagSet) *string {
+	output := flags.String("output", outputJSON, "output format, one of json, yaml, table or wide")
+	flags.StringVar(output, "o", outputJSON, "shorthand for --output")
+	return output
+}
+
+// checkOutput - checks the output format before any request is sent
+func checkOutput(format string) error {
+	switch format {
+	case outputJSON, outputYAML, outputTable, outputWide:
+		return nil
+	}
+	return usageError(fmt.Sprintf("invalid output %q, expected json, yaml, table or wide", format))
+}
+
+// writeAccounts - writes accounts in the given format
+// JSON output passes the response body of the api through unchanged, so it can be piped into jq
+func writeAccounts(w io.Writer, format string, raw []byte, accounts ...*accountlib.AccountData) error {
+	switch format {
+	case outputYAML:
+		return writeYAML(w, raw)
+	case outputTable:
+		return writeTable(w, tableColumns, accounts)
+	case outputWide:
+		return writeTable(w, append(append([]column{}, tableColumns...), wideColumns...), accounts)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(raw, []byte("\n")) {
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+	return nil
+}
+
+// writeYAML - writes a json response body as yaml
+func writeYAML(w io.Writer, raw []byte) error {
+	var document interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeTable - writes accounts as a table with a header row
+func writeTable(w io.Writer, columns []column, accounts []*accountlib.AccountData) error {
+	table := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i] = column.header
+	}
+	fmt.Fprintln(table, strings.Join(cells, "\t"))
+	for _, account := range accounts {
+		for i, column := range columns {
+			cells[i] = column.value(account)
+		}
+		fmt.Fprintln(table, strings.Join(cells, "\t"))
+	}
+	return table.Flush()
+}
+
+// responseRecorder - round tripper keeping the body of the last response, used for the raw json output
+// Requests are logged to log when it is set
+type responseRecorder struct {
+	base http.RoundTripper
+	log  io.Writer
+
+	mutex sync.Mutex
+	body  []byte
+}
+
+// RoundTrip - sends the request and records the response body
+func (recorder *responseRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := recorder.base.RoundTrip(req)
+	if recorder.log != nil {
+		if err != nil {
+			fmt.Fprintf(recorder.log, "%s %s failed after %s: %s\n", req.Method, req.URL, time.Since(start), err.Error())
+		} else {
+			fmt.Fprintf(recorder.log, "%s %s %d %s\n", req.Method, req.URL, resp.StatusCode, time.Since(start))
+		}
+	}
+	if err != nil {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	recorder.mutex.Lock()
+	recorder.body = body
+	recorder.mutex.Unlock()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// lastBody - returns the body of the last response
+func (recorder *responseRecorder) lastBody() []byte {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return recorder.body
+}
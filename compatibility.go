@@ -0,0 +1,103 @@
+package accountlib
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// api versions supported by this library, servers advertise their version in the APIVersionHeader of every response
+const (
+	APIVersionHeader = "Api-Version"
+	MinAPIVersion    = "1.0"
+	MaxAPIVersion    = "1.0"
+)
+
+// CompatibilityError - returned by CheckCompatibility when the api version of the server is outside the supported range
+// Warning is set when the library is expected to keep working, like for a newer minor version or a server
+// not advertising its version, so callers can log warnings and stop the application only on errors
+type CompatibilityError struct {
+	ServerVersion string
+	MinVersion    string
+	MaxVersion    string
+	Warning       bool
+	Reason        string
+}
+
+// Error - returns the compatibility error message
+func (e *CompatibilityError) Error() string {
+	level := "incompatible"
+	if e.Warning {
+		level = "possibly incompatible"
+	}
+	return fmt.Sprintf("%s api version %q, supported versions %s to %s: %s", level, e.ServerVersion, e.MinVersion, e.MaxVersion, e.Reason)
+}
+
+// CheckCompatibility - probes the health endpoint of the api and compares the advertised api version with the supported range
+// It returns nil for a supported version and a *CompatibilityError otherwise, use it at startup to catch an
+// incompatible api once instead of on every request
+func (client *Client) CheckCompatibility(ctx context.Context, options ...CallOption) (err error) {
+	// prepare request specifications
+	requestSpecifications := client.requestSpecifications(ctx, OperationCheckCompatibility, http.MethodGet, client.apiURL(healthPath), nil)
+	defer wrapError(&err, requestSpecifications, time.Now())
+
+	// make request
+	statusCode, response, headers, err := client.do(requestSpecifications, options)
+	if err != nil {
+		return err
+	}
+
+	// the version is checked on unhealthy apis too, as long as they advertise it
+	serverVersion := headers.Get(APIVersionHeader)
+	if serverVersion == "" && statusCode != http.StatusOK {
+		return client.statusErrors.HandleErrorStatusCode(statusCode, response)
+	}
+	return checkAPIVersion(serverVersion)
+}
+
+// checkAPIVersion - compares an api version with the supported range
+func checkAPIVersion(serverVersion string) error {
+	compatibilityError := &CompatibilityError{ServerVersion: serverVersion, MinVersion: MinAPIVersion, MaxVersion: MaxAPIVersion}
+	if serverVersion == "" {
+		compatibilityError.Warning = true
+		compatibilityError.Reason = "the server does not advertise its api version"
+		return compatibilityError
+	}
+
+	major, minor, err := parseAPIVersion(serverVersion)
+	if err != nil {
+		compatibilityError.Reason = err.Error()
+		return compatibilityError
+	}
+	minMajor, minMinor, _ := parseAPIVersion(MinAPIVersion)
+	maxMajor, maxMinor, _ := parseAPIVersion(MaxAPIVersion)
+	switch {
+	case major < minMajor || (major == minMajor && minor < minMinor):
+		compatibilityError.Reason = "the server is older than this library supports"
+	case major > maxMajor:
+		compatibilityError.Reason = "the server has a newer major version, upgrade this library"
+	case major == maxMajor && minor > maxMinor:
+		compatibilityError.Warning = true
+		compatibilityError.Reason = "the server has a newer minor version, features added since are not supported"
+	default:
+		return nil
+	}
+	return compatibilityError
+}
+
+// parseAPIVersion - parses an api version like 1.2 or v1.2 into its major and minor version, a missing minor version is 0
+func parseAPIVersion(version string) (major, minor int, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if major, err = strconv.Atoi(parts[0]); err != nil || major < 0 {
+		return 0, 0, fmt.Errorf("malformed api version %q", version)
+	}
+	if len(parts) > 1 {
+		if minor, err = strconv.Atoi(parts[1]); err != nil || minor < 0 {
+			return 0, 0, fmt.Errorf("malformed api version %q", version)
+		}
+	}
+	return major, minor, nil
+}
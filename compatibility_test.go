@@ -0,0 +1,74 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/errors"
+)
+
+// versionServer - returns a server advertising an api version with the given status code
+func versionServer(statusCode int, version string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if version != "" {
+			w.Header().Set(APIVersionHeader, version)
+		}
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(`{"status":"up"}`))
+	}))
+}
+
+// TestCheckCompatibility - tests supported, older, newer and malformed api versions
+func TestCheckCompatibility(t *testing.T) {
+	check := assert.New(t)
+	for version, expected := range map[string]*CompatibilityError{
+		"1.0":  nil,
+		"v1.0": nil,
+		"1":    nil,
+		"0.9":  {Reason: "the server is older than this library supports"},
+		"2.0":  {Reason: "the server has a newer major version, upgrade this library"},
+		"1.1":  {Warning: true, Reason: "the server has a newer minor version, features added since are not supported"},
+		"":     {Warning: true, Reason: "the server does not advertise its api version"},
+		"beta": {Reason: `malformed api version "beta"`},
+	} {
+		server := versionServer(http.StatusOK, version)
+		err := NewClient(&ClientOptions{BaseURL: server.URL}).CheckCompatibility(context.Background())
+		server.Close()
+		if expected == nil {
+			check.Nil(err, version)
+			continue
+		}
+		compatibilityError := &CompatibilityError{}
+		check.True(errors.As(err, &compatibilityError), version)
+		check.Equal(&CompatibilityError{
+			ServerVersion: version,
+			MinVersion:    MinAPIVersion,
+			MaxVersion:    MaxAPIVersion,
+			Warning:       expected.Warning,
+			Reason:        expected.Reason,
+		}, compatibilityError)
+	}
+}
+
+// TestCheckCompatibilityUnhealthy - tests the version of an unhealthy api is checked, and errors without a version returned
+func TestCheckCompatibilityUnhealthy(t *testing.T) {
+	check := assert.New(t)
+	server := versionServer(http.StatusNotFound, "2.0")
+	defer server.Close()
+	err := NewClient(&ClientOptions{BaseURL: server.URL}).CheckCompatibility(context.Background())
+	check.Contains(err.Error(), `incompatible api version "2.0", supported versions 1.0 to 1.0: the server has a newer major version, upgrade this library`)
+	operationError := &accounterrors.OperationError{}
+	check.True(errors.As(err, &operationError))
+	check.Equal(OperationCheckCompatibility, operationError.Operation)
+	check.Equal(http.MethodGet, operationError.Method)
+
+	unversionedServer := versionServer(http.StatusNotFound, "")
+	defer unversionedServer.Close()
+	err = NewClient(&ClientOptions{BaseURL: unversionedServer.URL}).CheckCompatibility(context.Background())
+	check.Contains(err.Error(), "resource not found")
+}
@@ -0,0 +1,264 @@
+package accountlib
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// log levels supported by the logging settings
+var logLevels = map[string]bool{"": true, "debug": true, "info": true, "warn": true, "error": true, "off": true}
+
+// Config - client settings loaded from a yaml config file
+// Profiles hold named environments like sandbox or production, their settings override the top level ones
+// DefaultProfile is used when no profile is selected
+type Config struct {
+	BaseURL            string                   `yaml:"base_url"`
+	Timeout            string                   `yaml:"timeout"`
+	StrictDecoding     bool                     `yaml:"strict_decoding"`
+	ValidateBeforeSend bool                     `yaml:"validate_before_send"`
+	Auth               AuthConfig               `yaml:"auth"`
+	Retry              RetryConfig              `yaml:"retry"`
+	TLS                TLSConfig                `yaml:"tls"`
+	Logging            LoggingConfig            `yaml:"logging"`
+	DefaultProfile     string                   `yaml:"default_profile"`
+	Profiles           map[string]ProfileConfig `yaml:"profiles"`
+}
+
+// ProfileConfig - settings of a named profile, unset settings are taken from the top level of the config
+type ProfileConfig struct {
+	BaseURL string      `yaml:"base_url"`
+	Timeout string      `yaml:"timeout"`
+	Auth    AuthConfig  `yaml:"auth"`
+	Retry   RetryConfig `yaml:"retry"`
+	TLS     TLSConfig   `yaml:"tls"`
+}
+
+// AuthConfig - authentication settings, TokenFile is read when Token is empty
+type AuthConfig struct {
+	Token     string `yaml:"token"`
+	TokenFile string `yaml:"token_file"`
+}
+
+// RetryConfig - retry policy settings, Count is the number of attempts per request
+type RetryConfig struct {
+	Count int `yaml:"count"`
+}
+
+// TLSConfig - tls settings, CAFile adds trusted certificates, CertFile and KeyFile hold a client certificate
+type TLSConfig struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// LoggingConfig - logging settings, Level is one of debug, info, warn, error or off
+// The settings are validated but not applied by the library, ClientOptions has no logging and the client itself does not
+// log. Only accountctl reads them, it logs every request to stderr with the debug level. Applications configure their
+// own logging from them
+type LoggingConfig struct {
+	Level string `yaml:"level"`
+}
+
+// LoadConfig - loads a yaml config file, unknown settings are rejected so typos do not go unnoticed
+// Relative file paths in the config are resolved against the directory of the config file
+func LoadConfig(path string) (*Config, error) {
+	if extension := strings.ToLower(filepath.Ext(path)); extension == ".toml" {
+		return nil, fmt.Errorf("unable to load config %s. error: toml config files are not supported, use yaml", path)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config %s. error: %s", path, err.Error())
+	}
+
+	config := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid config %s. error: %s", path, err.Error())
+	}
+	if err = config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s. error: %s", path, err.Error())
+	}
+	for name := range config.Profiles {
+		profile, _ := config.Profile(name)
+		if err = profile.validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s. error: profile %s: %s", path, name, err.Error())
+		}
+	}
+	if _, err = config.Profile(""); err != nil {
+		return nil, fmt.Errorf("invalid config %s. error: %s", path, err.Error())
+	}
+	config.resolvePaths(filepath.Dir(path))
+	return config, nil
+}
+
+// Profile - returns the config of a profile, with the settings of the profile overriding the top level ones
+// An empty name selects the default profile, the config itself is returned when it has no default profile
+func (config *Config) Profile(name string) (*Config, error) {
+	if name == "" {
+		name = config.DefaultProfile
+	}
+	if name == "" {
+		return config, nil
+	}
+	profile, ok := config.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(config.Profiles))
+		for known := range config.Profiles {
+			names = append(names, known)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown profile %q, known profiles: %s", name, strings.Join(names, ", "))
+	}
+
+	selected := *config
+	selected.DefaultProfile = ""
+	selected.Profiles = nil
+	if profile.BaseURL != "" {
+		selected.BaseURL = profile.BaseURL
+	}
+	if profile.Timeout != "" {
+		selected.Timeout = profile.Timeout
+	}
+	if profile.Auth != (AuthConfig{}) {
+		selected.Auth = profile.Auth
+	}
+	if profile.Retry != (RetryConfig{}) {
+		selected.Retry = profile.Retry
+	}
+	if profile.TLS != (TLSConfig{}) {
+		selected.TLS = profile.TLS
+	}
+	return &selected, nil
+}
+
+// NewClientFromConfig - creates a new account client from a config file and one of its profiles
+// An empty profile selects the profile named by ACCOUNTLIB_PROFILE, then the default profile of the config
+// The ACCOUNTLIB_* environment variables which are set take precedence over the config
+func NewClientFromConfig(path, profile string) (*Client, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	if config, err = config.Profile(profile); err != nil {
+		return nil, err
+	}
+	options, err := config.ClientOptions(nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(options), nil
+}
+
+// ClientOptions - returns client options from the config, overridden by the ACCOUNTLIB_* environment variables which are set
+// getenv looks the variables up, nil uses os.Getenv
+func (config *Config) ClientOptions(getenv func(string) string) (*ClientOptions, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	options := &ClientOptions{
+		BaseURL:            config.BaseURL,
+		StrictDecoding:     config.StrictDecoding,
+		ValidateBeforeSend: config.ValidateBeforeSend,
+		RetryCount:         config.Retry.Count,
+		Token:              config.Auth.Token,
+	}
+
+	var err error
+	if config.Timeout != "" {
+		if options.Timeout, err = ParseTimeout(config.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid timeout: %s", err.Error())
+		}
+	}
+	if options.Token == "" && config.Auth.TokenFile != "" {
+		token, err := ioutil.ReadFile(config.Auth.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read token file. error: %s", err.Error())
+		}
+		options.Token = strings.TrimSpace(string(token))
+	}
+	if config.TLS != (TLSConfig{}) {
+		tlsConfig, err := config.TLS.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		options.TLSConfig = tlsConfig
+	}
+	return mergeEnv(options, getenv)
+}
+
+// validate - checks the settings which can be checked without reading other files
+func (config *Config) validate() error {
+	if config.Timeout != "" {
+		if _, err := ParseTimeout(config.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout: %s", err.Error())
+		}
+	}
+	if config.Retry.Count < 0 {
+		return errors.New("retry count must not be negative")
+	}
+	if (config.TLS.CertFile == "") != (config.TLS.KeyFile == "") {
+		return errors.New("tls cert_file and key_file must be set together")
+	}
+	if !logLevels[config.Logging.Level] {
+		return fmt.Errorf("unknown log level %q", config.Logging.Level)
+	}
+	return nil
+}
+
+// resolvePaths - resolves relative file paths of the config and its profiles against the directory of the config file
+func (config *Config) resolvePaths(dir string) {
+	resolvePaths(dir, &config.Auth, &config.TLS)
+	for name, profile := range config.Profiles {
+		resolvePaths(dir, &profile.Auth, &profile.TLS)
+		config.Profiles[name] = profile
+	}
+}
+
+// resolvePaths - resolves relative file paths of auth and tls settings against a directory
+func resolvePaths(dir string, auth *AuthConfig, tlsSettings *TLSConfig) {
+	for _, path := range []*string{&auth.TokenFile, &tlsSettings.CAFile, &tlsSettings.CertFile, &tlsSettings.KeyFile} {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+}
+
+// tlsConfig - returns the tls client config of the settings
+func (settings TLSConfig) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: settings.InsecureSkipVerify}
+	if settings.CAFile != "" {
+		pem, err := ioutil.ReadFile(settings.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read tls ca file. error: %s", err.Error())
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in tls ca file %s", settings.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if settings.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(settings.CertFile, settings.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load tls client certificate. error: %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
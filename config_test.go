@@ -0,0 +1,198 @@
+package accountlib
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfigFile - writes a file into a temporary directory and returns its path
+func writeConfigFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// tempConfigDir - returns a temporary directory which is removed after the test
+func tempConfigDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "accountlib-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// TestLoadConfig - tests every setting is loaded and relative paths are resolved
+func TestLoadConfig(t *testing.T) {
+	check := assert.New(t)
+	dir := tempConfigDir(t)
+	writeConfigFile(t, dir, "token", "secret\n")
+	path := writeConfigFile(t, dir, "accountlib.yaml", `
+base_url: http://accounts.internal
+timeout: 3s
+strict_decoding: true
+auth:
+  token_file: token
+retry:
+  count: 4
+logging:
+  level: debug
+`)
+
+	config, err := LoadConfig(path)
+	check.Nil(err)
+	check.Equal("debug", config.Logging.Level)
+	check.Equal(filepath.Join(dir, "token"), config.Auth.TokenFile)
+
+	options, err := config.ClientOptions(func(string) string { return "" })
+	check.Nil(err)
+	check.Equal(&ClientOptions{
+		BaseURL:        "http://accounts.internal",
+		Timeout:        3 * time.Second,
+		StrictDecoding: true,
+		RetryCount:     4,
+		Token:          "secret",
+	}, options)
+}
+
+// TestConfigEnvironmentOverrides - tests environment variables take precedence over the config file
+func TestConfigEnvironmentOverrides(t *testing.T) {
+	check := assert.New(t)
+	config := &Config{BaseURL: "http://accounts.internal", Timeout: "3s", Retry: RetryConfig{Count: 4}}
+	variables := map[string]string{EnvBaseURL: "http://localhost:8080", EnvRetryCount: "1"}
+
+	options, err := config.ClientOptions(func(name string) string { return variables[name] })
+	check.Nil(err)
+	check.Equal("http://localhost:8080", options.BaseURL)
+	check.Equal(1, options.RetryCount)
+	check.Equal(3*time.Second, options.Timeout)
+}
+
+// TestLoadConfigInvalid - tests unknown settings, invalid values and toml files are rejected
+func TestLoadConfigInvalid(t *testing.T) {
+	check := assert.New(t)
+	dir := tempConfigDir(t)
+	for content, message := range map[string]string{
+		"base_ur1: http://accounts.internal": "field base_ur1 not found",
+		"timeout: soon":                      "invalid timeout",
+		"retry:\n  count: -1":                "retry count must not be negative",
+		"tls:\n  cert_file: client.pem":      "cert_file and key_file must be set together",
+		"logging:\n  level: verbose":         `unknown log level "verbose"`,
+	} {
+		_, err := LoadConfig(writeConfigFile(t, dir, "accountlib.yaml", content))
+		check.Contains(err.Error(), message)
+	}
+
+	_, err := LoadConfig(writeConfigFile(t, dir, "accountlib.toml", `base_url = "http://accounts.internal"`))
+	check.Contains(err.Error(), "toml config files are not supported")
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.yaml"))
+	check.Contains(err.Error(), "unable to load config")
+}
+
+// TestConfigTLS - tests the client trusts the certificate authority of the config and keeps the default timeout and
+// the connection statistics of its own transport
+func TestConfigTLS(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`))
+	}))
+	defer server.Close()
+	dir := tempConfigDir(t)
+	writeConfigFile(t, dir, "ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})))
+	path := writeConfigFile(t, dir, "accountlib.yaml", "base_url: "+server.URL+"\ntls:\n  ca_file: ca.pem\n")
+
+	config, err := LoadConfig(path)
+	check.Nil(err)
+	options, err := config.ClientOptions(func(string) string { return "" })
+	check.Nil(err)
+	check.Nil(options.HTTPClient)
+	client := NewClient(options)
+	check.Equal(5*time.Second, client.httpClient.Timeout)
+	accountData, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", accountData.ID)
+	check.Equal(int64(1), client.PoolStats().OpenConnections)
+
+	_, err = NewClient(&ClientOptions{BaseURL: server.URL}).Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.NotNil(err)
+}
+
+// profilesConfig - config with a sandbox and a production profile
+const profilesConfig = `
+timeout: 3s
+default_profile: sandbox
+auth:
+  token: shared
+profiles:
+  sandbox:
+    base_url: http://sandbox.internal
+  production:
+    base_url: https://production.internal
+    timeout: 10s
+    auth:
+      token_file: production-token
+`
+
+// TestConfigProfiles - tests profile settings override the top level ones
+func TestConfigProfiles(t *testing.T) {
+	check := assert.New(t)
+	dir := tempConfigDir(t)
+	config, err := LoadConfig(writeConfigFile(t, dir, "accountlib.yaml", profilesConfig))
+	check.Nil(err)
+
+	sandbox, err := config.Profile("")
+	check.Nil(err)
+	check.Equal("http://sandbox.internal", sandbox.BaseURL)
+	check.Equal("3s", sandbox.Timeout)
+	check.Equal("shared", sandbox.Auth.Token)
+
+	production, err := config.Profile("production")
+	check.Nil(err)
+	check.Equal("https://production.internal", production.BaseURL)
+	check.Equal("10s", production.Timeout)
+	check.Equal(AuthConfig{TokenFile: filepath.Join(dir, "production-token")}, production.Auth)
+	check.Nil(production.Profiles)
+
+	_, err = config.Profile("staging")
+	check.Equal(`unknown profile "staging", known profiles: production, sandbox`, err.Error())
+}
+
+// TestLoadConfigInvalidProfiles - tests invalid profiles and unknown default profiles are rejected
+func TestLoadConfigInvalidProfiles(t *testing.T) {
+	check := assert.New(t)
+	dir := tempConfigDir(t)
+
+	_, err := LoadConfig(writeConfigFile(t, dir, "accountlib.yaml", "profiles:\n  sandbox:\n    timeout: soon\n"))
+	check.Contains(err.Error(), "profile sandbox: invalid timeout")
+
+	_, err = LoadConfig(writeConfigFile(t, dir, "accountlib.yaml", "default_profile: staging\nprofiles:\n  sandbox: {}\n"))
+	check.Contains(err.Error(), `unknown profile "staging"`)
+}
+
+// TestNewClientFromConfig - tests a client is created from the selected profile
+func TestNewClientFromConfig(t *testing.T) {
+	check := assert.New(t)
+	dir := tempConfigDir(t)
+	writeConfigFile(t, dir, "production-token", "production-secret")
+	path := writeConfigFile(t, dir, "accountlib.yaml", profilesConfig)
+
+	client, err := NewClientFromConfig(path, "production")
+	check.Nil(err)
+	check.Equal("https://production.internal", client.baseURL)
+	check.Equal("Bearer production-secret", client.headers.Get("Authorization"))
+
+	_, err = NewClientFromConfig(path, "staging")
+	check.Contains(err.Error(), "unknown profile")
+}
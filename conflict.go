@@ -0,0 +1,72 @@
+package accountlib
+
+import (
+	"context"
+	"net/http"
+)
+
+// maximum number of conflicts resolved for a single create or update
+const maxConflictResolutions = 3
+
+// ConflictAction - the way a create or update failing with a conflict continues
+type ConflictAction string
+
+// conflict actions
+const (
+	// ConflictAbort - returns the conflict error
+	ConflictAbort ConflictAction = "abort"
+	// ConflictRetry - updates the remote account with the local attributes, overwriting the remote changes
+	ConflictRetry ConflictAction = "retry"
+	// ConflictMerge - updates the remote account with the merged attributes of the resolution
+	ConflictMerge ConflictAction = "merge"
+)
+
+// Conflict - a create or update which failed with a conflict, because the account exists already or its version changed
+// Local holds the params of the operation, without version for creates, Remote the current state of the account
+type Conflict struct {
+	Operation string
+	Local     AccountUpdateParams
+	Remote    *AccountData
+}
+
+// ConflictResolution - decision of a conflict resolver, Attributes hold the merged attributes for ConflictMerge
+type ConflictResolution struct {
+	Action     ConflictAction
+	Attributes *AccountCreateAttributes
+}
+
+// ConflictResolver - decides how a create or update failing with a conflict continues, set it in ClientOptions
+// Retries and merges are sent as update against the remote version. Further conflicts are resolved again up to
+// three times, errors of the resolver are returned
+type ConflictResolver func(ctx context.Context, conflict Conflict) (ConflictResolution, error)
+
+// resolveConflict - resolves the conflict of a create or update with the conflict resolver of the client
+// The conflict error is returned when the resolver aborts or the remote account can not be fetched
+func (client *Client) resolveConflict(ctx context.Context, operation string, local AccountUpdateParams, conflictErr error, options []CallOption) (*AccountData, error) {
+	for i := 0; i < maxConflictResolutions; i++ {
+		remote, err := client.Fetch(ctx, local.ID, options...)
+		if err != nil {
+			return nil, conflictErr
+		}
+		resolution, err := client.conflictResolver(ctx, Conflict{Operation: operation, Local: local, Remote: remote.Clone()})
+		if err != nil {
+			return nil, err
+		}
+
+		updateParams := AccountUpdateParams{ID: remote.ID, OrganisationID: remote.OrganisationID, Type: remote.Type, Version: remote.Version}
+		switch resolution.Action {
+		case ConflictRetry:
+			updateParams.Attributes = local.Attributes
+		case ConflictMerge:
+			updateParams.Attributes = resolution.Attributes
+		default:
+			return nil, conflictErr
+		}
+		updated, statusCode, err := client.update(ctx, updateParams, options)
+		if statusCode != http.StatusConflict {
+			return updated, err
+		}
+		conflictErr = err
+	}
+	return nil, conflictErr
+}
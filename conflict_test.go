@@ -0,0 +1,88 @@
+package accountlib_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/accountlibtest"
+	"accountlib/fakeserver"
+)
+
+// TestConflictResolver - tests conflicts of creates and updates are resolved by the resolver of the client
+func TestConflictResolver(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	var conflicts []accountlib.Conflict
+	resolution := accountlib.ConflictResolution{Action: accountlib.ConflictAbort}
+	client := accountlib.NewClient(&accountlib.ClientOptions{
+		BaseURL: server.URL,
+		ConflictResolver: func(ctx context.Context, conflict accountlib.Conflict) (accountlib.ConflictResolution, error) {
+			conflicts = append(conflicts, conflict)
+			return resolution, nil
+		},
+	})
+	fixture := accountlibtest.ValidGBAccount()
+	created, err := client.Create(context.Background(), fixture.CreateParams())
+	check.Nil(err)
+	stale := accountlib.AccountUpdateParams{ID: created.ID, Version: created.Version, Attributes: fixture.WithName("Jane Doe").CreateParams().Attributes}
+	_, err = client.Update(context.Background(), stale)
+	check.Nil(err)
+	check.Empty(conflicts)
+
+	// abort returns the conflict
+	_, err = client.Update(context.Background(), stale)
+	check.Contains(err.Error(), "request conflict")
+	check.Len(conflicts, 1)
+	check.Equal(accountlib.OperationUpdate, conflicts[0].Operation)
+	check.Equal(stale, conflicts[0].Local)
+	check.Equal(int64(1), *conflicts[0].Remote.Version)
+
+	// retry sends the local attributes against the remote version
+	resolution = accountlib.ConflictResolution{Action: accountlib.ConflictRetry}
+	updated, err := client.Update(context.Background(), stale)
+	check.Nil(err)
+	check.Equal(int64(2), *updated.Version)
+
+	// merge sends the attributes of the resolution
+	merged := updated.Attributes.CreateAttributes()
+	merged.Bic = "NWBKGB42"
+	resolution = accountlib.ConflictResolution{Action: accountlib.ConflictMerge, Attributes: merged}
+	updated, err = client.Update(context.Background(), stale)
+	check.Nil(err)
+	check.Equal(int64(3), *updated.Version)
+	check.Equal("NWBKGB42", updated.Attributes.Bic)
+
+	// creates of existing accounts are resolved the same way
+	resolution = accountlib.ConflictResolution{Action: accountlib.ConflictRetry}
+	updated, err = client.Create(context.Background(), fixture.WithName("John Doe").CreateParams())
+	check.Nil(err)
+	check.Equal(int64(4), *updated.Version)
+	check.Equal([]string{"John Doe"}, updated.Attributes.Name)
+	check.Equal(accountlib.OperationCreate, conflicts[len(conflicts)-1].Operation)
+	check.Nil(conflicts[len(conflicts)-1].Local.Version)
+}
+
+// TestConflictResolverError - tests errors of the resolver are returned
+func TestConflictResolverError(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	resolverError := errors.New("resolver failed")
+	client := accountlib.NewClient(&accountlib.ClientOptions{
+		BaseURL: server.URL,
+		ConflictResolver: func(context.Context, accountlib.Conflict) (accountlib.ConflictResolution, error) {
+			return accountlib.ConflictResolution{}, resolverError
+		},
+	})
+	params := accountlibtest.ValidGBAccount().CreateParams()
+	_, err := client.Create(context.Background(), params)
+	check.Nil(err)
+
+	_, err = client.Create(context.Background(), params)
+	check.Equal(resolverError, err)
+}
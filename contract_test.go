@@ -0,0 +1,104 @@
+package accountlib_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"accountlib"
+	"accountlib/accountlibtest"
+)
+
+// contract test constants
+const contractURLEnv = "ACCOUNTLIB_CONTRACT_URL"
+
+// ContractTestSuite - verifies the assumptions of the client against a live accounts api
+// The suite only runs when ACCOUNTLIB_CONTRACT_URL points to a real or staging endpoint, e.g.
+// ACCOUNTLIB_CONTRACT_URL=http://localhost:8080 go test -run TestContractTestSuite -v
+type ContractTestSuite struct {
+	suite.Suite
+	client *accountlib.Client
+}
+
+// TestContractTestSuite - runs the contract test suite if an endpoint is configured
+func TestContractTestSuite(t *testing.T) {
+	baseURL := os.Getenv(contractURLEnv)
+	if baseURL == "" {
+		t.Skipf("%s is not set, skipping contract tests", contractURLEnv)
+	}
+	suite.Run(t, &ContractTestSuite{
+		client: accountlib.NewClient(&accountlib.ClientOptions{BaseURL: baseURL, StrictDecoding: true}),
+	})
+}
+
+// newParams - returns valid create params with a fresh account id
+func (s *ContractTestSuite) newParams() accountlib.AccountCreateParams {
+	return accountlibtest.ValidGBAccount().WithID(uuid.New().String()).CreateParams()
+}
+
+// TestAccountLifecycle - tests create, fetch, list and delete return the documented payloads
+func (s *ContractTestSuite) TestAccountLifecycle() {
+	params := s.newParams()
+
+	// create returns the account with version 0 and the sent attributes
+	created, err := s.client.Create(context.Background(), params)
+	s.Require().NoError(err)
+	s.Equal(params.ID, created.ID)
+	s.Equal(params.OrganisationID, created.OrganisationID)
+	s.Require().NotNil(created.Version)
+	s.Equal(int64(0), *created.Version)
+	s.Require().NotNil(created.Attributes)
+	s.Equal(*params.Attributes.Country, *created.Attributes.Country)
+	s.Equal(params.Attributes.Name, created.Attributes.Name)
+
+	// fetch returns the created account
+	fetched, err := s.client.Fetch(context.Background(), params.ID)
+	s.Require().NoError(err)
+	s.True(fetched.Equal(created), "fetched account differs from created account: %v", accountlib.Diff(created, fetched))
+
+	// list returns pages of accounts
+	accountList, err := s.client.List(context.Background(), accountlib.ListParams{PageSize: 1})
+	s.Require().NoError(err)
+	s.Len(accountList.Data, 1)
+
+	// delete with the current version removes the account
+	s.Require().NoError(s.client.Delete(context.Background(), params.ID, created.Version))
+	_, err = s.client.Fetch(context.Background(), params.ID)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "resource not found")
+}
+
+// TestDuplicateCreate - tests creating an existing account id is a conflict
+func (s *ContractTestSuite) TestDuplicateCreate() {
+	params := s.newParams()
+	created, err := s.client.Create(context.Background(), params)
+	s.Require().NoError(err)
+	defer func() { _ = s.client.Delete(context.Background(), params.ID, created.Version) }()
+
+	_, err = s.client.Create(context.Background(), params)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "request conflict")
+}
+
+// TestStaleDelete - tests deleting with a wrong version is a conflict
+func (s *ContractTestSuite) TestStaleDelete() {
+	params := s.newParams()
+	created, err := s.client.Create(context.Background(), params)
+	s.Require().NoError(err)
+	defer func() { _ = s.client.Delete(context.Background(), params.ID, created.Version) }()
+
+	staleVersion := *created.Version + 1
+	err = s.client.Delete(context.Background(), params.ID, &staleVersion)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "request conflict")
+}
+
+// TestFetchUnknownAccount - tests fetching an unknown account is not found
+func (s *ContractTestSuite) TestFetchUnknownAccount() {
+	_, err := s.client.Fetch(context.Background(), uuid.New().String())
+	s.Require().Error(err)
+	s.Contains(err.Error(), "resource not found")
+}
@@ -0,0 +1,106 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// credential names read by the client
+const (
+	TokenName = "token"
+)
+
+// ErrNotFound - matches errors of credentials a store does not hold
+var ErrNotFound = errors.New("credential not found")
+
+// CredentialStore - retrieves secrets like api tokens and signing keys by name when they are needed, so they are not
+// passed to the client as plain strings. Implement it for secret managers like vault or the secret managers of cloud
+// providers, StoreFunc adapts a function. Credentials which are not held by the store return an error matching ErrNotFound
+type CredentialStore interface {
+	Credential(ctx context.Context, name string) ([]byte, error)
+}
+
+// StoreFunc - adapts a function to a credential store
+type StoreFunc func(ctx context.Context, name string) ([]byte, error)
+
+// Credential - calls the function
+func (store StoreFunc) Credential(ctx context.Context, name string) ([]byte, error) {
+	return store(ctx, name)
+}
+
+// EnvStore - reads credentials from environment variables named by the prefix and the upper case name, with
+// characters other than letters and digits replaced by underscores. The token of the ACCOUNTLIB_ prefix is ACCOUNTLIB_TOKEN
+type EnvStore struct {
+	Prefix string
+	getenv func(string) string
+}
+
+// NewEnvStore - returns a store reading credentials from the environment variables of the prefix
+func NewEnvStore(prefix string) *EnvStore {
+	return &EnvStore{Prefix: prefix, getenv: os.Getenv}
+}
+
+// Credential - returns the value of the environment variable of the credential, empty variables are not found
+func (store *EnvStore) Credential(_ context.Context, name string) ([]byte, error) {
+	variable := store.Prefix + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	value := store.getenv(variable)
+	if value == "" {
+		return nil, fmt.Errorf("%w: environment variable %s is not set", ErrNotFound, variable)
+	}
+	return []byte(value), nil
+}
+
+// FileStore - reads credentials from the files named like the credentials in a directory, like mounted kubernetes
+// secrets. Files are read on every call, so rotated secrets are picked up. Surrounding white space is trimmed
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore - returns a store reading credentials from the files of a directory
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// Credential - returns the content of the file of the credential, names leaving the directory are rejected
+func (store *FileStore) Credential(_ context.Context, name string) ([]byte, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid credential name %q", name)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(store.Dir, name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credential %s. error: %w", name, err)
+	}
+	return []byte(strings.TrimSpace(string(content))), nil
+}
+
+// Chain - returns a store returning the credential of the first store holding it, like environment variables
+// overriding the files of mounted secrets. Errors other than ErrNotFound are returned right away
+func Chain(stores ...CredentialStore) CredentialStore {
+	stores = append([]CredentialStore(nil), stores...)
+	return StoreFunc(func(ctx context.Context, name string) ([]byte, error) {
+		for _, store := range stores {
+			credential, err := store.Credential(ctx, name)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return credential, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	})
+}
@@ -0,0 +1,72 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnvStore - tests credentials are read from the environment variables of the prefix
+func TestEnvStore(t *testing.T) {
+	check := assert.New(t)
+	store := NewEnvStore("ACCOUNTLIB_")
+	variables := map[string]string{"ACCOUNTLIB_TOKEN": "secret", "ACCOUNTLIB_SIGNING_KEY_2021": "key"}
+	store.getenv = func(name string) string { return variables[name] }
+	credential, err := store.Credential(context.Background(), TokenName)
+	check.Nil(err)
+	check.Equal("secret", string(credential))
+	credential, err = store.Credential(context.Background(), "signing-key.2021")
+	check.Nil(err)
+	check.Equal("key", string(credential))
+	_, err = store.Credential(context.Background(), "missing")
+	check.True(errors.Is(err, ErrNotFound))
+}
+
+// TestFileStore - tests credentials are read from the files of a directory and names leaving it are rejected
+func TestFileStore(t *testing.T) {
+	check := assert.New(t)
+	dir, err := ioutil.TempDir("", "credentials")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	check.Nil(ioutil.WriteFile(filepath.Join(dir, TokenName), []byte("secret\n"), 0600))
+	store := NewFileStore(dir)
+
+	credential, err := store.Credential(context.Background(), TokenName)
+	check.Nil(err)
+	check.Equal("secret", string(credential))
+	_, err = store.Credential(context.Background(), "missing")
+	check.True(errors.Is(err, ErrNotFound))
+	for _, name := range []string{"", "..", "../token", `sub\token`} {
+		_, err = store.Credential(context.Background(), name)
+		check.NotNil(err, name)
+		check.False(errors.Is(err, ErrNotFound), name)
+	}
+}
+
+// TestChain - tests the first store holding a credential wins and other errors are returned right away
+func TestChain(t *testing.T) {
+	check := assert.New(t)
+	failing := StoreFunc(func(ctx context.Context, name string) ([]byte, error) {
+		if name == "broken" {
+			return nil, errors.New("vault is sealed")
+		}
+		return nil, ErrNotFound
+	})
+	fallback := StoreFunc(func(ctx context.Context, name string) ([]byte, error) {
+		return []byte("fallback " + name), nil
+	})
+	store := Chain(failing, fallback)
+
+	credential, err := store.Credential(context.Background(), TokenName)
+	check.Nil(err)
+	check.Equal("fallback token", string(credential))
+	_, err = store.Credential(context.Background(), "broken")
+	check.EqualError(err, "vault is sealed")
+	_, err = Chain(failing).Credential(context.Background(), TokenName)
+	check.True(errors.Is(err, ErrNotFound))
+}
@@ -0,0 +1,185 @@
+package accountlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// timeType - type of timestamps, which are compared by instant
+var timeType = reflect.TypeOf(time.Time{})
+
+// FieldChange - describes a field which differs between two accounts
+// Path is the json path of the field (e.g. attributes.country), Old and New hold the dereferenced values
+// and are nil when the field is not set
+type FieldChange struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// Equal - reports whether both accounts hold the same values
+func (data *AccountData) Equal(other *AccountData) bool {
+	return len(Diff(data, other)) == 0
+}
+
+// Diff - compares two accounts attribute by attribute and returns the changed fields
+// Unset pointers and empty slices are treated as equal to their zero values
+func Diff(a, b *AccountData) []FieldChange {
+	if a == nil {
+		a = &AccountData{}
+	}
+	if b == nil {
+		b = &AccountData{}
+	}
+	return diffValues("", reflect.ValueOf(*a), reflect.ValueOf(*b), nil)
+}
+
+// diffValues - appends the changes between two values of the same type
+func diffValues(path string, old, new reflect.Value, changes []FieldChange) []FieldChange {
+	switch old.Kind() {
+	case reflect.Ptr:
+		if old.IsNil() && new.IsNil() {
+			return changes
+		}
+		if old.Type().Elem() == timeType {
+			if old.IsNil() || new.IsNil() || !old.Interface().(*time.Time).Equal(*new.Interface().(*time.Time)) {
+				changes = append(changes, FieldChange{Path: path, Old: derefInterface(old), New: derefInterface(new)})
+			}
+			return changes
+		}
+		if old.Type().Elem().Kind() == reflect.Struct {
+			return diffValues(path, derefOrZero(old), derefOrZero(new), changes)
+		}
+		if old.IsNil() || new.IsNil() || !reflect.DeepEqual(old.Elem().Interface(), new.Elem().Interface()) {
+			changes = append(changes, FieldChange{Path: path, Old: derefInterface(old), New: derefInterface(new)})
+		}
+	case reflect.Struct:
+		for i := 0; i < old.NumField(); i++ {
+			field := old.Type().Field(i)
+			if field.PkgPath != "" {
+				// unexported fields hold decoding state, not account values
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				// fields excluded from json, like Extra, are flattened into the parent path
+				name = ""
+			}
+			if name == "links" {
+				// links point to the account, they are no part of it
+				continue
+			}
+			changes = diffValues(joinPath(path, name), old.Field(i), new.Field(i), changes)
+		}
+	case reflect.Map:
+		changes = diffRawMessages(path, rawMessages(old), rawMessages(new), changes)
+	case reflect.Slice:
+		if old.Len() == 0 && new.Len() == 0 {
+			return changes
+		}
+		if !reflect.DeepEqual(old.Interface(), new.Interface()) {
+			changes = append(changes, FieldChange{Path: path, Old: sliceInterface(old), New: sliceInterface(new)})
+		}
+	default:
+		if !reflect.DeepEqual(old.Interface(), new.Interface()) {
+			changes = append(changes, FieldChange{Path: path, Old: old.Interface(), New: new.Interface()})
+		}
+	}
+	return changes
+}
+
+// diffRawMessages - appends the changes between two maps of raw json values
+func diffRawMessages(path string, old, new map[string]json.RawMessage, changes []FieldChange) []FieldChange {
+	keys := make([]string, 0, len(old)+len(new))
+	for key := range old {
+		keys = append(keys, key)
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		oldValue, oldOk := old[key]
+		newValue, newOk := new[key]
+		if oldOk && newOk && jsonEqual(oldValue, newValue) {
+			continue
+		}
+		change := FieldChange{Path: joinPath(path, key)}
+		if oldOk {
+			change.Old = oldValue
+		}
+		if newOk {
+			change.New = newValue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// rawMessages - returns a map as map of raw json values, encoding the values of other maps like relationships
+func rawMessages(value reflect.Value) map[string]json.RawMessage {
+	if raw, ok := value.Interface().(map[string]json.RawMessage); ok {
+		return raw
+	}
+	if value.Len() == 0 {
+		return nil
+	}
+	raw := make(map[string]json.RawMessage, value.Len())
+	iterator := value.MapRange()
+	for iterator.Next() {
+		encoded, _ := json.Marshal(iterator.Value().Interface())
+		raw[iterator.Key().String()] = encoded
+	}
+	return raw
+}
+
+// jsonEqual - reports whether two raw json values are equal ignoring insignificant whitespace
+func jsonEqual(a, b json.RawMessage) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
+
+// derefOrZero - returns the value a pointer points to, or the zero value for nil pointers
+func derefOrZero(value reflect.Value) reflect.Value {
+	if value.IsNil() {
+		return reflect.Zero(value.Type().Elem())
+	}
+	return value.Elem()
+}
+
+// derefInterface - returns the value a pointer points to, or nil for nil pointers
+func derefInterface(value reflect.Value) interface{} {
+	if value.IsNil() {
+		return nil
+	}
+	return value.Elem().Interface()
+}
+
+// sliceInterface - returns the slice value, or nil for empty slices
+func sliceInterface(value reflect.Value) interface{} {
+	if value.Len() == 0 {
+		return nil
+	}
+	return value.Interface()
+}
+
+// joinPath - joins json path segments
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	if name == "" {
+		return path
+	}
+	return path + "." + name
+}
@@ -0,0 +1,56 @@
+package accountlib
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiffEqualAccounts - tests equal accounts, including separately allocated pointers, have no changes
+func TestDiffEqualAccounts(t *testing.T) {
+	check := assert.New(t)
+	countryA, countryB := CountryCode("GB"), CountryCode("GB")
+	a := &AccountData{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3", Attributes: &AccountAttributes{Country: &countryA}}
+	b := &AccountData{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3", Attributes: &AccountAttributes{Country: &countryB, Name: []string{}}}
+
+	check.Empty(Diff(a, b))
+	check.True(a.Equal(b))
+}
+
+// TestDiffChangedFields - tests changed fields are reported with their json paths
+func TestDiffChangedFields(t *testing.T) {
+	check := assert.New(t)
+	countryA, countryB := CountryCode("GB"), CountryCode("FR")
+	version := int64(1)
+	a := &AccountData{
+		Attributes: &AccountAttributes{Country: &countryA, Name: []string{"Samantha Holder"}},
+	}
+	b := &AccountData{
+		Version: &version,
+		Attributes: &AccountAttributes{
+			Country: &countryB,
+			Name:    []string{"Sam Holder"},
+			Extra:   map[string]json.RawMessage{"new_field": json.RawMessage(`"value"`)},
+		},
+	}
+
+	changes := Diff(a, b)
+	check.Equal([]FieldChange{
+		{Path: "attributes.country", Old: CountryCode("GB"), New: CountryCode("FR")},
+		{Path: "attributes.name", Old: []string{"Samantha Holder"}, New: []string{"Sam Holder"}},
+		{Path: "attributes.new_field", Old: nil, New: json.RawMessage(`"value"`)},
+		{Path: "version", Old: nil, New: int64(1)},
+	}, changes)
+	check.False(a.Equal(b))
+}
+
+// TestDiffNilAttributes - tests nil attributes are compared as empty attributes
+func TestDiffNilAttributes(t *testing.T) {
+	check := assert.New(t)
+	bankID := "400300"
+	b := &AccountData{Attributes: &AccountAttributes{BankID: bankID}}
+
+	check.Equal([]FieldChange{{Path: "attributes.bank_id", Old: "", New: bankID}}, Diff(&AccountData{}, b))
+	check.Empty(Diff(nil, &AccountData{Attributes: &AccountAttributes{}}))
+}
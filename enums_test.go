@@ -0,0 +1,41 @@
+package accountlib
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnumsValid - tests valid enum values
+func TestEnumsValid(t *testing.T) {
+	check := assert.New(t)
+	check.True(AccountClassificationBusiness.Valid())
+	check.True(AccountStatusConfirmed.Valid())
+	check.True(CountryCode("GB").Valid())
+	check.True(CurrencyCode("GBP").Valid())
+}
+
+// TestEnumsInvalid - tests typos and lower case codes are rejected
+func TestEnumsInvalid(t *testing.T) {
+	check := assert.New(t)
+	check.False(AccountClassification("personel").Valid())
+	check.False(AccountStatus("open").Valid())
+	check.False(CountryCode("gb").Valid())
+	check.False(CountryCode("UK").Valid())
+	check.False(CurrencyCode("GBR").Valid())
+}
+
+// TestEnumsJSON - tests typed values keep their json representation
+func TestEnumsJSON(t *testing.T) {
+	check := assert.New(t)
+	classification := AccountClassificationPersonal
+	country := CountryCode("GB")
+	encoded, err := json.Marshal(&AccountCreateAttributes{
+		AccountClassification: &classification,
+		Country:               &country,
+		BaseCurrency:          "GBP",
+	})
+	check.Equal(err, nil)
+	check.JSONEq(string(encoded), `{"account_classification":"Personal","country":"GB","base_currency":"GBP"}`)
+}
@@ -0,0 +1,87 @@
+package accountlib
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// environment variables configuring a client
+const (
+	EnvBaseURL            = "ACCOUNTLIB_BASE_URL"
+	EnvTimeout            = "ACCOUNTLIB_TIMEOUT"
+	EnvRetryCount         = "ACCOUNTLIB_RETRY_COUNT"
+	EnvToken              = "ACCOUNTLIB_TOKEN"
+	EnvOrganisationID     = "ACCOUNTLIB_ORGANISATION_ID"
+	EnvStrictDecoding     = "ACCOUNTLIB_STRICT_DECODING"
+	EnvValidateBeforeSend = "ACCOUNTLIB_VALIDATE_BEFORE_SEND"
+	EnvProfile            = "ACCOUNTLIB_PROFILE"
+)
+
+// NewClientFromEnv - creates a new account client configured from the ACCOUNTLIB_* environment variables
+func NewClientFromEnv() (*Client, error) {
+	options, err := ClientOptionsFromEnv(nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(options), nil
+}
+
+// ClientOptionsFromEnv - returns client options read from the ACCOUNTLIB_* environment variables
+// getenv looks the variables up, nil uses os.Getenv. Unset variables keep the defaults of the client
+// ACCOUNTLIB_TIMEOUT takes a duration like 5s or a number of seconds, the boolean variables take true or false
+func ClientOptionsFromEnv(getenv func(string) string) (*ClientOptions, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	return mergeEnv(&ClientOptions{}, getenv)
+}
+
+// mergeEnv - overrides options with the ACCOUNTLIB_* environment variables which are set
+func mergeEnv(options *ClientOptions, getenv func(string) string) (*ClientOptions, error) {
+	if value := getenv(EnvBaseURL); value != "" {
+		options.BaseURL = value
+	}
+	if value := getenv(EnvToken); value != "" {
+		options.Token = value
+	}
+	if value := getenv(EnvOrganisationID); value != "" {
+		options.OrganisationID = value
+	}
+
+	var err error
+	if value := getenv(EnvTimeout); value != "" {
+		if options.Timeout, err = ParseTimeout(value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", EnvTimeout, err.Error())
+		}
+	}
+	if value := getenv(EnvRetryCount); value != "" {
+		if options.RetryCount, err = strconv.Atoi(value); err != nil || options.RetryCount < 0 {
+			return nil, fmt.Errorf("invalid %s: %q is not a non-negative number", EnvRetryCount, value)
+		}
+	}
+	if value := getenv(EnvStrictDecoding); value != "" {
+		if options.StrictDecoding, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %q is not a boolean", EnvStrictDecoding, value)
+		}
+	}
+	if value := getenv(EnvValidateBeforeSend); value != "" {
+		if options.ValidateBeforeSend, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %q is not a boolean", EnvValidateBeforeSend, value)
+		}
+	}
+	return options, nil
+}
+
+// ParseTimeout - parses a timeout given as duration like 5s or as a number of seconds
+func ParseTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("%q is not a positive duration", value)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,105 @@
+package accountlib
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/httprequest"
+)
+
+// TestClientOptionsFromEnv - tests every variable is read into the options
+func TestClientOptionsFromEnv(t *testing.T) {
+	check := assert.New(t)
+	variables := map[string]string{
+		EnvBaseURL:            "http://accounts.internal",
+		EnvTimeout:            "1500ms",
+		EnvRetryCount:         "5",
+		EnvToken:              "secret",
+		EnvOrganisationID:     "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+		EnvStrictDecoding:     "true",
+		EnvValidateBeforeSend: "1",
+	}
+
+	options, err := ClientOptionsFromEnv(func(name string) string { return variables[name] })
+	check.Nil(err)
+	check.Equal(&ClientOptions{
+		BaseURL:            "http://accounts.internal",
+		Timeout:            1500 * time.Millisecond,
+		RetryCount:         5,
+		Token:              "secret",
+		OrganisationID:     "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+		StrictDecoding:     true,
+		ValidateBeforeSend: true,
+	}, options)
+}
+
+// TestClientOptionsFromEnvUnset - tests unset variables keep the defaults
+func TestClientOptionsFromEnvUnset(t *testing.T) {
+	check := assert.New(t)
+	options, err := ClientOptionsFromEnv(func(string) string { return "" })
+	check.Nil(err)
+	check.Equal(&ClientOptions{}, options)
+}
+
+// TestClientOptionsFromEnvInvalid - tests invalid values name the variable
+func TestClientOptionsFromEnvInvalid(t *testing.T) {
+	check := assert.New(t)
+	for name, value := range map[string]string{
+		EnvTimeout:            "soon",
+		EnvRetryCount:         "-1",
+		EnvStrictDecoding:     "maybe",
+		EnvValidateBeforeSend: "yes please",
+	} {
+		_, err := ClientOptionsFromEnv(func(lookup string) string {
+			if lookup == name {
+				return value
+			}
+			return ""
+		})
+		check.Contains(err.Error(), "invalid "+name, name)
+	}
+	_, err := ClientOptionsFromEnv(func(lookup string) string {
+		return map[string]string{EnvRetryCount: "-1"}[lookup]
+	})
+	check.EqualError(err, `invalid ACCOUNTLIB_RETRY_COUNT: "-1" is not a non-negative number`)
+}
+
+// TestParseTimeout - tests timeouts given as durations and as seconds
+func TestParseTimeout(t *testing.T) {
+	check := assert.New(t)
+	timeout, err := ParseTimeout("7")
+	check.Nil(err)
+	check.Equal(7*time.Second, timeout)
+
+	timeout, err = ParseTimeout("250ms")
+	check.Nil(err)
+	check.Equal(250*time.Millisecond, timeout)
+
+	_, err = ParseTimeout("0")
+	check.NotNil(err)
+}
+
+// TestNewClientTimeoutRetryToken - tests the timeout, retry count and token options
+func TestNewClientTimeoutRetryToken(t *testing.T) {
+	check := assert.New(t)
+	httpClient := &http.Client{Timeout: time.Second}
+	client := NewClient(&ClientOptions{HTTPClient: httpClient, Timeout: 3 * time.Second, RetryCount: 2, Token: "secret"})
+
+	check.Equal(3*time.Second, client.handler.(*httprequest.RequestHandler).HTTPClient.Timeout)
+	check.Equal(time.Second, httpClient.Timeout)
+
+	specs := client.requestSpecifications(context.Background(), OperationFetch, http.MethodGet, "http://localhost:8080", nil)
+	check.Equal(2, specs.RetryCount)
+	check.Equal("Bearer secret", specs.Headers.Get("Authorization"))
+}
+
+// TestNewClientDisableRetries - tests disabled retries are passed to every request
+func TestNewClientDisableRetries(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(&ClientOptions{DisableRetries: true})
+	check.True(client.requestSpecifications(context.Background(), OperationFetch, http.MethodGet, "http://localhost:8080", nil).DisableRetries)
+}
@@ -1,32 +1,472 @@
 package accounterrors
 
 import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"accountlib/redact"
 )
 
+// ErrInvalidID - matches errors returned for ids which are not well formed uuids
+var ErrInvalidID = errors.New("invalid id")
+
+// ErrTimeout - matches errors returned when a request timed out on the client side
+var ErrTimeout = errors.New("timeout")
+
+// ErrReadOnlyClient - returned by read only clients for requests changing data, like creates, updates and deletes
+var ErrReadOnlyClient = errors.New("client is read only")
+
+// ErrClientShutdown - returned for operations started after the client was shut down
+var ErrClientShutdown = errors.New("client is shut down")
+
+// ErrPanic - matches errors of requests which panicked, like in a custom round tripper or request observer
+var ErrPanic = errors.New("request panicked")
+
+// ErrInvalidSpecifications - matches errors of requests with invalid specifications, which were not sent
+var ErrInvalidSpecifications = errors.New("invalid request specifications")
+
+// ErrBadSignature - matches errors of responses whose signature or digest does not verify, and of unsigned responses
+// when signatures are required
+var ErrBadSignature = errors.New("bad response signature")
+
+// ErrClockSkew - matches errors of requests whose response shows a clock skew beyond the maximum of the client
+var ErrClockSkew = errors.New("clock skew")
+
+// ErrQueued - matches errors of mutations which failed and were queued for replay with Drain
+var ErrQueued = errors.New("mutation queued")
+
 // errorMap - holds error message for respective status code
 var errorMap = map[int]string{
-	http.StatusBadRequest:          "bad request",
-	http.StatusUnauthorized:        "unauthorized",
-	http.StatusForbidden:           "forbidden",
-	http.StatusNotFound:            "resource not found",
-	http.StatusMethodNotAllowed:    "incorrect http method",
-	http.StatusNotAcceptable:       "incorrect content type",
-	http.StatusConflict:            "request conflict",
-	http.StatusTooManyRequests:     "too many requests",
-	http.StatusInternalServerError: "internal server error",
-	http.StatusBadGateway:          "bad gateway",
-	http.StatusServiceUnavailable:  "service unavailable",
-	http.StatusGatewayTimeout:      "gateway timeout",
-}
-
-// handleErrorStatusCode - returns an error based on the status code
+	http.StatusBadRequest:            "bad request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "resource not found",
+	http.StatusMethodNotAllowed:      "incorrect http method",
+	http.StatusNotAcceptable:         "incorrect content type",
+	http.StatusRequestTimeout:        "request timeout",
+	http.StatusConflict:              "request conflict",
+	http.StatusGone:                  "resource gone",
+	http.StatusPreconditionFailed:    "precondition failed",
+	http.StatusRequestEntityTooLarge: "request too large",
+	http.StatusUnsupportedMediaType:  "unsupported media type",
+	http.StatusUnprocessableEntity:   "unprocessable entity",
+	http.StatusTooManyRequests:       "too many requests",
+	http.StatusInternalServerError:   "internal server error",
+	http.StatusNotImplemented:        "not implemented",
+	http.StatusBadGateway:            "bad gateway",
+	http.StatusServiceUnavailable:    "service unavailable",
+	http.StatusGatewayTimeout:        "gateway timeout",
+}
+
+// retryableStatusCodes - status codes of responses which are sent again by the retry loop
+var retryableStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusGatewayTimeout,
+	http.StatusServiceUnavailable,
+}
+
+// handleErrorStatusCode - returns a *StatusError based on the status code
+// Unprocessable entities and bad requests reporting invalid fields wrap a *ValidationError
 func HandleErrorStatusCode(statusCode int, response []byte) (err error) {
+	return (*StatusErrors)(nil).HandleErrorStatusCode(statusCode, response)
+}
+
+// defaultMaxBodySize - bytes of a response body included in error messages by default
+const defaultMaxBodySize = 1024
+
+// StatusErrors - builds the errors of responses with error status codes for a single client
+// Messages override or extend the default error messages by status code, without affecting other clients
+// MaxBodySize limits the bytes of the response body included in error messages, it defaults to 1024. Longer bodies
+// are truncated and binary bodies are left out
+type StatusErrors struct {
+	Messages    map[int]string
+	MaxBodySize int
+}
+
+// NewStatusErrors - returns status errors with a copy of the messages, so later changes of the map do not leak into them
+func NewStatusErrors(messages map[int]string) *StatusErrors {
+	statusErrors := &StatusErrors{Messages: make(map[int]string, len(messages))}
+	for statusCode, message := range messages {
+		statusErrors.Messages[statusCode] = message
+	}
+	return statusErrors
+}
+
+// HandleErrorStatusCode - returns a *StatusError based on the status code, using the messages of the status errors
+// Unprocessable entities and bad requests reporting invalid fields wrap a *ValidationError. Personal data of the
+// response is scrubbed with redact.Scrub, since error messages flow into logs and tickets
+func (statusErrors *StatusErrors) HandleErrorStatusCode(statusCode int, response []byte) (err error) {
+	errMsg := statusErrors.message(statusCode)
+	if !binaryBody(response) {
+		response = redact.Scrub(response)
+	}
+	if statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity {
+		if fieldErrors := parseFieldErrors(response); len(fieldErrors) > 0 {
+			return &StatusError{StatusCode: statusCode, Err: NewValidationError(errMsg, fieldErrors)}
+		}
+	}
+	if statusCode == http.StatusUnprocessableEntity {
+		return &StatusError{StatusCode: statusCode, Err: &ValidationError{Message: fmt.Sprintf("%s: %s", errMsg, statusErrors.body(response))}}
+	}
+	return &StatusError{StatusCode: statusCode, Err: fmt.Errorf("%s: %s", errMsg, statusErrors.body(response))}
+}
+
+// body - returns the response body for an error message, truncated to the maximum body size
+// Binary bodies are replaced by their size
+func (statusErrors *StatusErrors) body(response []byte) string {
+	if binaryBody(response) {
+		return fmt.Sprintf("<%d bytes of binary content>", len(response))
+	}
+	maxBodySize := defaultMaxBodySize
+	if statusErrors != nil && statusErrors.MaxBodySize > 0 {
+		maxBodySize = statusErrors.MaxBodySize
+	}
+	if len(response) <= maxBodySize {
+		return string(response)
+	}
+	cut := maxBodySize
+	for cut > 0 && !utf8.RuneStart(response[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s…truncated (%d bytes)", response[:cut], len(response))
+}
+
+// binaryBody - reports whether a response body is not text, which is the case for invalid utf-8 and nul bytes
+func binaryBody(response []byte) bool {
+	return !utf8.Valid(response) || bytes.IndexByte(response, 0) >= 0
+}
+
+// message - returns the error message of a status code, statuses without message are internal errors
+func (statusErrors *StatusErrors) message(statusCode int) string {
+	if statusErrors != nil {
+		if errMsg, ok := statusErrors.Messages[statusCode]; ok {
+			return errMsg
+		}
+	}
 	if errMsg, ok := errorMap[statusCode]; ok {
-		err = fmt.Errorf("%s: %s", errMsg, string(response))
-	} else {
-		err = fmt.Errorf("internal error: %s", string(response))
+		return errMsg
+	}
+	return "internal error"
+}
+
+// StatusError - returned for requests the api answered with an error status code
+// Err describes the error of the response, like a *ValidationError for bad requests reporting invalid fields
+type StatusError struct {
+	StatusCode int
+	Err        error
+}
+
+// Error - returns the error message of the response
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap - returns the error of the response
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode - returns the status code of the response which caused an error of the client
+// Errors of requests without response, like transport errors or invalid input, return false
+func StatusCode(err error) (int, bool) {
+	var statusError *StatusError
+	if !errors.As(err, &statusError) {
+		return 0, false
+	}
+	return statusError.StatusCode, true
+}
+
+// IsRetryableStatusCode - reports whether the retry loop sends requests answered with the status code again
+func IsRetryableStatusCode(statusCode int) bool {
+	for _, retryableStatusCode := range retryableStatusCodes {
+		if statusCode == retryableStatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldError - describes a problem with a single request field
+// Field is the dotted path of the field, like attributes.bank_id, Pointer the json pointer of the field in the
+// request body, like /data/attributes/bank_id, and Code classifies the problem
+type FieldError struct {
+	Field   string
+	Message string
+	Pointer string
+	Code    string
+}
+
+// Error - returns the field error message
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// InvalidIDError - returned when an account or organisation id is not a well formed uuid
+type InvalidIDError struct {
+	Field string
+	Value string
+}
+
+// Error - returns the invalid id error message
+func (e *InvalidIDError) Error() string {
+	return fmt.Sprintf("invalid %s: %q", e.Field, e.Value)
+}
+
+// Is - makes the error match ErrInvalidID
+func (e *InvalidIDError) Is(target error) bool {
+	return target == ErrInvalidID
+}
+
+// TimeoutError - returned when a request timed out on the client side, before the server answered
+// A 408 status code of the server is not a TimeoutError, it is handled like every other status code
+type TimeoutError struct {
+	Err error
+}
+
+// Error - returns the timeout error message
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timeout encountered. error: %s", e.Err.Error())
+}
+
+// Timeout - reports that the error is a timeout, like net.Error does
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// Is - makes the error match ErrTimeout
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// Unwrap - returns the error of the http client
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// IsTemporary - reports whether a transport error is expected to go away when the request is sent again
+// Timeouts, refused, reset and aborted connections and temporary dns failures are temporary. Unknown hosts,
+// tls certificate errors, bad response signatures, clock skew, canceled contexts and panics are permanent. Other errors are
+// treated as temporary
+func IsTemporary(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrPanic) || errors.Is(err, ErrBadSignature) ||
+		errors.Is(err, ErrClockSkew) {
+		return false
+	}
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var dnsError *net.DNSError
+	if errors.As(err, &dnsError) {
+		return dnsError.IsTimeout || dnsError.IsTemporary
+	}
+	for _, temporary := range []error{syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ECONNABORTED, syscall.EPIPE, io.ErrUnexpectedEOF, io.EOF} {
+		if errors.Is(err, temporary) {
+			return true
+		}
+	}
+	var unknownAuthorityError x509.UnknownAuthorityError
+	var hostnameError x509.HostnameError
+	var certificateInvalidError x509.CertificateInvalidError
+	if errors.As(err, &unknownAuthorityError) || errors.As(err, &hostnameError) || errors.As(err, &certificateInvalidError) {
+		return false
+	}
+	return true
+}
+
+// IsRetryable - reports whether an error of the client is expected to go away when the operation is run again, using
+// the classification of the default retry loop, for retries above the client like across job runs. Exhausted retries,
+// retryable status codes, 429 and temporary transport errors are retryable. Queued mutations, invalid input, read only
+// and shut down clients and all other status codes are not. Runs of non idempotent operations failing ambiguously,
+// like creates timing out, may have been processed by the api, use an idempotency key before running them again
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrQueued) {
+		return false
+	}
+	var retriesExhaustedError *RetriesExhaustedError
+	if errors.As(err, &retriesExhaustedError) {
+		return true
+	}
+	var statusError *StatusError
+	if errors.As(err, &statusError) {
+		return statusError.StatusCode == http.StatusTooManyRequests || IsRetryableStatusCode(statusError.StatusCode)
+	}
+	var validationError *ValidationError
+	if errors.As(err, &validationError) {
+		return false
+	}
+	for _, permanent := range []error{ErrInvalidID, ErrInvalidSpecifications, ErrReadOnlyClient, ErrClientShutdown} {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+	return IsTemporary(err)
+}
+
+// Attempt - outcome of a single attempt of a request, Err is set when no response was received
+type Attempt struct {
+	StatusCode int
+	Err        error
+}
+
+// RetriesExhaustedError - returned when every attempt of a request failed with a retryable outcome
+// Attempts hold the outcome of every attempt, Err the error of the last one
+type RetriesExhaustedError struct {
+	Attempts []Attempt
+	Err      error
+}
+
+// Error - returns the error message with the outcome of every attempt
+func (e *RetriesExhaustedError) Error() string {
+	outcomes := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		outcomes[i] = strconv.Itoa(attempt.StatusCode)
+		if attempt.Err != nil {
+			outcomes[i] = "error"
+		}
+	}
+	return fmt.Sprintf("request failed after %d attempts (%s). error: %s", len(e.Attempts), strings.Join(outcomes, ", "), e.Err.Error())
+}
+
+// Unwrap - returns the error of the last attempt
+func (e *RetriesExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// OperationError - wraps the error of a request with the operation it failed in
+// URL is sanitized, it holds neither user info nor query values
+type OperationError struct {
+	Operation string
+	Method    string
+	URL       string
+	Elapsed   time.Duration
+	Err       error
+}
+
+// Error - returns the error message prefixed with the operation
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("%s %s %s failed after %s. error: %s", e.Operation, e.Method, e.URL, e.Elapsed.Round(time.Millisecond), e.Err.Error())
+}
+
+// Unwrap - returns the error of the request
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// SpecificationError - returned for requests with invalid specifications before they are sent
+// Field names the invalid field of the specifications, Message describes the problem
+type SpecificationError struct {
+	Field   string
+	Message string
+}
+
+// Error - returns the error message with the invalid field
+func (e *SpecificationError) Error() string {
+	return fmt.Sprintf("invalid request specifications: %s %s", e.Field, e.Message)
+}
+
+// Is - makes the error match ErrInvalidSpecifications
+func (e *SpecificationError) Is(target error) bool {
+	return target == ErrInvalidSpecifications
+}
+
+// MaxRecordsExceededError - returned by ListAll when the accounts to list exceed the maximum record count of the client
+type MaxRecordsExceededError struct {
+	MaxRecords int
+}
+
+// Error - returns the error message with the maximum record count
+func (e *MaxRecordsExceededError) Error() string {
+	return fmt.Sprintf("list exceeds the maximum of %d records, narrow the filters or raise the maximum", e.MaxRecords)
+}
+
+// QueuedError - returned when a mutation failed with a retryable error and was queued for replay with Drain
+// QueueID identifies the queued mutation, Err is the error of the mutation
+type QueuedError struct {
+	QueueID string
+	Err     error
+}
+
+// Error - returns the error message with the queue id
+func (e *QueuedError) Error() string {
+	return fmt.Sprintf("mutation queued as %s for replay. error: %s", e.QueueID, e.Err.Error())
+}
+
+// Is - makes the error match ErrQueued
+func (e *QueuedError) Is(target error) bool {
+	return target == ErrQueued
+}
+
+// Unwrap - returns the error of the mutation
+func (e *QueuedError) Unwrap() error {
+	return e.Err
+}
+
+// SignatureError - returned for responses failing signature verification, KeyID names the key of the signature
+// when the response is signed and Reason describes the failure
+type SignatureError struct {
+	KeyID  string
+	Reason string
+}
+
+// Error - returns the error message with the reason of the failure
+func (e *SignatureError) Error() string {
+	if e.KeyID == "" {
+		return fmt.Sprintf("bad response signature: %s", e.Reason)
 	}
+	return fmt.Sprintf("bad response signature of key %s: %s", e.KeyID, e.Reason)
+}
+
+// Is - makes the error match ErrBadSignature
+func (e *SignatureError) Is(target error) bool {
+	return target == ErrBadSignature
+}
+
+// ClockSkewError - returned when the Date header of a response differs from the local clock by more than Max
+// A positive Skew means the clock of the api is ahead of the local clock
+type ClockSkewError struct {
+	Skew time.Duration
+	Max  time.Duration
+}
+
+// Error - returns the error message with the measured skew
+func (e *ClockSkewError) Error() string {
+	return fmt.Sprintf("clock skew of %s to the api exceeds %s", e.Skew, e.Max)
+}
+
+// Is - makes the error match ErrClockSkew
+func (e *ClockSkewError) Is(target error) bool {
+	return target == ErrClockSkew
+}
+
+// PanicError - returned when a request panicked, Value is the value passed to panic and Stack the stack of the panic
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error - returns the error message with the panic value, the stack is left out
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("request panicked: %v", e.Value)
+}
+
+// Is - makes the error match ErrPanic
+func (e *PanicError) Is(target error) bool {
+	return target == ErrPanic
+}
+
+// Unwrap - returns the panic value when it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
 	return err
 }
@@ -1,8 +1,18 @@
 package accounterrors
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -34,3 +44,193 @@ func TestEmptyResponse(t *testing.T) {
 	err := HandleErrorStatusCode(0, []byte(``))
 	check.Contains(err.Error(), "internal error")
 }
+
+// TestInvalidIDError - test if an invalid id error matches ErrInvalidID
+func TestInvalidIDError(t *testing.T) {
+	check := assert.New(t)
+	var err error = &InvalidIDError{Field: "account id", Value: "57f6-465c"}
+	check.True(errors.Is(err, ErrInvalidID))
+	check.Equal(err.Error(), `invalid account id: "57f6-465c"`)
+}
+
+// TestRetriesExhaustedError - tests the message lists every attempt and the last error is unwrapped
+func TestRetriesExhaustedError(t *testing.T) {
+	check := assert.New(t)
+	last := HandleErrorStatusCode(http.StatusServiceUnavailable, []byte(`{}`))
+	err := &RetriesExhaustedError{
+		Attempts: []Attempt{{StatusCode: http.StatusGatewayTimeout}, {Err: errors.New("connection reset")}, {StatusCode: http.StatusServiceUnavailable}},
+		Err:      last,
+	}
+	check.Equal("request failed after 3 attempts (504, error, 503). error: service unavailable: {}", err.Error())
+	check.True(errors.Is(err, last))
+}
+
+// TestOperationError - tests the message is prefixed with the operation and the request error is unwrapped
+func TestOperationError(t *testing.T) {
+	check := assert.New(t)
+	requestError := HandleErrorStatusCode(http.StatusNotFound, nil)
+	err := &OperationError{Operation: "fetch", Method: http.MethodGet, URL: "http://localhost:8080/v1/organisation/accounts/1", Elapsed: 1500 * time.Microsecond, Err: requestError}
+	check.Equal("fetch GET http://localhost:8080/v1/organisation/accounts/1 failed after 2ms. error: resource not found: ", err.Error())
+	check.True(errors.Is(err, requestError))
+}
+
+// TestIsTemporary - tests transport errors are classified as temporary or permanent
+func TestIsTemporary(t *testing.T) {
+	check := assert.New(t)
+	refused := &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	reset := &url.Error{Op: "Get", Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}
+	for err, temporary := range map[error]bool{
+		&TimeoutError{Err: errors.New("deadline")}:               true,
+		fmt.Errorf("failed to send request. Error: %w", refused): true,
+		reset: true,
+		&net.DNSError{Err: "server misbehaving", IsTemporary: true}:      true,
+		errors.New("unclassified"):                                       true,
+		&net.DNSError{Err: "no such host", IsNotFound: true}:             false,
+		&url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}:         false,
+		&url.Error{Op: "Get", Err: x509.HostnameError{Host: "accounts"}}: false,
+		fmt.Errorf("request canceled. error: %w", context.Canceled):      false,
+		nil: false,
+	} {
+		check.Equal(temporary, IsTemporary(err), fmt.Sprint(err))
+	}
+}
+
+// TestQueuedError - tests the error matches ErrQueued and unwraps the error of the mutation
+func TestQueuedError(t *testing.T) {
+	check := assert.New(t)
+	mutationError := HandleErrorStatusCode(http.StatusServiceUnavailable, nil)
+	err := &QueuedError{QueueID: "1", Err: mutationError}
+	check.Equal("mutation queued as 1 for replay. error: service unavailable: ", err.Error())
+	check.True(errors.Is(err, ErrQueued))
+	check.True(errors.Is(err, mutationError))
+}
+
+// TestPanicError - tests the error matches ErrPanic, unwraps error values and is permanent
+func TestPanicError(t *testing.T) {
+	check := assert.New(t)
+	value := errors.New("nil map")
+	err := &PanicError{Value: value, Stack: []byte("goroutine 1")}
+	check.Equal("request panicked: nil map", err.Error())
+	check.True(errors.Is(err, ErrPanic))
+	check.True(errors.Is(err, value))
+	check.False(IsTemporary(err))
+	check.Nil((&PanicError{Value: "boom"}).Unwrap())
+}
+
+// TestSpecificationError - tests the specification error message and its match with ErrInvalidSpecifications
+func TestSpecificationError(t *testing.T) {
+	check := assert.New(t)
+	err := &SpecificationError{Field: "URL", Message: "is empty"}
+	check.Equal("invalid request specifications: URL is empty", err.Error())
+	check.True(errors.Is(err, ErrInvalidSpecifications))
+}
+
+// TestIsRetryable - tests errors are classified like the retry loop classifies the outcome of attempts
+func TestIsRetryable(t *testing.T) {
+	check := assert.New(t)
+	retriesExhausted := &RetriesExhaustedError{Attempts: []Attempt{{StatusCode: http.StatusServiceUnavailable}}, Err: HandleErrorStatusCode(http.StatusServiceUnavailable, nil)}
+	for name, testCase := range map[string]struct {
+		err       error
+		retryable bool
+	}{
+		"nil":                 {nil, false},
+		"retries exhausted":   {&OperationError{Operation: "create", Err: retriesExhausted}, true},
+		"queued":              {&QueuedError{QueueID: "1", Err: retriesExhausted}, false},
+		"service unavailable": {HandleErrorStatusCode(http.StatusServiceUnavailable, nil), true},
+		"too many requests":   {HandleErrorStatusCode(http.StatusTooManyRequests, nil), true},
+		"gateway timeout":     {fmt.Errorf("wrapped: %w", HandleErrorStatusCode(http.StatusGatewayTimeout, nil)), true},
+		"not found":           {HandleErrorStatusCode(http.StatusNotFound, nil), false},
+		"internal error":      {HandleErrorStatusCode(http.StatusInternalServerError, nil), false},
+		"validation":          {NewValidationError("invalid create params", []FieldError{{Field: "id", Message: "is required"}}), false},
+		"invalid id":          {&InvalidIDError{Field: "account id", Value: "1"}, false},
+		"specifications":      {&SpecificationError{Field: "URL", Message: "is empty"}, false},
+		"read only":           {ErrReadOnlyClient, false},
+		"shut down":           {ErrClientShutdown, false},
+		"timeout":             {&TimeoutError{Err: context.DeadlineExceeded}, true},
+		"connection refused":  {fmt.Errorf("failed to send request. Error: %w", syscall.ECONNREFUSED), true},
+		"canceled":            {context.Canceled, false},
+	} {
+		check.Equal(testCase.retryable, IsRetryable(testCase.err), name)
+	}
+}
+
+// TestStatusCode - tests the status code is found in wrapped errors of responses
+func TestStatusCode(t *testing.T) {
+	check := assert.New(t)
+	statusCode, ok := StatusCode(&OperationError{Operation: "fetch", Err: HandleErrorStatusCode(http.StatusNotFound, nil)})
+	check.True(ok)
+	check.Equal(http.StatusNotFound, statusCode)
+
+	retriesExhausted := &RetriesExhaustedError{Attempts: []Attempt{{StatusCode: http.StatusServiceUnavailable}}, Err: HandleErrorStatusCode(http.StatusServiceUnavailable, nil)}
+	statusCode, ok = StatusCode(&QueuedError{QueueID: "1", Err: retriesExhausted})
+	check.True(ok)
+	check.Equal(http.StatusServiceUnavailable, statusCode)
+
+	statusCode, ok = StatusCode(HandleErrorStatusCode(http.StatusBadRequest, []byte(`{"error_message": "validation failure list:\nid in body is required"}`)))
+	check.True(ok)
+	check.Equal(http.StatusBadRequest, statusCode)
+
+	for _, err := range []error{nil, &TimeoutError{Err: context.DeadlineExceeded}, ErrReadOnlyClient, errors.New("bad request: 400")} {
+		statusCode, ok = StatusCode(err)
+		check.False(ok)
+		check.Equal(0, statusCode)
+	}
+}
+
+// TestMappedStatusCodes - tests the status codes emitted by the accounts api have their own error messages
+func TestMappedStatusCodes(t *testing.T) {
+	check := assert.New(t)
+	for statusCode, message := range map[int]string{
+		http.StatusRequestTimeout:        "request timeout",
+		http.StatusGone:                  "resource gone",
+		http.StatusPreconditionFailed:    "precondition failed",
+		http.StatusRequestEntityTooLarge: "request too large",
+		http.StatusUnsupportedMediaType:  "unsupported media type",
+		http.StatusNotImplemented:        "not implemented",
+	} {
+		err := HandleErrorStatusCode(statusCode, []byte(`{"error_message": "failed"}`))
+		check.EqualError(err, message+`: {"error_message": "failed"}`)
+		mapped, ok := StatusCode(err)
+		check.True(ok)
+		check.Equal(statusCode, mapped)
+	}
+}
+
+// TestStatusErrorsMessages - tests messages of status errors override and extend the default messages
+func TestStatusErrorsMessages(t *testing.T) {
+	check := assert.New(t)
+	messages := map[int]string{http.StatusUnavailableForLegalReasons: "unavailable for legal reasons", http.StatusNotFound: "account not found"}
+	statusErrors := NewStatusErrors(messages)
+	messages[http.StatusConflict] = "changed after creation"
+
+	check.EqualError(statusErrors.HandleErrorStatusCode(http.StatusUnavailableForLegalReasons, []byte("blocked")), "unavailable for legal reasons: blocked")
+	check.EqualError(statusErrors.HandleErrorStatusCode(http.StatusNotFound, nil), "account not found: ")
+	check.EqualError(statusErrors.HandleErrorStatusCode(http.StatusConflict, nil), "request conflict: ")
+	check.EqualError(HandleErrorStatusCode(http.StatusNotFound, nil), "resource not found: ")
+	check.EqualError(HandleErrorStatusCode(http.StatusUnavailableForLegalReasons, nil), "internal error: ")
+}
+
+// TestStatusErrorsBody - tests long bodies are truncated and binary bodies left out of error messages
+func TestStatusErrorsBody(t *testing.T) {
+	check := assert.New(t)
+	page := []byte("<html>" + strings.Repeat("x", 2<<20) + "</html>")
+	err := HandleErrorStatusCode(http.StatusBadGateway, page)
+	check.Equal("bad gateway: <html>"+strings.Repeat("x", 1018)+"…truncated (2097165 bytes)", err.Error())
+
+	statusErrors := &StatusErrors{MaxBodySize: 8}
+	check.EqualError(statusErrors.HandleErrorStatusCode(http.StatusBadGateway, page), "bad gateway: <html>xx…truncated (2097165 bytes)")
+	check.EqualError(statusErrors.HandleErrorStatusCode(http.StatusBadGateway, []byte("aööööö")), "bad gateway: aööö…truncated (11 bytes)")
+	check.EqualError(statusErrors.HandleErrorStatusCode(http.StatusBadGateway, []byte("short")), "bad gateway: short")
+	check.EqualError(statusErrors.HandleErrorStatusCode(http.StatusBadGateway, []byte{0x1f, 0x8b, 0x08, 0x00}), "bad gateway: <4 bytes of binary content>")
+	check.EqualError(statusErrors.HandleErrorStatusCode(http.StatusBadGateway, []byte{0xff, 0xfe, 'a'}), "bad gateway: <3 bytes of binary content>")
+}
+
+// TestStatusErrorsScrubbing - tests personal data of responses is scrubbed from error messages and field errors
+func TestStatusErrorsScrubbing(t *testing.T) {
+	check := assert.New(t)
+	err := HandleErrorStatusCode(http.StatusConflict, []byte(`{"error_message": "iban GB11NWBK40030041426819 exists", "name": "Samantha Holder"}`))
+	check.EqualError(err, `request conflict: {"error_message": "iban ******************6819 exists", "name": "[redacted]"}`)
+
+	err = HandleErrorStatusCode(http.StatusBadRequest, []byte(`{"error_message": "validation failure list:\naccount_number in body 41426819 is too long"}`))
+	check.EqualError(err, "bad request: attributes.account_number ****6819 is too long")
+}
@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package accounterrors
+
+import "testing"
+
+// FuzzHandleErrorStatusCode - builds errors from arbitrary status codes and bodies, which must not panic
+func FuzzHandleErrorStatusCode(f *testing.F) {
+	f.Add(400, []byte(`{"error_message": "validation failure list:\nvalidation failure list:\ncountry in body is required"}`))
+	f.Add(404, []byte(``))
+	f.Add(0, []byte{0xff, 0x00})
+	f.Fuzz(func(t *testing.T, statusCode int, response []byte) {
+		if err := HandleErrorStatusCode(statusCode, response); err == nil {
+			t.Fatal("expected an error for every status code")
+		}
+	})
+}
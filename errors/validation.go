@@ -0,0 +1,137 @@
+package accounterrors
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// codes of field errors found by client side validation, field errors of the api carry the codes of the api
+const (
+	CodeRequired    = "required"
+	CodeInvalid     = "invalid"
+	CodeTooMany     = "too_many"
+	CodeTooLong     = "too_long"
+	CodeUnsupported = "unsupported"
+)
+
+// ValidationError - aggregates the field errors of invalid request data, found by client side validation or
+// reported by the api. Message summarizes the error, Errors hold the problem of each field
+type ValidationError struct {
+	Message string
+	Errors  []FieldError
+}
+
+// Error - returns the summary followed by the field errors
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Message
+	}
+	problems := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		problems[i] = fieldError.Error()
+	}
+	return e.Message + ": " + strings.Join(problems, "; ")
+}
+
+// NewValidationError - returns a validation error of the field errors, nil if there are none
+// Field errors without pointer get the json pointer of their field in the request body, field errors without code CodeInvalid
+func NewValidationError(message string, fieldErrors []FieldError) error {
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+	validationError := &ValidationError{Message: message, Errors: make([]FieldError, len(fieldErrors))}
+	for i, fieldError := range fieldErrors {
+		if fieldError.Pointer == "" {
+			fieldError.Pointer = FieldPointer(fieldError.Field)
+		}
+		if fieldError.Code == "" {
+			fieldError.Code = CodeInvalid
+		}
+		validationError.Errors[i] = fieldError
+	}
+	return validationError
+}
+
+// FieldPointer - returns the json pointer of a dotted field in the data of a request body,
+// like /data/attributes/name/1 for attributes.name[1]
+func FieldPointer(field string) string {
+	if field == "" {
+		return "/data"
+	}
+	field = strings.NewReplacer("[", ".", "]", "").Replace(field)
+	tokens := strings.Split(field, ".")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+	}
+	return "/data/" + strings.Join(tokens, "/")
+}
+
+// pointerField - returns the dotted field of a json pointer in the data of a request body
+func pointerField(pointer string) string {
+	pointer = strings.TrimPrefix(strings.TrimPrefix(pointer, "/data"), "/")
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return strings.Join(tokens, ".")
+}
+
+// dataFields - fields of the data of a request body outside of the attributes
+var dataFields = map[string]bool{"id": true, "organisation_id": true, "type": true, "version": true, "relationships": true}
+
+// validationFailure - matches a line of a validation failure list of the api, like "country in body should match '^[A-Z]{2}$'"
+var validationFailure = regexp.MustCompile(`^([\w.\[\]]+) in body (.+)$`)
+
+// parseFieldErrors - returns the field errors of an api error response, from json:api error objects with a source
+// pointer or from the lines of a validation failure list. Fields of validation failure lists which are not fields
+// of the data are attributed to the attributes
+func parseFieldErrors(response []byte) []FieldError {
+	body := struct {
+		ErrorMessage string `json:"error_message"`
+		Errors       []struct {
+			Code   string `json:"code"`
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+			Source *struct {
+				Pointer string `json:"pointer"`
+			} `json:"source"`
+		} `json:"errors"`
+	}{}
+	if err := json.Unmarshal(response, &body); err != nil {
+		return nil
+	}
+
+	var fieldErrors []FieldError
+	for _, apiError := range body.Errors {
+		if apiError.Source == nil || apiError.Source.Pointer == "" {
+			continue
+		}
+		message := apiError.Detail
+		if message == "" {
+			message = apiError.Title
+		}
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   pointerField(apiError.Source.Pointer),
+			Message: message,
+			Pointer: apiError.Source.Pointer,
+			Code:    apiError.Code,
+		})
+	}
+	for _, line := range strings.Split(body.ErrorMessage, "\n") {
+		match := validationFailure.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		field := match[1]
+		if root := strings.SplitN(field, ".", 2)[0]; !dataFields[root] && root != "attributes" {
+			field = "attributes." + field
+		}
+		code := CodeInvalid
+		if strings.HasPrefix(match[2], "is required") {
+			code = CodeRequired
+		}
+		fieldErrors = append(fieldErrors, FieldError{Field: field, Message: match[2], Pointer: FieldPointer(field), Code: code})
+	}
+	return fieldErrors
+}
@@ -0,0 +1,76 @@
+package accounterrors
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFieldPointer - tests dotted fields are converted into json pointers of the request data
+func TestFieldPointer(t *testing.T) {
+	check := assert.New(t)
+	check.Equal("/data/attributes/bank_id", FieldPointer("attributes.bank_id"))
+	check.Equal("/data/attributes/name/1", FieldPointer("attributes.name[1]"))
+	check.Equal("/data/id", FieldPointer("id"))
+	check.Equal("/data", FieldPointer(""))
+	check.Equal("attributes.name.1", pointerField("/data/attributes/name/1"))
+}
+
+// TestNewValidationError - tests field errors are completed with pointers and codes
+func TestNewValidationError(t *testing.T) {
+	check := assert.New(t)
+	check.Nil(NewValidationError("invalid create params", nil))
+
+	err := NewValidationError("invalid create params", []FieldError{
+		{Field: "attributes.bank_id", Message: "is required for this country"},
+		{Field: "id", Message: "is required", Pointer: "/data/id", Code: CodeRequired},
+	})
+	check.EqualError(err, "invalid create params: attributes.bank_id is required for this country; id is required")
+	validationError := &ValidationError{}
+	check.True(errors.As(err, &validationError))
+	check.Equal([]FieldError{
+		{Field: "attributes.bank_id", Message: "is required for this country", Pointer: "/data/attributes/bank_id", Code: CodeInvalid},
+		{Field: "id", Message: "is required", Pointer: "/data/id", Code: CodeRequired},
+	}, validationError.Errors)
+}
+
+// TestBadRequestValidationError - tests the field errors of bad request responses are parsed
+func TestBadRequestValidationError(t *testing.T) {
+	check := assert.New(t)
+	err := HandleErrorStatusCode(http.StatusBadRequest, []byte(`{"error_message": "validation failure list:\nvalidation failure list:\nid in body is required\ncountry in body should match '^[A-Z]{2}$'"}`))
+	validationError := &ValidationError{}
+	check.True(errors.As(err, &validationError))
+	check.Equal([]FieldError{
+		{Field: "id", Message: "is required", Pointer: "/data/id", Code: CodeRequired},
+		{Field: "attributes.country", Message: "should match '^[A-Z]{2}$'", Pointer: "/data/attributes/country", Code: CodeInvalid},
+	}, validationError.Errors)
+	check.EqualError(err, "bad request: id is required; attributes.country should match '^[A-Z]{2}$'")
+
+	err = HandleErrorStatusCode(http.StatusBadRequest, []byte(`{"errors": [
+		{"code": "bank_id_length", "detail": "must have 6 characters", "source": {"pointer": "/data/attributes/bank_id"}},
+		{"code": "unavailable", "title": "try again later"}
+	]}`))
+	check.True(errors.As(err, &validationError))
+	check.Equal([]FieldError{{Field: "attributes.bank_id", Message: "must have 6 characters", Pointer: "/data/attributes/bank_id", Code: "bank_id_length"}}, validationError.Errors)
+
+	err = HandleErrorStatusCode(http.StatusBadRequest, []byte(`{"error_message": "invalid request body"}`))
+	check.False(errors.As(err, &validationError))
+	check.EqualError(err, `bad request: {"error_message": "invalid request body"}`)
+}
+
+// TestUnprocessableEntityValidationError - tests unprocessable entities return validation errors with or without field errors
+func TestUnprocessableEntityValidationError(t *testing.T) {
+	check := assert.New(t)
+	err := HandleErrorStatusCode(http.StatusUnprocessableEntity, []byte(`{"errors": [{"code": "invalid_bic", "detail": "is not a known bic", "source": {"pointer": "/data/attributes/bic"}}]}`))
+	validationError := &ValidationError{}
+	check.True(errors.As(err, &validationError))
+	check.Equal([]FieldError{{Field: "attributes.bic", Message: "is not a known bic", Pointer: "/data/attributes/bic", Code: "invalid_bic"}}, validationError.Errors)
+	check.EqualError(err, "unprocessable entity: attributes.bic is not a known bic")
+
+	err = HandleErrorStatusCode(http.StatusUnprocessableEntity, []byte(`{"error_message": "account cannot be processed"}`))
+	check.True(errors.As(err, &validationError))
+	check.Empty(validationError.Errors)
+	check.EqualError(err, `unprocessable entity: {"error_message": "account cannot be processed"}`)
+}
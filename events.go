@@ -0,0 +1,280 @@
+package accountlib
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"accountlib/errors"
+)
+
+// event stream constants
+const (
+	eventsPath           = "events"
+	eventStreamType      = "text/event-stream"
+	defaultReconnectWait = time.Second
+	maxReconnectWait     = 30 * time.Second
+	maxStreamErrorBody   = 64 << 10
+)
+
+// EventFilter - selects the events of a stream, empty fields select all events
+// EventTypes hold event types like account.created, OrganisationID limits the stream to the accounts of an organisation
+type EventFilter struct {
+	EventTypes     []string
+	OrganisationID string
+}
+
+// AccountEvent - account event received from the event stream
+// Err is set on events which could not be decoded, and on the last event before the channel closes when
+// the stream fails permanently, e.g. because the token was revoked
+type AccountEvent struct {
+	ID      string
+	Type    string
+	Account *AccountData
+	Err     error
+}
+
+// StreamEvents - streams account events from the event stream endpoint of the api
+// Dropped connections are reopened, resuming after the last received event. The channel is closed when
+// ctx is done, the client is shut down or the stream fails permanently. An error is returned when the stream cannot be opened at all,
+// e.g. when the api does not offer an event stream
+func (client *Client) StreamEvents(ctx context.Context, filter EventFilter) (<-chan AccountEvent, error) {
+	ctx, release, err := client.lifecycle.background(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body, err := client.openEventStream(ctx, filter, "")
+	if err != nil {
+		release()
+		return nil, err
+	}
+	events := make(chan AccountEvent)
+	go func() {
+		defer release()
+		client.streamEvents(ctx, filter, body, events)
+	}()
+	return events, nil
+}
+
+// streamEvents - reads events from the stream into the channel, reconnecting until ctx is done
+func (client *Client) streamEvents(ctx context.Context, filter EventFilter, body io.ReadCloser, events chan<- AccountEvent) {
+	defer close(events)
+	lastEventID := ""
+	reconnectWait := defaultReconnectWait
+	wait := reconnectWait
+	for {
+		reader := newEventReader(body, lastEventID)
+		for {
+			event, ok := reader.next()
+			lastEventID = reader.lastEventID
+			if !ok {
+				break
+			}
+			wait = reconnectWait
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				body.Close()
+				return
+			}
+		}
+		body.Close()
+		if reader.retry > 0 {
+			reconnectWait, wait = reader.retry, reader.retry
+		}
+
+		// reconnect, backing off while the api keeps failing
+		for {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			var err error
+			if body, err = client.openEventStream(ctx, filter, lastEventID); err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if permanentStreamError(err) {
+				select {
+				case events <- AccountEvent{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if wait *= 2; wait > maxReconnectWait {
+				wait = maxReconnectWait
+			}
+		}
+	}
+}
+
+// openEventStream - opens the event stream, resuming after lastEventID when it is set
+// Streams rejected with 401 are opened once more with a fresh token of the token provider
+func (client *Client) openEventStream(ctx context.Context, filter EventFilter, lastEventID string) (io.ReadCloser, error) {
+	token, err := client.streamToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.requestEventStream(ctx, filter, lastEventID, token)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && token != "" {
+		resp.Body.Close()
+		client.tokenProvider.Invalidate(token)
+		if token, err = client.streamToken(ctx); err != nil {
+			return nil, err
+		}
+		resp, err = client.requestEventStream(ctx, filter, lastEventID, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		response, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxStreamErrorBody))
+		return nil, client.statusErrors.HandleErrorStatusCode(resp.StatusCode, response)
+	}
+	return resp.Body, nil
+}
+
+// requestEventStream - requests the event stream, with the bearer token when it is set
+func (client *Client) requestEventStream(ctx context.Context, filter EventFilter, lastEventID, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.eventsURL(filter), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range client.headers {
+		req.Header[name] = values
+	}
+	if token != "" {
+		req.Header.Set(authorizationHeader, "Bearer "+token)
+	}
+	req.Header.Set("Accept", eventStreamType)
+	req.Header.Set("Cache-Control", "no-cache")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	return client.streamClient().Do(req)
+}
+
+// streamToken - returns the token of the token provider for the event stream, empty without token provider
+func (client *Client) streamToken(ctx context.Context) (string, error) {
+	if client.tokenProvider == nil {
+		return "", nil
+	}
+	token, err := client.tokenProvider.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to get token. error: %w", err)
+	}
+	return token, nil
+}
+
+// streamClient - returns the http client of the client without timeout, the lifetime of a stream is bound by its context
+func (client *Client) streamClient() *http.Client {
+	if client.httpClient == nil {
+		return &http.Client{}
+	}
+	streamClient := *client.httpClient
+	streamClient.Timeout = 0
+	return &streamClient
+}
+
+// eventsURL - returns the url of the event stream with the filter as query values
+func (client *Client) eventsURL(filter EventFilter) string {
+	query := url.Values{}
+	if len(filter.EventTypes) > 0 {
+		query.Set("filter[event_type]", strings.Join(filter.EventTypes, ","))
+	}
+	if filter.OrganisationID != "" {
+		query.Set("filter[organisation_id]", filter.OrganisationID)
+	}
+	requestURL := client.apiURL(eventsPath)
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	return requestURL
+}
+
+// permanentStreamError - reports whether reconnecting is pointless, which is the case for client errors except 408 and 429
+func permanentStreamError(err error) bool {
+	statusCode, ok := accounterrors.StatusCode(err)
+	return ok && statusCode >= 400 && statusCode < 500 && statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests
+}
+
+// eventReader - reads server-sent events, retry holds the reconnect delay requested by the server
+// id buffers the last id line and lastEventID takes it on every blank line, even when no event is dispatched because
+// the event carried no data. Ids of incomplete events at the end of the stream are not taken, like by browsers
+type eventReader struct {
+	reader      *bufio.Reader
+	retry       time.Duration
+	id          string
+	lastEventID string
+}
+
+// newEventReader - returns a reader of the events of a stream resuming after the last event id
+func newEventReader(body io.Reader, lastEventID string) *eventReader {
+	return &eventReader{reader: bufio.NewReader(body), id: lastEventID, lastEventID: lastEventID}
+}
+
+// next - returns the next event of the stream, false when the stream ended
+func (reader *eventReader) next() (AccountEvent, bool) {
+	event := AccountEvent{}
+	var data []string
+	for {
+		line, err := reader.reader.ReadString('\n')
+		if err != nil {
+			// an event without its terminating blank line is incomplete and dropped
+			return AccountEvent{}, false
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			reader.lastEventID = reader.id
+			if len(data) == 0 {
+				event = AccountEvent{}
+				continue
+			}
+			event.ID = reader.lastEventID
+			event.Account = &AccountData{}
+			if err := json.Unmarshal([]byte(strings.Join(data, "\n")), event.Account); err != nil {
+				event.Account = nil
+				event.Err = fmt.Errorf("invalid event %s. error: %s", event.ID, err.Error())
+			}
+			return event, true
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if index := strings.Index(line, ":"); index >= 0 {
+			field, value = line[:index], strings.TrimPrefix(line[index+1:], " ")
+		}
+		switch field {
+		case "id":
+			// ids containing NUL are ignored like by browsers, an empty id resets the last event id
+			if !strings.ContainsRune(value, 0) {
+				reader.id = value
+			}
+		case "event":
+			event.Type = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if milliseconds, err := strconv.Atoi(value); err == nil && milliseconds > 0 {
+				reader.retry = time.Duration(milliseconds) * time.Millisecond
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package accountlib
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStreamEvents - tests events are decoded and the stream resumes after the last event when the connection drops
+func TestStreamEvents(t *testing.T) {
+	check := assert.New(t)
+	var mutex sync.Mutex
+	var lastEventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		check.Equal("/v1/events", r.URL.Path)
+		check.Equal("account.created,account.deleted", r.URL.Query().Get("filter[event_type]"))
+		check.Equal("text/event-stream", r.Header.Get("Accept"))
+		check.Equal("Bearer secret", r.Header.Get("Authorization"))
+		mutex.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		connection := len(lastEventIDs)
+		mutex.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		// the first connection drops after two events, the second one after its events and an incomplete one
+		if connection == 1 {
+			fmt.Fprint(w, "retry: 10\n: keep alive\n\n")
+			fmt.Fprint(w, "id: 1\nevent: account.created\ndata: {\"id\": \"7eb322ba-57f6-465c-b600-79f26ac7fdc3\",\ndata: \"version\": 0}\n\n")
+			fmt.Fprint(w, "id: 2\r\nevent: account.created\r\ndata: {\"id\": \"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc\"}\r\n\r\n")
+			return
+		}
+		fmt.Fprint(w, "id: 3\nevent: account.deleted\ndata: {\"id\": \"7eb322ba-57f6-465c-b600-79f26ac7fdc3\"}\n\n")
+		fmt.Fprint(w, "id: 4\nevent: account.deleted\ndata: {\"id\": ")
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewClient(&ClientOptions{BaseURL: server.URL, Token: "secret"})
+	events, err := client.StreamEvents(ctx, EventFilter{EventTypes: []string{"account.created", "account.deleted"}})
+	check.Nil(err)
+
+	received := []AccountEvent{<-events, <-events, <-events}
+	check.Equal("1", received[0].ID)
+	check.Equal("account.created", received[0].Type)
+	check.Equal(int64(0), *received[0].Account.Version)
+	check.Equal("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", received[1].Account.ID)
+	check.Equal("account.deleted", received[2].Type)
+	check.Nil(received[2].Err)
+
+	// the incomplete event is dropped, so the third connection resumes after event 3
+	check.Eventually(func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(lastEventIDs) >= 3
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	for range events {
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	check.Equal([]string{"", "2", "3"}, lastEventIDs[:3])
+}
+
+// TestEventReaderLastEventID - tests id lines update the last event id without data and ids of incomplete events are not taken
+func TestEventReaderLastEventID(t *testing.T) {
+	check := assert.New(t)
+	reader := newEventReader(strings.NewReader("id: 1\ndata: {}\n\nid: 2\n\ndata: {}\n\nid: 3\ndata: {"), "0")
+
+	event, ok := reader.next()
+	check.True(ok)
+	check.Equal("1", event.ID)
+	event, ok = reader.next()
+	check.True(ok)
+	check.Equal("2", event.ID)
+	check.Equal("2", reader.lastEventID)
+	_, ok = reader.next()
+	check.False(ok)
+	check.Equal("2", reader.lastEventID)
+
+	// a blank line after an id line without data moves the last event id on
+	reader = newEventReader(strings.NewReader("id: 5\n\n"), "4")
+	_, ok = reader.next()
+	check.False(ok)
+	check.Equal("5", reader.lastEventID)
+}
+
+// TestStreamEventsInvalidData - tests events with invalid data are delivered with an error
+func TestStreamEventsInvalidData(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "id: 1\nevent: account.created\ndata: [\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := NewClient(&ClientOptions{BaseURL: server.URL}).StreamEvents(ctx, EventFilter{})
+	check.Nil(err)
+	event := <-events
+	check.Nil(event.Account)
+	check.Contains(event.Err.Error(), "invalid event 1")
+}
+
+// TestStreamEventsUnavailable - tests an error is returned when the stream cannot be opened
+func TestStreamEventsUnavailable(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(&ClientOptions{BaseURL: server.URL}).StreamEvents(context.Background(), EventFilter{})
+	check.Contains(err.Error(), "resource not found")
+}
+
+// TestStreamEventsPermanentError - tests the channel is closed after an error event when reconnecting fails permanently
+func TestStreamEventsPermanentError(t *testing.T) {
+	check := assert.New(t)
+	var mutex sync.Mutex
+	connections := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		connections++
+		connection := connections
+		mutex.Unlock()
+		switch connection {
+		case 1:
+			fmt.Fprint(w, "retry: 10\n\n")
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := NewClient(&ClientOptions{BaseURL: server.URL}).StreamEvents(ctx, EventFilter{OrganisationID: "35eedc2c-0318-40dc-a090-d6f42e7b2754"})
+	check.Nil(err)
+	event, ok := <-events
+	check.True(ok)
+	check.Contains(event.Err.Error(), "unauthorized")
+	_, ok = <-events
+	check.False(ok)
+	mutex.Lock()
+	defer mutex.Unlock()
+	check.Equal(3, connections)
+}
@@ -1,11 +1,11 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-
-	"github.com/google/uuid"
+	"time"
 
 	"accountlib"
 )
@@ -19,42 +19,36 @@ func initClient() *accountlib.Client {
 
 // createAccount - example function for creating an account
 func createAccount(client *accountlib.Client) (*accountlib.AccountData, error) {
-	country := "GB"
-
 	// create account
-	accountData, err := client.Create(accountlib.AccountCreateParams{
-		ID:             uuid.New().String(),
-		OrganisationID: "cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb",
-		Type:           "accounts",
-		Attributes: &accountlib.AccountCreateAttributes{
-			Country:                &country,
-			BaseCurrency:           "GBP",
-			BankID:                 "400300",
-			BankIDCode:             "GBDSC",
-			Bic:                    "NWBKGB22",
-			ProcessingService:      "ABC Bank",
-			UserDefinedInformation: "Some important info",
-			ValidationType:         "card",
-			ReferenceMask:          "############",
-			AcceptanceQualifier:    "same_day",
-			Name:                   []string{"Samantha Holder"},
-			AlternativeNames:       []string{"Sam Holder"},
-		},
-	},
+	accountData, err := client.Create(context.Background(), accountlib.NewAccountBuilder().
+		OrganisationID("cca3d6ba-cdb1-11eb-be5c-bfc51b0459bb").
+		Country("GB").
+		BaseCurrency("GBP").
+		BankID("400300").
+		BankIDCode("GBDSC").
+		Bic("NWBKGB22").
+		ProcessingService("ABC Bank").
+		UserDefinedInformation("Some important info").
+		ValidationType("card").
+		ReferenceMask("############").
+		AcceptanceQualifier("same_day").
+		Name("Samantha Holder").
+		AlternativeNames("Sam Holder").
+		Build(),
 	)
 
 	return accountData, err
 }
 
-// fetchAccount - example function for fetching an account based on account id
+// fetchAccount - example function for fetching an account based on account id, with a timeout for this call only
 func fetchAccount(client *accountlib.Client, accountID string) (*accountlib.AccountData, error) {
-	accountData, err := client.Fetch(accountID)
+	accountData, err := client.Fetch(context.Background(), accountID, accountlib.WithTimeout(2*time.Second))
 	return accountData, err
 }
 
 // deleteAccount - example function for deleting an account based on account id, version
 func deleteAccount(client *accountlib.Client, accountID string, version *int64) error {
-	err := client.Delete(accountID, version)
+	err := client.Delete(context.Background(), accountID, version)
 	return err
 }
 
@@ -0,0 +1,352 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"accountlib"
+	"accountlib/internal/listfilter"
+)
+
+// fake server constants
+const (
+	accountsPath    = "/v1/organisation/accounts"
+	healthPath      = "/v1/health"
+	defaultPageSize = 100
+	contentType     = "application/vnd.api+json"
+)
+
+// Server - in-memory implementation of the accounts api on top of httptest.Server
+type Server struct {
+	URL string
+
+	server        *httptest.Server
+	mutex         sync.Mutex
+	accounts      map[string]*accountlib.AccountData
+	subscriptions map[string]*accountlib.Subscription
+	apiVersion    string
+	scenarios     []*Scenario
+}
+
+// now - returns the time the server sets as created_on and modified_on, at the second resolution of the api
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// New - starts a fake accounts api server, it must be closed after use
+func New() *Server {
+	server := &Server{
+		accounts:      make(map[string]*accountlib.AccountData),
+		subscriptions: make(map[string]*accountlib.Subscription),
+		apiVersion:    accountlib.MaxAPIVersion,
+	}
+	server.server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	server.URL = server.server.URL
+	return server
+}
+
+// Close - shuts the server down
+func (server *Server) Close() {
+	server.server.Close()
+}
+
+// AddAccount - stores an account, replacing any account with the same id
+// Accounts without a version or timestamps get the ones the api would set on creation
+func (server *Server) AddAccount(account accountlib.AccountData) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	stored := account.Clone()
+	if stored.Version == nil {
+		stored.Version = new(int64)
+	}
+	if stored.CreatedOn == nil {
+		createdOn := now()
+		stored.CreatedOn = &createdOn
+	}
+	if stored.ModifiedOn == nil {
+		modifiedOn := *stored.CreatedOn
+		stored.ModifiedOn = &modifiedOn
+	}
+	server.accounts[stored.ID] = stored
+}
+
+// SetAPIVersion - sets the api version advertised with every response, an empty version advertises none
+func (server *Server) SetAPIVersion(version string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.apiVersion = version
+}
+
+// Accounts - returns the stored accounts sorted by id
+func (server *Server) Accounts() []accountlib.AccountData {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	accounts := make([]accountlib.AccountData, 0, len(server.accounts))
+	for _, account := range server.sortedAccounts() {
+		accounts = append(accounts, *account.Clone())
+	}
+	return accounts
+}
+
+// serveHTTP - handles requests with their scenario or routes them to the account handlers
+func (server *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !server.serveScenario(w, r) {
+		server.serve(w, r)
+	}
+}
+
+// serve - routes requests to the account handlers
+func (server *Server) serve(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.apiVersion != "" {
+		w.Header().Set(accountlib.APIVersionHeader, server.apiVersion)
+	}
+	if r.URL.Path == healthPath && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "up"})
+		return
+	}
+	if r.URL.Path == subscriptionsPath || strings.HasPrefix(r.URL.Path, subscriptionsPath+"/") {
+		server.serveSubscriptions(w, r)
+		return
+	}
+	if r.URL.Path == accountsPath {
+		switch r.Method {
+		case http.MethodGet:
+			server.list(w, r)
+		case http.MethodPost:
+			server.create(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	accountID := strings.TrimPrefix(r.URL.Path, accountsPath+"/")
+	if !strings.HasPrefix(r.URL.Path, accountsPath+"/") || accountID == "" || strings.Contains(accountID, "/") {
+		writeError(w, http.StatusNotFound, "route not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		server.fetch(w, accountID)
+	case http.MethodPatch:
+		server.update(w, r, accountID)
+	case http.MethodDelete:
+		server.delete(w, r, accountID)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// create - handles account creation
+func (server *Server) create(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		Data *accountlib.AccountData `json:"data"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Data == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	account := request.Data
+	if account.ID == "" || account.OrganisationID == "" {
+		writeError(w, http.StatusBadRequest, "validation failure list:\nid in body is required")
+		return
+	}
+	if _, ok := server.accounts[account.ID]; ok {
+		writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		return
+	}
+	version := int64(0)
+	account.Version = &version
+	createdOn, modifiedOn := now(), now()
+	account.CreatedOn, account.ModifiedOn = &createdOn, &modifiedOn
+	server.accounts[account.ID] = account
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"data":  account,
+		"links": map[string]string{"self": accountsPath + "/" + account.ID},
+	})
+}
+
+// fetch - handles fetching a single account
+func (server *Server) fetch(w http.ResponseWriter, accountID string) {
+	account, ok := server.accounts[accountID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", accountID))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  account,
+		"links": map[string]string{"self": accountsPath + "/" + accountID},
+	})
+}
+
+// update - handles updating an account, the version must match the stored one and attributes are replaced
+func (server *Server) update(w http.ResponseWriter, r *http.Request, accountID string) {
+	request := struct {
+		Data *accountlib.AccountData `json:"data"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Data == nil || request.Data.Version == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	account, ok := server.accounts[accountID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", accountID))
+		return
+	}
+	if *account.Version != *request.Data.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+	if attributes := request.Data.Attributes; attributes != nil {
+		if account.Attributes != nil {
+			attributes.Status = account.Attributes.Status
+			attributes.StatusReason = account.Attributes.StatusReason
+		}
+		account.Attributes = attributes
+	}
+	version := *account.Version + 1
+	account.Version = &version
+	modifiedOn := now()
+	account.ModifiedOn = &modifiedOn
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  account,
+		"links": map[string]string{"self": accountsPath + "/" + accountID},
+	})
+}
+
+// delete - handles deleting an account, the version must match the stored one
+func (server *Server) delete(w http.ResponseWriter, r *http.Request, accountID string) {
+	version, err := strconv.ParseInt(r.URL.Query().Get("version"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+	account, ok := server.accounts[accountID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	if *account.Version != version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+	delete(server.accounts, accountID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// list - handles listing accounts with page[number], page[size] and filter[...] query parameters
+func (server *Server) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pageNumber, pageSize, err := pagination(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// filter accounts
+	accounts := make([]*accountlib.AccountData, 0, len(server.accounts))
+	for _, account := range server.sortedAccounts() {
+		if matchesFilters(account, query) {
+			accounts = append(accounts, account)
+		}
+	}
+
+	// select the page
+	lastPage := 0
+	if len(accounts) > 0 {
+		lastPage = (len(accounts) - 1) / pageSize
+	}
+	start := pageNumber * pageSize
+	if start > len(accounts) {
+		start = len(accounts)
+	}
+	end := start + pageSize
+	if end > len(accounts) {
+		end = len(accounts)
+	}
+
+	links := map[string]string{
+		"self":  pageLink(query, pageNumber, pageSize),
+		"first": pageLink(query, 0, pageSize),
+		"last":  pageLink(query, lastPage, pageSize),
+	}
+	if pageNumber < lastPage {
+		links["next"] = pageLink(query, pageNumber+1, pageSize)
+	}
+	if pageNumber > 0 {
+		links["prev"] = pageLink(query, pageNumber-1, pageSize)
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(accounts)))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  accounts[start:end],
+		"links": links,
+		"meta":  map[string]int{"count": len(accounts)},
+	})
+}
+
+// sortedAccounts - returns the stored accounts sorted by id, the caller must hold the mutex
+func (server *Server) sortedAccounts() []*accountlib.AccountData {
+	accounts := make([]*accountlib.AccountData, 0, len(server.accounts))
+	for _, account := range server.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	return accounts
+}
+
+// pagination - returns the requested page number and size
+func pagination(query url.Values) (pageNumber, pageSize int, err error) {
+	pageSize = defaultPageSize
+	if value := query.Get("page[number]"); value != "" {
+		if pageNumber, err = strconv.Atoi(value); err != nil || pageNumber < 0 {
+			return 0, 0, fmt.Errorf("invalid page number %q", value)
+		}
+	}
+	if value := query.Get("page[size]"); value != "" {
+		if pageSize, err = strconv.Atoi(value); err != nil || pageSize <= 0 {
+			return 0, 0, fmt.Errorf("invalid page size %q", value)
+		}
+	}
+	return pageNumber, pageSize, nil
+}
+
+// matchesFilters - reports whether the account matches all filter[...] query parameters
+func matchesFilters(account *accountlib.AccountData, query url.Values) bool {
+	return listfilter.Match(account, func(name string) string { return query.Get("filter[" + name + "]") })
+}
+
+// pageLink - returns the link to a page of the list, keeping the filters of the query
+func pageLink(query url.Values, pageNumber, pageSize int) string {
+	linkQuery := url.Values{}
+	for key, values := range query {
+		linkQuery[key] = values
+	}
+	linkQuery.Set("page[number]", strconv.Itoa(pageNumber))
+	linkQuery.Set("page[size]", strconv.Itoa(pageSize))
+	return accountsPath + "?" + linkQuery.Encode()
+}
+
+// writeError - writes an api error response
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]string{"error_message": message})
+}
+
+// writeJSON - writes a json response
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,168 @@
+package fakeserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+)
+
+// TestServerAccountLifecycle - tests create, fetch and delete through the account client
+func TestServerAccountLifecycle(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	params := accountlib.NewAccountBuilder().OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").Country("GB").Build()
+
+	created, err := client.Create(context.Background(), params)
+	check.Equal(err, nil)
+	check.Equal(*created.Version, int64(0))
+
+	fetched, err := client.Fetch(context.Background(), params.ID)
+	check.Equal(err, nil)
+	check.True(fetched.Equal(created))
+
+	check.Equal(client.Delete(context.Background(), params.ID, created.Version), nil)
+	check.Empty(server.Accounts())
+}
+
+// TestServerConflicts - tests duplicate creates and stale deletes return 409
+func TestServerConflicts(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	version := int64(2)
+	server.AddAccount(accountlib.AccountData{
+		ID:             "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		OrganisationID: "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+		Version:        &version,
+	})
+
+	_, err := client.Create(context.Background(), accountlib.AccountCreateParams{
+		ID:             "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		OrganisationID: "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+	})
+	check.Contains(err.Error(), "request conflict")
+
+	staleVersion := int64(1)
+	err = client.Delete(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", &staleVersion)
+	check.Contains(err.Error(), "request conflict")
+
+	_, err = client.Fetch(context.Background(), "0d209d7f-d07a-4542-947f-5885fddddae2")
+	check.Contains(err.Error(), "resource not found")
+}
+
+// TestServerList - tests paginated and filtered listing
+func TestServerList(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	for _, id := range []string{"1", "2", "3"} {
+		server.AddAccount(accountlib.AccountData{ID: id, OrganisationID: "org"})
+	}
+	server.AddAccount(accountlib.AccountData{ID: "4", OrganisationID: "other"})
+
+	response, err := http.Get(server.URL + accountsPath + "?filter[organisation_id]=org&page[number]=1&page[size]=2")
+	check.Equal(err, nil)
+	defer response.Body.Close()
+	list := struct {
+		Data  []accountlib.AccountData `json:"data"`
+		Links map[string]string        `json:"links"`
+		Meta  map[string]int           `json:"meta"`
+	}{}
+	check.Equal(json.NewDecoder(response.Body).Decode(&list), nil)
+
+	check.Equal(response.StatusCode, http.StatusOK)
+	check.Len(list.Data, 1)
+	check.Equal(list.Data[0].ID, "3")
+	check.Contains(list.Links["prev"], "page%5Bnumber%5D=0")
+	check.NotContains(list.Links, "next")
+	check.Equal(3, list.Meta["count"])
+	check.Equal("3", response.Header.Get("X-Total-Count"))
+}
+
+// TestServerUpdate - tests updates replace the attributes, increment the version and reject stale versions
+func TestServerUpdate(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	params := accountlib.NewAccountBuilder().OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").Country("GB").Build()
+	created, err := client.Create(context.Background(), params)
+	check.Equal(err, nil)
+
+	updated, err := client.Update(context.Background(), accountlib.AccountUpdateParams{
+		ID:         params.ID,
+		Version:    created.Version,
+		Attributes: accountlib.NewAccountBuilder().Country("FR").Build().Attributes,
+	})
+	check.Equal(err, nil)
+	check.Equal(*updated.Version, int64(1))
+	check.Equal(*updated.Attributes.Country, accountlib.CountryCode("FR"))
+
+	_, err = client.Update(context.Background(), accountlib.AccountUpdateParams{ID: params.ID, Version: created.Version})
+	check.Contains(err.Error(), "request conflict")
+}
+
+// TestServerHealth - tests the health endpoint reports the server as up
+func TestServerHealth(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	health, err := client.Health(context.Background())
+	check.Equal(err, nil)
+	check.True(health.Healthy())
+}
+
+// TestServerAPIVersion - tests the advertised api version is checked by the client
+func TestServerAPIVersion(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	check.Nil(client.CheckCompatibility(context.Background()))
+
+	server.SetAPIVersion("2.0")
+	err := client.CheckCompatibility(context.Background())
+	compatibilityError := &accountlib.CompatibilityError{}
+	check.True(errors.As(err, &compatibilityError))
+	check.False(compatibilityError.Warning)
+}
+
+// TestServerSubscriptions - tests create, list and delete of subscriptions through the account client
+func TestServerSubscriptions(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	params := accountlib.SubscriptionCreateParams{
+		ID:             "a1f3c2b4-2d4e-4f6a-8b9c-0d1e2f3a4b5c",
+		OrganisationID: "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+		Attributes:     &accountlib.SubscriptionAttributes{CallbackURI: "https://hooks.internal/accounts", EventTypes: []string{"account.created", "account.deleted"}},
+	}
+
+	subscription, err := client.CreateSubscription(context.Background(), params)
+	check.Nil(err)
+	check.Equal(int64(0), *subscription.Version)
+	_, err = client.CreateSubscription(context.Background(), params)
+	check.Contains(err.Error(), "request conflict")
+
+	subscriptions, err := client.ListSubscriptions(context.Background(), accountlib.ListParams{})
+	check.Nil(err)
+	check.Len(subscriptions.Data, 1)
+	check.Equal([]string{"account.created", "account.deleted"}, subscriptions.Data[0].Attributes.EventTypes)
+
+	check.Nil(client.DeleteSubscription(context.Background(), params.ID, subscription.Version))
+	check.Empty(server.Subscriptions())
+	err = client.DeleteSubscription(context.Background(), params.ID, subscription.Version)
+	check.Contains(err.Error(), "resource not found")
+}
@@ -0,0 +1,176 @@
+package fakeserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"accountlib"
+)
+
+// Route - requests a scenario applies to
+type Route string
+
+// routes of the accounts api, RouteAny matches every request
+const (
+	RouteAny    Route = "any"
+	RouteCreate Route = "create"
+	RouteFetch  Route = "fetch"
+	RouteUpdate Route = "update"
+	RouteDelete Route = "delete"
+	RouteList   Route = "list"
+	RouteHealth Route = "health"
+)
+
+// Scenario - scripted behaviour of the requests of a route, configured with a fluent api, e.g.
+// server.On(fakeserver.RouteCreate).Times(2).Respond(http.StatusServiceUnavailable) fails the first two creates.
+// Scenarios apply in the order they were added, the first scenario with requests left handles a request
+type Scenario struct {
+	server     *Server
+	route      Route
+	times      int
+	statusCode int
+	body       *string
+	delay      time.Duration
+	malformed  bool
+}
+
+// On - adds a scenario for the requests of the route, which applies to every request until limited with Times
+func (server *Server) On(route Route) *Scenario {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	scenario := &Scenario{server: server, route: route}
+	server.scenarios = append(server.scenarios, scenario)
+	return scenario
+}
+
+// ResetScenarios - removes every scenario, requests are handled normally again
+func (server *Server) ResetScenarios() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.scenarios = nil
+}
+
+// Times - limits the scenario to the next n requests of its route
+func (scenario *Scenario) Times(n int) *Scenario {
+	return scenario.change(func() { scenario.times = n })
+}
+
+// Respond - answers the requests with the status code and an api error instead of handling them, state is not changed
+func (scenario *Scenario) Respond(statusCode int) *Scenario {
+	return scenario.change(func() { scenario.statusCode = statusCode })
+}
+
+// Body - answers the requests with the raw body, with the status code of Respond or 200
+func (scenario *Scenario) Body(body string) *Scenario {
+	return scenario.change(func() { scenario.body = &body })
+}
+
+// Delay - delays the requests before they are handled, requests canceled by the client during the delay are dropped
+func (scenario *Scenario) Delay(delay time.Duration) *Scenario {
+	return scenario.change(func() { scenario.delay = delay })
+}
+
+// Malformed - handles the requests normally but truncates the json of their responses, like a broken proxy
+func (scenario *Scenario) Malformed() *Scenario {
+	return scenario.change(func() { scenario.malformed = true })
+}
+
+// change - changes the scenario under the mutex of the server, which reads it while handling requests
+func (scenario *Scenario) change(change func()) *Scenario {
+	scenario.server.mutex.Lock()
+	defer scenario.server.mutex.Unlock()
+	change()
+	return scenario
+}
+
+// serveScenario - handles a request with the first scenario of its route with requests left, reporting whether
+// a scenario applied
+func (server *Server) serveScenario(w http.ResponseWriter, r *http.Request) bool {
+	server.mutex.Lock()
+	scenario, ok := server.nextScenario(routeOf(r))
+	if !ok {
+		server.mutex.Unlock()
+		return false
+	}
+	applied := *scenario
+	apiVersion := server.apiVersion
+	server.mutex.Unlock()
+
+	if applied.delay > 0 {
+		timer := time.NewTimer(applied.delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return true
+		}
+	}
+	if apiVersion != "" && !applied.malformed {
+		w.Header().Set(accountlib.APIVersionHeader, apiVersion)
+	}
+	switch {
+	case applied.body != nil:
+		statusCode := applied.statusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(*applied.body))
+	case applied.statusCode != 0:
+		writeError(w, applied.statusCode, strings.ToLower(http.StatusText(applied.statusCode)))
+	case applied.malformed:
+		recorder := httptest.NewRecorder()
+		server.serve(recorder, r)
+		for name, values := range recorder.Header() {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(recorder.Code)
+		body := recorder.Body.Bytes()
+		_, _ = w.Write(body[:len(body)/2])
+	default:
+		server.serve(w, r)
+	}
+	return true
+}
+
+// nextScenario - returns the first scenario of the route with requests left, using up one of its requests
+func (server *Server) nextScenario(route Route) (*Scenario, bool) {
+	for i, scenario := range server.scenarios {
+		if scenario.route != RouteAny && scenario.route != route {
+			continue
+		}
+		if scenario.times > 0 {
+			scenario.times--
+			if scenario.times == 0 {
+				server.scenarios = append(server.scenarios[:i:i], server.scenarios[i+1:]...)
+			}
+		}
+		return scenario, true
+	}
+	return nil, false
+}
+
+// routeOf - returns the route of a request, subscription requests and unknown routes only match RouteAny
+func routeOf(r *http.Request) Route {
+	switch {
+	case r.URL.Path == healthPath:
+		return RouteHealth
+	case r.URL.Path == accountsPath && r.Method == http.MethodGet:
+		return RouteList
+	case r.URL.Path == accountsPath && r.Method == http.MethodPost:
+		return RouteCreate
+	case strings.HasPrefix(r.URL.Path, accountsPath+"/"):
+		switch r.Method {
+		case http.MethodGet:
+			return RouteFetch
+		case http.MethodPatch:
+			return RouteUpdate
+		case http.MethodDelete:
+			return RouteDelete
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package fakeserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+)
+
+// TestScenarioTimes - tests the first creates fail with the scripted status code and the following ones succeed
+func TestScenarioTimes(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	server.On(RouteCreate).Times(2).Respond(http.StatusServiceUnavailable)
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL, DisableRetries: true})
+	params := accountlib.NewAccountBuilder().OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").Country("GB").Build()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Create(context.Background(), params)
+		check.Contains(err.Error(), "service unavailable")
+	}
+	check.Empty(server.Accounts())
+	_, err := client.Create(context.Background(), params)
+	check.Nil(err)
+	check.Len(server.Accounts(), 1)
+}
+
+// TestScenarioBodyAndMalformed - tests raw bodies and truncated responses fail decoding in the client
+func TestScenarioBodyAndMalformed(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	params := accountlib.NewAccountBuilder().OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").Country("GB").Build()
+
+	server.On(RouteCreate).Times(1).Malformed()
+	_, err := client.Create(context.Background(), params)
+	check.Contains(err.Error(), "invalid response")
+	check.Len(server.Accounts(), 1)
+
+	server.On(RouteFetch).Times(1).Body(`{"data": []}`)
+	_, err = client.Fetch(context.Background(), params.ID)
+	check.NotNil(err)
+	_, err = client.Fetch(context.Background(), params.ID)
+	check.Nil(err)
+}
+
+// TestScenarioDelay - tests delayed requests run into the timeout of the client until the scenarios are reset
+func TestScenarioDelay(t *testing.T) {
+	check := assert.New(t)
+	server := New()
+	defer server.Close()
+	server.On(RouteAny).Delay(time.Second)
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL, DisableRetries: true})
+
+	_, err := client.Health(context.Background(), accountlib.WithTimeout(20*time.Millisecond))
+	check.True(errors.Is(err, context.DeadlineExceeded))
+
+	server.ResetScenarios()
+	_, err = client.Health(context.Background(), accountlib.WithTimeout(time.Second))
+	check.Nil(err)
+}
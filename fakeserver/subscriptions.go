@@ -0,0 +1,132 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"accountlib"
+)
+
+// subscriptions api path
+const subscriptionsPath = "/v1/subscriptions"
+
+// Subscriptions - returns the stored subscriptions sorted by id
+func (server *Server) Subscriptions() []accountlib.Subscription {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	subscriptions := make([]accountlib.Subscription, 0, len(server.subscriptions))
+	for _, subscription := range server.sortedSubscriptions() {
+		subscriptions = append(subscriptions, *subscription)
+	}
+	return subscriptions
+}
+
+// serveSubscriptions - routes subscription requests to the subscription handlers
+func (server *Server) serveSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == subscriptionsPath {
+		switch r.Method {
+		case http.MethodGet:
+			server.listSubscriptions(w, r)
+		case http.MethodPost:
+			server.createSubscription(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	subscriptionID := strings.TrimPrefix(r.URL.Path, subscriptionsPath+"/")
+	if subscriptionID == "" || strings.Contains(subscriptionID, "/") {
+		writeError(w, http.StatusNotFound, "route not found")
+		return
+	}
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	server.deleteSubscription(w, r, subscriptionID)
+}
+
+// createSubscription - handles subscription creation
+func (server *Server) createSubscription(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		Data *accountlib.Subscription `json:"data"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Data == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	subscription := request.Data
+	if subscription.ID == "" || subscription.OrganisationID == "" || subscription.Attributes == nil || subscription.Attributes.CallbackURI == "" {
+		writeError(w, http.StatusBadRequest, "validation failure list:\nid, organisation_id and callback_uri in body are required")
+		return
+	}
+	if _, ok := server.subscriptions[subscription.ID]; ok {
+		writeError(w, http.StatusConflict, "Subscription cannot be created as it violates a duplicate constraint")
+		return
+	}
+	version := int64(0)
+	subscription.Version = &version
+	server.subscriptions[subscription.ID] = subscription
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"data":  subscription,
+		"links": map[string]string{"self": subscriptionsPath + "/" + subscription.ID},
+	})
+}
+
+// listSubscriptions - handles listing subscriptions with page[number] and page[size] query parameters
+func (server *Server) listSubscriptions(w http.ResponseWriter, r *http.Request) {
+	pageNumber, pageSize, err := pagination(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	subscriptions := server.sortedSubscriptions()
+	start := pageNumber * pageSize
+	if start > len(subscriptions) {
+		start = len(subscriptions)
+	}
+	end := start + pageSize
+	if end > len(subscriptions) {
+		end = len(subscriptions)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  subscriptions[start:end],
+		"links": map[string]string{"self": r.URL.RequestURI()},
+	})
+}
+
+// deleteSubscription - handles deleting a subscription, the version must match the stored one
+func (server *Server) deleteSubscription(w http.ResponseWriter, r *http.Request, subscriptionID string) {
+	version, err := strconv.ParseInt(r.URL.Query().Get("version"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+	subscription, ok := server.subscriptions[subscriptionID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", subscriptionID))
+		return
+	}
+	if *subscription.Version != version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+	delete(server.subscriptions, subscriptionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sortedSubscriptions - returns the stored subscriptions sorted by id, the caller must hold the mutex
+func (server *Server) sortedSubscriptions() []*accountlib.Subscription {
+	subscriptions := make([]*accountlib.Subscription, 0, len(server.subscriptions))
+	for _, subscription := range server.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i].ID < subscriptions[j].ID })
+	return subscriptions
+}
@@ -0,0 +1,39 @@
+//go:build go1.18
+// +build go1.18
+
+package accountlib
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// FuzzDecodeAccount - decodes arbitrary account responses, which must not panic and must re-encode when accepted
+func FuzzDecodeAccount(f *testing.F) {
+	f.Add([]byte(`{"data": {"id":"7eb322ba-57f6-465c-b600-79f26ac7fdc3","attributes":{"country":"GB","name":["a"]},"version":0}}`))
+	f.Add([]byte(`{"data": {"attributes":{"unknown":{"nested":[1,2]}}}}`))
+	f.Add([]byte(`{"data": null}`))
+	f.Add([]byte(`{"data": {"attributes": null}}`))
+	f.Add([]byte(`[]`))
+	f.Fuzz(func(t *testing.T, response []byte) {
+		accountData, err := DecodeAccount(response)
+		if err != nil || accountData == nil {
+			return
+		}
+		if _, err = json.Marshal(accountData); err != nil {
+			t.Fatalf("decoded account cannot be encoded: %s", err.Error())
+		}
+		_ = accountData.String()
+		_ = accountData.Clone().Equal(accountData)
+	})
+}
+
+// FuzzDecodeAccountListStrict - decodes arbitrary list responses in strict mode, which must not panic
+func FuzzDecodeAccountListStrict(f *testing.F) {
+	f.Add([]byte(`{"data": [{"id":"7eb322ba-57f6-465c-b600-79f26ac7fdc3"}], "links": {"self": "/v1/organisation/accounts"}}`))
+	f.Add([]byte(`{"data": [{"attributes":{"unknown":true}}, null]}`))
+	f.Fuzz(func(t *testing.T, response []byte) {
+		dataResponse := accountListEnvelope{}
+		_ = decodeJSON(response, &dataResponse, true)
+	})
+}
@@ -0,0 +1,58 @@
+package accountlib
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// health api path
+const healthPath = "health"
+
+// HealthStatus - status reported by the health endpoint of the api
+type HealthStatus string
+
+// health statuses reported by the api
+const (
+	HealthStatusUp   HealthStatus = "up"
+	HealthStatusDown HealthStatus = "down"
+)
+
+// Health - holds the health response of the api
+type Health struct {
+	Status HealthStatus `json:"status"`
+}
+
+// Healthy - reports whether the api is up
+func (health *Health) Healthy() bool {
+	return health != nil && health.Status == HealthStatusUp
+}
+
+// Health - returns the health of the api, use it for verifying connectivity at startup
+// Requests are retried like every other request, an api which does not answer with 200 returns an error
+func (client *Client) Health(ctx context.Context, options ...CallOption) (health *Health, err error) {
+	// prepare request specifications
+	requestSpecifications := client.requestSpecifications(ctx, OperationHealth, http.MethodGet, client.apiURL(healthPath), nil)
+	defer wrapError(&err, requestSpecifications, time.Now())
+
+	// make request
+	statusCode, response, _, err := client.do(requestSpecifications, options)
+	if err != nil {
+		return
+	}
+
+	// handle status code, response
+	if statusCode == http.StatusOK {
+		health = &Health{}
+		err = client.decodeResponse(response, health)
+		if err != nil {
+			err = fmt.Errorf("received invalid response. error: %s", err.Error())
+			return nil, err
+		}
+		return health, nil
+	}
+	err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
+
+	return
+}
@@ -0,0 +1,74 @@
+package accountlib
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// healthServer - returns a server answering the health endpoint with the given status code and body
+func healthServer(statusCode int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+// TestHealthUp - tests a healthy api
+func TestHealthUp(t *testing.T) {
+	check := assert.New(t)
+	server := healthServer(http.StatusOK, `{"status":"up"}`)
+	defer server.Close()
+
+	health, err := NewClient(&ClientOptions{BaseURL: server.URL}).Health(context.Background())
+	check.Nil(err)
+	check.Equal(HealthStatusUp, health.Status)
+	check.True(health.Healthy())
+}
+
+// TestHealthDown - tests an api reporting itself as down
+func TestHealthDown(t *testing.T) {
+	check := assert.New(t)
+	server := healthServer(http.StatusOK, `{"status":"down"}`)
+	defer server.Close()
+
+	health, err := NewClient(&ClientOptions{BaseURL: server.URL}).Health(context.Background())
+	check.Nil(err)
+	check.False(health.Healthy())
+}
+
+// TestHealthErrors - tests error status codes and invalid responses
+func TestHealthErrors(t *testing.T) {
+	check := assert.New(t)
+	server := healthServer(http.StatusInternalServerError, `{"error_message":"database unavailable"}`)
+	defer server.Close()
+	_, err := NewClient(&ClientOptions{BaseURL: server.URL}).Health(context.Background())
+	check.Contains(err.Error(), "database unavailable")
+
+	invalidServer := healthServer(http.StatusOK, `{"status":`)
+	defer invalidServer.Close()
+	_, err = NewClient(&ClientOptions{BaseURL: invalidServer.URL}).Health(context.Background())
+	check.Contains(err.Error(), "received invalid response")
+}
+
+// TestHealthCanceled - tests the context cancels the retries of the health check
+func TestHealthCanceled(t *testing.T) {
+	check := assert.New(t)
+	server := healthServer(http.StatusServiceUnavailable, ``)
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := NewClient(&ClientOptions{BaseURL: server.URL, RetryCount: 10}).Health(ctx)
+	check.Contains(err.Error(), "context deadline exceeded")
+	check.True(time.Since(start) < time.Second)
+}
@@ -2,13 +2,20 @@ package httprequest
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"os"
+	"runtime/debug"
+	"sync/atomic"
 	"time"
+
+	"accountlib/errors"
 )
 
 // http request constants
@@ -18,128 +25,362 @@ const (
 	defaultKeepAliveTime         = 30 * time.Second
 	defaultIdleConnectionTimeout = 30 * time.Second
 	defaultTimeout               = 5 * time.Second
+	defaultBackOff               = 100 * time.Millisecond
 	defaultRequestType           = "application/json"
-)
-
-// default transport and retry codes
-var (
-	defaultTransport = &http.Transport{
-		DialContext: (&net.Dialer{
-			KeepAlive: defaultKeepAliveTime,
-		}).DialContext,
-		MaxIdleConns:        defaultMaxIdleConnection,
-		IdleConnTimeout:     defaultIdleConnectionTimeout,
-		TLSHandshakeTimeout: 10 * time.Second,
-		MaxIdleConnsPerHost: defaultMaxIdleConnection,
-	}
-	defaultRetryStatusCodes = []int{
-		http.StatusRequestTimeout,
-		http.StatusGatewayTimeout,
-		http.StatusServiceUnavailable,
-	}
+	idempotencyKeyHeader         = "Idempotency-Key"
+	maxPresizedBody              = 10 << 20
 )
 
 // RequestHandlerIface - request handler interface
+//
+// Deprecated: RequestHandlerIface - use RequestSender, which returns the attempts, duration and trace info of the request as well
 type RequestHandlerIface interface {
 	MakeRequest(specs *RequestSpecifications) (statusCode int, body []byte, headers http.Header, err error)
 }
 
+// RequestSender - sends requests, implemented by RequestHandler
+type RequestSender interface {
+	Send(specs *RequestSpecifications) (*Response, error)
+}
+
+// the request handler implements both request interfaces
+var (
+	_ RequestHandlerIface = (*RequestHandler)(nil)
+	_ RequestSender       = (*RequestHandler)(nil)
+)
+
 // RequestSpecifications - controls each http request behaviour
+// Operation names the operation the request belongs to, OnAttempt is called after every attempt of the request
+// Observer is notified of the start, the attempts and the end of the request
+// POST and PATCH requests are not retried after ambiguous failures, where the server may have processed the request,
+// unless RetryNonIdempotent is set or the request carries an Idempotency-Key header
+// A zero RetryCount uses the default retry count, DisableRetries sends the request exactly once
+// TimeoutDuration limits every attempt of the request, a zero TimeoutDuration uses the deprecated Timeout in seconds
+// and without either the timeout of the http client
+// RetryIf decides which failed attempts are retried, replacing DefaultRetryIf. Successful attempts are never retried
+// and POST and PATCH requests are still not retried after ambiguous failures
+// RetryPolicy overrides the retry count, the back off and RetryIf of the request with its non zero fields
+// Multipart sends a streamed multipart/form-data body instead of Params
 type RequestSpecifications struct {
-	URL        string
-	HTTPMethod string
-	Params     []byte
-	Timeout    int
-	RetryCount int
+	Operation          string
+	URL                string
+	HTTPMethod         string
+	Params             []byte
+	TimeoutDuration    time.Duration
+	RetryCount         int
+	DisableRetries     bool
+	RetryNonIdempotent bool
+	Headers            http.Header
+	Context            context.Context
+	OnAttempt          func(attempt Attempt)
+	Observer           RequestObserver
+	RetryIf            func(statusCode int, err error) bool
+	RetryPolicy        *RetryPolicy
+	Multipart          *Multipart
+	// Deprecated: Timeout - timeout of every attempt in seconds, use TimeoutDuration. It is only used when
+	// TimeoutDuration is zero
+	Timeout int
 }
 
-// RequestHandler - holds http client
+// RetryPolicy - controls the retries of a single request, zero fields keep the defaults of the request
+// Attempts is the number of attempts including the first one, 1 sends the request exactly once. BackOff is the wait
+// before the first retry, it doubles after every retry and defaults to 100ms. RetryIf decides which failed attempts are
+// retried. Budget limits the time spent on the request, no retry is started when its back off would exceed the budget
+type RetryPolicy struct {
+	Attempts int
+	BackOff  time.Duration
+	RetryIf  func(statusCode int, err error) bool
+	Budget   time.Duration
+}
+
+// Attempt - outcome of a single attempt of a request
+// Number starts at 1, BackOff is the wait before the next attempt and zero when no attempt follows
+type Attempt struct {
+	Number     int
+	StatusCode int
+	Err        error
+	BackOff    time.Duration
+}
+
+// RequestHandler - holds http client and the statistics of its connection pool
 type RequestHandler struct {
+	stats      poolStats
 	HTTPClient *http.Client
 }
 
+// newTransport - returns the default transport, every handler gets its own so tuning or closing the connections of one
+// client does not affect the others. Proxies are taken from HTTPS_PROXY, HTTP_PROXY and NO_PROXY like by the standard library
+// The connections are counted in the pool statistics
+func newTransport(stats *poolStats) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: stats.countingDialer((&net.Dialer{
+			KeepAlive: defaultKeepAliveTime,
+		}).DialContext),
+		MaxIdleConns:        defaultMaxIdleConnection,
+		IdleConnTimeout:     defaultIdleConnectionTimeout,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConnsPerHost: defaultMaxIdleConnection,
+	}
+}
+
 // NewRequestHandler  - returns RequestHandler object
+// Handlers without custom client use their own transport
 func NewRequestHandler(customClient *http.Client) *RequestHandler {
 	if customClient != nil {
 		return &RequestHandler{
 			HTTPClient: customClient,
 		}
 	}
-	httpClient := &http.Client{}
-	httpClient.Transport = defaultTransport
-	httpClient.Timeout = time.Duration(defaultTimeout)
-	return &RequestHandler{
-		HTTPClient: httpClient,
+	handler := &RequestHandler{}
+	handler.HTTPClient = &http.Client{
+		Transport: newTransport(&handler.stats),
+		Timeout:   defaultTimeout,
 	}
+	return handler
 }
 
 // MakeRequest - prepares request and makes an API call
+//
+// Deprecated: MakeRequest - use Send, which returns the attempts, duration and trace info of the request as well
 func (r *RequestHandler) MakeRequest(specs *RequestSpecifications) (statusCode int, body []byte, headers http.Header, err error) {
-	baseBackOffTime := 100 * time.Millisecond
-	requestCount := 1
+	response, err := r.Send(specs)
+	return response.StatusCode, response.Body, response.Headers, err
+}
 
-	// prepare request
+// Send - prepares request and makes an API call, the response is never nil and holds the outcome of the last attempt
+// Panics of the transport, the observer or the attempt callback are returned as *accounterrors.PanicError
+// Invalid specifications are returned as *accounterrors.SpecificationError without sending the request
+func (r *RequestHandler) Send(specs *RequestSpecifications) (response *Response, err error) {
+	response = &Response{}
+	start := time.Now()
+	defer func() {
+		if value := recover(); value != nil {
+			err = panicked(response, value)
+		}
+		response.Duration = time.Since(start)
+	}()
+	baseBackOffTime := defaultBackOff
+	if specs.RetryPolicy != nil && specs.RetryPolicy.BackOff > 0 {
+		baseBackOffTime = specs.RetryPolicy.BackOff
+	}
+	if specs.Observer != nil {
+		// the request is sent with the context of the observer, the context of the specifications is restored afterwards
+		callerCtx := specs.Context
+		ctx := callerCtx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		request := RequestInfo{Operation: specs.Operation, Method: specs.HTTPMethod, URL: specs.URL}
+		ctx = specs.Observer.OnRequestStart(ctx, request)
+		specs.Context = ctx
+		defer func() {
+			specs.Context = callerCtx
+			// panics are turned into the error before the observer sees the result, panics of OnRequestEnd itself are
+			// recovered by the outer defer
+			if value := recover(); value != nil {
+				err = panicked(response, value)
+			}
+			specs.Observer.OnRequestEnd(ctx, request, RequestResult{StatusCode: response.StatusCode, Err: err, Attempts: response.Attempts, Duration: time.Since(start)})
+		}()
+	}
+
+	// validate and prepare request
+	if err = specs.Validate(); err != nil {
+		return response, err
+	}
 	newHandler, newRequest, err := r.prepareRequest(specs)
 	if err != nil {
-		return statusCode, nil, nil, err
+		return response, err
 	}
 
-	// handle retries using exponential backoff strategy
-	for requestCount <= specs.RetryCount {
-		// sending the request
-		statusCode, body, headers, err = sendRequest(newHandler, newRequest)
-		if checkRetryRequired(statusCode) || err != nil {
-			time.Sleep(time.Duration(baseBackOffTime))
-			baseBackOffTime = 2 * baseBackOffTime
-		} else {
+	// handle retries using exponential backoff strategy, the same request is reused for every attempt
+	for requestCount := 1; requestCount <= specs.RetryCount; requestCount++ {
+		if requestCount > 1 {
+			if err = rewindBody(newRequest); err != nil {
+				response.Body, response.Headers = nil, nil
+				return response, err
+			}
+		}
+
+		// sending the request, traced for the timings of the attempt
+		tracer := newTracer()
+		tracedRequest := newRequest.WithContext(httptrace.WithClientTrace(newRequest.Context(), tracer.clientTrace()))
+		atomic.AddInt64(&r.stats.inflight, 1)
+		statusCode, body, headers, sendError := sendRequest(newHandler, tracedRequest)
+		atomic.AddInt64(&r.stats.inflight, -1)
+		if gotConn, reused := tracer.connection(); gotConn {
+			r.stats.countConnection(reused)
+		}
+		err = sendError
+		*response = Response{StatusCode: statusCode, Body: body, Headers: headers, Attempts: requestCount, Trace: tracer.traceInfo()}
+		retry := specs.Retryable(statusCode, err) && (idempotent(specs) || !ambiguousFailure(statusCode, err)) && requestCount < specs.RetryCount &&
+			specs.withinBudget(time.Since(start)+baseBackOffTime)
+		attempt := Attempt{Number: requestCount, StatusCode: statusCode, Err: err}
+		if retry {
+			attempt.BackOff = baseBackOffTime
+		}
+		if specs.OnAttempt != nil {
+			specs.OnAttempt(attempt)
+		}
+		if specs.Observer != nil {
+			specs.Observer.OnAttempt(newRequest.Context(), RequestInfo{Operation: specs.Operation, Method: specs.HTTPMethod, URL: specs.URL}, attempt)
+		}
+		if !retry {
 			break
 		}
-		requestCount++
+		if waitError := backOff(newRequest.Context(), baseBackOffTime); waitError != nil {
+			response.Body, response.Headers = nil, nil
+			return response, waitError
+		}
+		baseBackOffTime = 2 * baseBackOffTime
 	}
 
-	return
+	return response, err
+}
+
+// panicked - clears the response of a panicked request and returns the panic as *accounterrors.PanicError
+func panicked(response *Response, value interface{}) error {
+	response.StatusCode, response.Body, response.Headers = 0, nil, nil
+	return &accounterrors.PanicError{Value: value, Stack: debug.Stack()}
 }
 
 // prepareRequest - returns customized request handler with default values if not exclusively specified
 func (r *RequestHandler) prepareRequest(specs *RequestSpecifications) (*http.Client, *http.Request, error) {
-	//Create request
-	req, err := http.NewRequest(specs.HTTPMethod, specs.URL, nil)
+	// a bytes reader lets the request set the content length and rewind the body for retries
+	var body io.Reader
+	if hasBody(specs.HTTPMethod) && specs.Multipart == nil {
+		body = bytes.NewReader(specs.Params)
+	}
+
+	//Create request, requests without a context are not cancelable
+	ctx := specs.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, specs.HTTPMethod, specs.URL, body)
 	if err != nil {
 		err = fmt.Errorf("unable to create http request. error: %s", err.Error())
 		return r.HTTPClient, req, err
 	}
-	//check and set retry count
-	if specs.RetryCount == 0 {
+	//check and set retry count, the attempts of a retry policy take precedence
+	if specs.RetryPolicy != nil && specs.RetryPolicy.Attempts > 0 {
+		specs.RetryCount = specs.RetryPolicy.Attempts
+	} else if specs.DisableRetries {
+		specs.RetryCount = 1
+	} else if specs.RetryCount == 0 {
 		specs.RetryCount = defaultRetryCount
 	}
-	// check and set request timeout
-	if specs.Timeout != 0 {
-		r.HTTPClient.Timeout = time.Duration(specs.Timeout) * time.Second
+	// check and set request timeout on a copy, the http client is shared by concurrent requests
+	httpClient := r.HTTPClient
+	if timeout := specs.timeout(); timeout != 0 {
+		timeoutClient := *r.HTTPClient
+		timeoutClient.Timeout = timeout
+		httpClient = &timeoutClient
+	}
+	// add custom headers
+	for name, values := range specs.Headers {
+		req.Header[name] = values
 	}
-	// add post headers and body
-	if specs.HTTPMethod == http.MethodPost {
-		// handle POST request
-		req.Header.Add("Content-type", defaultRequestType)
-		body := prepareRequestBody(specs.Params)
-		req.Body = body
+	// add body headers, multipart bodies replace the params
+	if specs.Multipart != nil {
+		multipartBody(req, specs.Multipart)
+	} else if hasBody(specs.HTTPMethod) {
+		req.Header.Set("Content-Type", defaultRequestType)
 	}
-	return r.HTTPClient, req, nil
+	return httpClient, req, nil
 }
 
-// prepareRequestBody - converts []byte to readcloser
-func prepareRequestBody(params []byte) io.ReadCloser {
-	var body *bytes.Buffer
-	body = bytes.NewBuffer(params)
-	return ioutil.NopCloser(body)
+// timeout - returns the timeout of every attempt, falling back to the deprecated timeout in seconds
+func (specs *RequestSpecifications) timeout() time.Duration {
+	if specs.TimeoutDuration != 0 {
+		return specs.TimeoutDuration
+	}
+	return time.Duration(specs.Timeout) * time.Second
+}
+
+// backOff - waits before the next attempt, returning early when the context is done
+func backOff(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("request canceled. error: %w", ctx.Err())
+	}
+}
+
+// hasBody - reports whether requests of the method carry the params as body
+func hasBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPatch
+}
+
+// rewindBody - resets the request body before the request is sent again
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("unable to rewind request body. error: %s", err.Error())
+	}
+	req.Body = body
+	return nil
+}
+
+// Retryable - reports whether an attempt with the outcome is retried, using the RetryIf of the retry policy or of the
+// specifications for failed attempts when it is set
+func (specs *RequestSpecifications) Retryable(statusCode int, err error) bool {
+	if err == nil && statusCode < http.StatusBadRequest {
+		return false
+	}
+	if specs.RetryPolicy != nil && specs.RetryPolicy.RetryIf != nil {
+		return specs.RetryPolicy.RetryIf(statusCode, err)
+	}
+	if specs.RetryIf != nil {
+		return specs.RetryIf(statusCode, err)
+	}
+	return DefaultRetryIf(statusCode, err)
+}
+
+// withinBudget - reports whether the elapsed time stays within the budget of the retry policy
+func (specs *RequestSpecifications) withinBudget(elapsed time.Duration) bool {
+	return specs.RetryPolicy == nil || specs.RetryPolicy.Budget <= 0 || elapsed <= specs.RetryPolicy.Budget
+}
+
+// DefaultRetryIf - checks if retry is required based on the outcome of an attempt, only temporary errors and the
+// retryable status codes are retried. Custom RetryIf predicates can fall back to it
+func DefaultRetryIf(statusCode int, err error) bool {
+	if err != nil {
+		return accounterrors.IsTemporary(err)
+	}
+	return checkRetryRequired(statusCode)
+}
+
+// idempotent - reports whether sending the request twice has the same effect as sending it once
+func idempotent(specs *RequestSpecifications) bool {
+	return !hasBody(specs.HTTPMethod) || specs.RetryNonIdempotent || specs.Headers.Get(idempotencyKeyHeader) != ""
+}
+
+// ambiguousFailure - reports whether the server may have processed a failed request, which is the case for
+// timeouts and connections failing after they were established
+func ambiguousFailure(statusCode int, err error) bool {
+	if err == nil {
+		return statusCode == http.StatusGatewayTimeout
+	}
+	var opError *net.OpError
+	return !(errors.As(err, &opError) && opError.Op == "dial")
+}
+
+// RetryableStatusCode - reports whether requests answered with the status code are retried
+func RetryableStatusCode(statusCode int) bool {
+	return checkRetryRequired(statusCode)
 }
 
 // checkRetryRequired - checks if retry is required based on the status code
 func checkRetryRequired(statusCode int) bool {
-	retryFlag := false
-	for _, retryCode := range defaultRetryStatusCodes {
-		retryFlag = retryFlag || (retryCode == statusCode)
-	}
-	return retryFlag
+	return accounterrors.IsRetryableStatusCode(statusCode)
 }
 
 // sendRequest - sends HTTP request
@@ -148,21 +389,34 @@ func sendRequest(newHandler *http.Client, newRequest *http.Request) (int, []byte
 	resp, err := newHandler.Do(newRequest)
 	if err != nil {
 		if os.IsTimeout(err) {
-			err = fmt.Errorf("timeout encountered. error: %s", err.Error())
-			return http.StatusRequestTimeout, nil, nil, err
+			return 0, nil, nil, &accounterrors.TimeoutError{Err: err}
 		}
-		err = fmt.Errorf("failed to send request. Error: %s", err.Error())
+		err = fmt.Errorf("failed to send request. Error: %w", err)
 		return 0, nil, nil, err
 	}
 
-	// read response body
+	// read response body, sized from the content length when the server sends one
 	defer resp.Body.Close()
-	body := new(bytes.Buffer)
-	_, readError := body.ReadFrom(resp.Body)
+	body, readError := readBody(resp)
 	if readError != nil {
-		err = fmt.Errorf("failed to read response body. error: %s", err.Error())
+		if os.IsTimeout(readError) {
+			return resp.StatusCode, nil, nil, &accounterrors.TimeoutError{Err: readError}
+		}
+		err = fmt.Errorf("failed to read response body. error: %w", readError)
 		return resp.StatusCode, nil, nil, err
 	}
 
-	return resp.StatusCode, body.Bytes(), resp.Header, nil
+	return resp.StatusCode, body, resp.Header, nil
+}
+
+// readBody - reads the complete response body
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.ContentLength >= 0 && resp.ContentLength <= maxPresizedBody {
+		body := make([]byte, resp.ContentLength)
+		if _, err := io.ReadFull(resp.Body, body); err != nil {
+			return nil, err
+		}
+		return body, nil
+	}
+	return ioutil.ReadAll(resp.Body)
 }
@@ -1,13 +1,23 @@
 package httprequest
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+
+	"accountlib/errors"
 )
 
 // HttpTestSuite - test suite for http request
@@ -53,6 +63,18 @@ func TestNewRequestHandler(t *testing.T) {
 	check.NotEqual(requestHandler.HTTPClient.Transport, nil)
 }
 
+// TestNewRequestHandlerTransport - tests every handler gets its own transport
+func TestNewRequestHandlerTransport(t *testing.T) {
+	check := assert.New(t)
+	first := NewRequestHandler(nil).HTTPClient.Transport.(*http.Transport)
+	second := NewRequestHandler(nil).HTTPClient.Transport.(*http.Transport)
+	check.False(first == second)
+
+	first.MaxIdleConnsPerHost = 1
+	check.Equal(defaultMaxIdleConnection, second.MaxIdleConnsPerHost)
+	check.NotNil(second.Proxy)
+}
+
 // TestNewRequestHandlerWithCustomClient - test request handler object creation with custom client
 func TestNewRequestHandlerWithCustomClient(t *testing.T) {
 	check := assert.New(t)
@@ -104,40 +126,103 @@ func (s *HTTPTestSuite) TestMakeRequestFailureResponse() {
 	check.Equal(1, info[http.MethodGet+" "+s.url])
 }
 
-// TestMakeRequestCreationError - tests http request create error
-func (s *HTTPTestSuite) TestMakeRequestCreationError() {
+// TestMakeRequestInvalidSpecifications - tests invalid specifications are rejected before the request is sent
+func (s *HTTPTestSuite) TestMakeRequestInvalidSpecifications() {
+	check := assert.New(s.T())
+	for name, testCase := range map[string]struct {
+		specs RequestSpecifications
+		field string
+	}{
+		"empty url":         {RequestSpecifications{HTTPMethod: http.MethodGet}, "URL"},
+		"malformed url":     {RequestSpecifications{HTTPMethod: http.MethodGet, URL: "http://%zz"}, "URL"},
+		"relative url":      {RequestSpecifications{HTTPMethod: http.MethodGet, URL: "/v1/organisation/accounts"}, "URL"},
+		"wrong method":      {RequestSpecifications{HTTPMethod: "TEST", URL: s.url}, "HTTPMethod"},
+		"invalid method":    {RequestSpecifications{HTTPMethod: "*?", URL: s.url}, "HTTPMethod"},
+		"body of get":       {RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, Params: []byte(`{}`)}, "Params"},
+		"multipart of get":  {RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, Multipart: &Multipart{}}, "Multipart"},
+		"multipart params":  {RequestSpecifications{HTTPMethod: http.MethodPost, URL: s.url, Params: []byte(`{}`), Multipart: &Multipart{}}, "Multipart"},
+		"negative retries":  {RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, RetryCount: -1}, "RetryCount"},
+		"negative timeout":  {RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, TimeoutDuration: -time.Second}, "TimeoutDuration"},
+		"negative seconds":  {RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, Timeout: -1}, "Timeout"},
+		"tiny timeout":      {RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, TimeoutDuration: 10}, "TimeoutDuration"},
+		"negative attempts": {RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, RetryPolicy: &RetryPolicy{Attempts: -1}}, "RetryPolicy.Attempts"},
+		"negative budget":   {RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, RetryPolicy: &RetryPolicy{Budget: -time.Second}}, "RetryPolicy.Budget"},
+	} {
+		specs := testCase.specs
+
+		// make http request
+		_, _, _, err := s.requestHandler.MakeRequest(&specs)
+		specificationError := &accounterrors.SpecificationError{}
+		check.True(errors.As(err, &specificationError), name)
+		check.True(errors.Is(err, accounterrors.ErrInvalidSpecifications), name)
+		check.Equal(testCase.field, specificationError.Field, name)
+	}
+	check.Equal(0, httpmock.GetTotalCallCount())
+}
+
+// TestPrepareRequestCustomTimeout - tests prepare request with custom timeout, leaving the shared http client unchanged
+func (s *HTTPTestSuite) TestPrepareRequestCustomTimeout() {
 	check := assert.New(s.T())
+	defaultClientTimeout := s.requestHandler.HTTPClient.Timeout
 
 	// make http request
-	_, _, _, err := s.requestHandler.MakeRequest(&RequestSpecifications{
-		HTTPMethod: "*?",
+	httpClient, _, _ := s.requestHandler.prepareRequest(&RequestSpecifications{
+		HTTPMethod:      http.MethodPost,
+		Params:          []byte(""),
+		TimeoutDuration: 250 * time.Millisecond,
 	})
-	check.Contains(err.Error(), "unable to create http request")
+	check.Equal(250*time.Millisecond, httpClient.Timeout)
+	check.Equal(defaultClientTimeout, s.requestHandler.HTTPClient.Timeout)
+
+	// the deprecated timeout in seconds is used when no timeout is set
+	httpClient, _, _ = s.requestHandler.prepareRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodGet,
+		Timeout:    10,
+	})
+	check.Equal(10*time.Second, httpClient.Timeout)
 }
 
-// TestMakeRequestWrongHttpMethod - tests with wrong http method
-func (s *HTTPTestSuite) TestMakeRequestWrongHttpMethod() {
+// TestMakeRequestSubSecondTimeout - tests attempts exceeding a sub second timeout fail with a timeout error
+func (s *HTTPTestSuite) TestMakeRequestSubSecondTimeout() {
 	check := assert.New(s.T())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
 
-	// make http request
-	_, _, _, err := s.requestHandler.MakeRequest(&RequestSpecifications{
-		HTTPMethod: "TEST",
+	_, _, _, err := NewRequestHandler(nil).MakeRequest(&RequestSpecifications{
+		URL:             server.URL,
+		HTTPMethod:      http.MethodGet,
+		TimeoutDuration: 50 * time.Millisecond,
+		DisableRetries:  true,
 	})
-	check.Contains(err.Error(), "failed to send request")
+	check.True(errors.Is(err, accounterrors.ErrTimeout))
 }
 
-// TestPrepareRequestCustomTimeout - tests prepare request with custom timeout
-func (s *HTTPTestSuite) TestPrepareRequestCustomTimeout() {
+// TestPrepareRequestRetryCount - tests the default retry count and disabled retries
+func (s *HTTPTestSuite) TestPrepareRequestRetryCount() {
+	check := assert.New(s.T())
+	specs := &RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url}
+	_, _, err := s.requestHandler.prepareRequest(specs)
+	check.Nil(err)
+	check.Equal(defaultRetryCount, specs.RetryCount)
+
+	specs = &RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, RetryCount: 5, DisableRetries: true}
+	_, _, err = s.requestHandler.prepareRequest(specs)
+	check.Nil(err)
+	check.Equal(1, specs.RetryCount)
+}
+
+// TestMakeRequestDisableRetries - tests a request with disabled retries is sent once
+func (s *HTTPTestSuite) TestMakeRequestDisableRetries() {
 	check := assert.New(s.T())
-	customTimeout := 10
+	httpmock.RegisterResponder(http.MethodGet, s.url, httpmock.NewStringResponder(http.StatusServiceUnavailable, ``))
 
 	// make http request
-	_, _, _ = s.requestHandler.prepareRequest(&RequestSpecifications{
-		HTTPMethod: http.MethodPost,
-		Params:     []byte(""),
-		Timeout:    customTimeout,
-	})
-	check.Equal(s.requestHandler.HTTPClient.Timeout, time.Duration(customTimeout)*time.Second)
+	statusCode, _, _, err := s.requestHandler.MakeRequest(&RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, DisableRetries: true})
+	check.Nil(err)
+	check.Equal(http.StatusServiceUnavailable, statusCode)
+	check.Equal(1, httpmock.GetTotalCallCount())
 }
 
 // TestRetryRequired - tests a successful retry check
@@ -153,3 +238,366 @@ func TestRetryNotRequired(t *testing.T) {
 	retryRequired := checkRetryRequired(http.StatusConflict)
 	check.Equal(retryRequired, false)
 }
+
+// TestMakeRequestRetryIf - tests the retry predicate decides which failed attempts are retried
+func TestMakeRequestRetryIf(t *testing.T) {
+	check := assert.New(t)
+	statusCodes := []int{http.StatusConflict, http.StatusServiceUnavailable, http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCodes[0])
+		statusCodes = statusCodes[1:]
+	}))
+	defer server.Close()
+
+	// conflicts are retried, service unavailable is not
+	var outcomes []int
+	statusCode, _, _, err := NewRequestHandler(nil).MakeRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodGet,
+		URL:        server.URL,
+		RetryCount: 3,
+		RetryIf: func(statusCode int, err error) bool {
+			outcomes = append(outcomes, statusCode)
+			return statusCode == http.StatusConflict
+		},
+	})
+	check.Nil(err)
+	check.Equal(http.StatusServiceUnavailable, statusCode)
+	check.Equal([]int{http.StatusConflict, http.StatusServiceUnavailable}, outcomes)
+}
+
+// TestMakeRequestRetryPolicy - tests the retry policy overrides the retry count, back off and predicate of a request
+func TestMakeRequestRetryPolicy(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	var backOffs []time.Duration
+	policy := &RetryPolicy{Attempts: 3, BackOff: time.Millisecond, RetryIf: func(statusCode int, err error) bool {
+		return statusCode == http.StatusConflict
+	}}
+	statusCode, _, _, err := NewRequestHandler(nil).MakeRequest(&RequestSpecifications{
+		HTTPMethod:     http.MethodGet,
+		URL:            server.URL,
+		DisableRetries: true,
+		RetryIf:        func(int, error) bool { return false },
+		RetryPolicy:    policy,
+		OnAttempt:      func(attempt Attempt) { backOffs = append(backOffs, attempt.BackOff) },
+	})
+	check.Nil(err)
+	check.Equal(http.StatusConflict, statusCode)
+	check.Equal([]time.Duration{time.Millisecond, 2 * time.Millisecond, 0}, backOffs)
+
+	// no retry is started beyond the budget
+	backOffs = nil
+	policy.BackOff = time.Second
+	policy.Budget = 500 * time.Millisecond
+	_, _, _, err = NewRequestHandler(nil).MakeRequest(&RequestSpecifications{
+		HTTPMethod:  http.MethodGet,
+		URL:         server.URL,
+		RetryPolicy: policy,
+		OnAttempt:   func(attempt Attempt) { backOffs = append(backOffs, attempt.BackOff) },
+	})
+	check.Nil(err)
+	check.Equal([]time.Duration{0}, backOffs)
+}
+
+// TestRetryable - tests successes are never retried and failures fall back to DefaultRetryIf without predicate
+func TestRetryable(t *testing.T) {
+	check := assert.New(t)
+	specs := &RequestSpecifications{}
+	check.True(specs.Retryable(http.StatusServiceUnavailable, nil))
+	check.False(specs.Retryable(http.StatusConflict, nil))
+	check.True(specs.Retryable(0, syscall.ECONNRESET))
+
+	specs.RetryIf = func(int, error) bool { return true }
+	check.False(specs.Retryable(http.StatusOK, nil))
+	check.True(specs.Retryable(http.StatusConflict, nil))
+}
+
+// stubTransport - round tripper answering every request with a canned response, without network
+type stubTransport struct {
+	body []byte
+}
+
+// RoundTrip - returns the canned response
+func (transport *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader(transport.body)),
+		ContentLength: int64(len(transport.body)),
+		Request:       req,
+	}, nil
+}
+
+// BenchmarkMakeRequest - measures the allocations of a POST request and its response handling
+func BenchmarkMakeRequest(b *testing.B) {
+	response := bytes.Repeat([]byte(`{"data": {"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`), 20)
+	requestHandler := NewRequestHandler(&http.Client{Transport: &stubTransport{body: response}})
+	params := bytes.Repeat([]byte(`{"data": {"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`), 10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _, err := requestHandler.MakeRequest(&RequestSpecifications{
+			HTTPMethod: http.MethodPost,
+			URL:        "http://localhost:8080/v1/organisation/accounts",
+			Params:     params,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestMakeRequestRetryResendsBody - tests that a retried POST request sends the complete body again
+func (s *HTTPTestSuite) TestMakeRequestRetryResendsBody() {
+	check := assert.New(s.T())
+	params := `{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`
+	createURL := "http://localhost:8080/v1/organisation/accounts"
+
+	// fail the first attempt, succeed afterwards
+	bodies := []string{}
+	httpmock.RegisterResponder(http.MethodPost, createURL, func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, ``), nil
+		}
+		return httpmock.NewStringResponse(http.StatusCreated, params), nil
+	})
+
+	// make http request
+	statusCode, _, _, err := s.requestHandler.MakeRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodPost,
+		URL:        createURL,
+		Params:     []byte(params),
+		RetryCount: 2,
+	})
+	check.Nil(err)
+	check.Equal(http.StatusCreated, statusCode)
+	check.Equal([]string{params, params}, bodies)
+}
+
+// TestPrepareRequestHeaders - tests custom headers are added to the request
+func (s *HTTPTestSuite) TestPrepareRequestHeaders() {
+	check := assert.New(s.T())
+
+	// prepare http request
+	_, req, err := s.requestHandler.prepareRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodGet,
+		URL:        s.url,
+		Headers:    http.Header{"Authorization": []string{"Bearer token"}},
+	})
+	check.Nil(err)
+	check.Equal("Bearer token", req.Header.Get("Authorization"))
+}
+
+// TestMakeRequestCanceledContext - tests a canceled context stops the retries
+func (s *HTTPTestSuite) TestMakeRequestCanceledContext() {
+	check := assert.New(s.T())
+	ctx, cancel := context.WithCancel(context.Background())
+	httpmock.RegisterResponder(http.MethodGet, s.url, func(req *http.Request) (*http.Response, error) {
+		cancel()
+		return httpmock.NewStringResponse(http.StatusServiceUnavailable, ``), nil
+	})
+
+	// make http request
+	_, _, _, err := s.requestHandler.MakeRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodGet,
+		URL:        s.url,
+		RetryCount: 3,
+		Context:    ctx,
+	})
+	check.True(errors.Is(err, context.Canceled))
+	check.Equal(1, httpmock.GetCallCountInfo()[http.MethodGet+" "+s.url])
+}
+
+// TestMakeRequestOnAttempt - tests every attempt is reported with the back off before the next attempt
+func (s *HTTPTestSuite) TestMakeRequestOnAttempt() {
+	check := assert.New(s.T())
+	statusCodes := []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusOK}
+	httpmock.RegisterResponder(http.MethodGet, s.url, func(req *http.Request) (*http.Response, error) {
+		statusCode := statusCodes[0]
+		statusCodes = statusCodes[1:]
+		return httpmock.NewStringResponse(statusCode, ``), nil
+	})
+
+	// make http request
+	attempts := []Attempt{}
+	statusCode, _, _, err := s.requestHandler.MakeRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodGet,
+		URL:        s.url,
+		RetryCount: 3,
+		OnAttempt:  func(attempt Attempt) { attempts = append(attempts, attempt) },
+	})
+	check.Nil(err)
+	check.Equal(http.StatusOK, statusCode)
+	check.Equal([]Attempt{
+		{Number: 1, StatusCode: http.StatusServiceUnavailable, BackOff: 100 * time.Millisecond},
+		{Number: 2, StatusCode: http.StatusGatewayTimeout, BackOff: 200 * time.Millisecond},
+		{Number: 3, StatusCode: http.StatusOK},
+	}, attempts)
+}
+
+// TestMakeRequestTimeout - tests client side timeouts return a timeout error instead of a status code
+func TestMakeRequestTimeout(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	// make http request
+	requestHandler := NewRequestHandler(&http.Client{Timeout: 10 * time.Millisecond})
+	statusCode, _, _, err := requestHandler.MakeRequest(&RequestSpecifications{HTTPMethod: http.MethodGet, URL: server.URL, RetryCount: 1})
+	check.Equal(0, statusCode)
+	check.True(errors.Is(err, accounterrors.ErrTimeout))
+	timeoutError, ok := err.(interface{ Timeout() bool })
+	check.True(ok && timeoutError.Timeout())
+}
+
+// TestMakeRequestServerTimeout - tests a 408 status code of the server is returned as status code
+func (s *HTTPTestSuite) TestMakeRequestServerTimeout() {
+	check := assert.New(s.T())
+	httpmock.RegisterResponder(http.MethodGet, s.url, httpmock.NewStringResponder(http.StatusRequestTimeout, ``))
+
+	// make http request
+	statusCode, _, _, err := s.requestHandler.MakeRequest(&RequestSpecifications{HTTPMethod: http.MethodGet, URL: s.url, RetryCount: 1})
+	check.Nil(err)
+	check.Equal(http.StatusRequestTimeout, statusCode)
+}
+
+// TestMakeRequestPermanentError - tests permanent transport errors like untrusted certificates are not retried
+func TestMakeRequestPermanentError(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	defer server.Close()
+
+	// make http request
+	attempts := 0
+	_, _, _, err := NewRequestHandler(nil).MakeRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodGet,
+		URL:        server.URL,
+		RetryCount: 3,
+		OnAttempt:  func(Attempt) { attempts++ },
+	})
+	check.NotNil(err)
+	check.False(accounterrors.IsTemporary(err))
+	check.Equal(1, attempts)
+}
+
+// TestMakeRequestNonIdempotentRetries - tests creates are retried after ambiguous failures only when it is safe
+func TestMakeRequestNonIdempotentRetries(t *testing.T) {
+	check := assert.New(t)
+	// the server drops every connection, so the client cannot tell whether the request was processed
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connection, _, _ := w.(http.Hijacker).Hijack()
+		connection.Close()
+	}))
+	defer server.Close()
+
+	for name, testCase := range map[string]struct {
+		specs    RequestSpecifications
+		attempts int
+	}{
+		"post":               {RequestSpecifications{HTTPMethod: http.MethodPost}, 1},
+		"patch":              {RequestSpecifications{HTTPMethod: http.MethodPatch}, 1},
+		"get":                {RequestSpecifications{HTTPMethod: http.MethodGet}, 2},
+		"delete":             {RequestSpecifications{HTTPMethod: http.MethodDelete}, 2},
+		"retry opt in":       {RequestSpecifications{HTTPMethod: http.MethodPost, RetryNonIdempotent: true}, 2},
+		"idempotency header": {RequestSpecifications{HTTPMethod: http.MethodPost, Headers: http.Header{"Idempotency-Key": []string{"key"}}}, 2},
+	} {
+		attempts := 0
+		specs := testCase.specs
+		specs.URL = server.URL
+		specs.RetryCount = 2
+		specs.OnAttempt = func(Attempt) { attempts++ }
+
+		// make http request
+		_, _, _, err := NewRequestHandler(nil).MakeRequest(&specs)
+		check.NotNil(err, name)
+		check.Equal(testCase.attempts, attempts, name)
+	}
+}
+
+// TestAmbiguousFailure - tests which failures may have been processed by the server
+func TestAmbiguousFailure(t *testing.T) {
+	check := assert.New(t)
+	check.True(ambiguousFailure(http.StatusGatewayTimeout, nil))
+	check.False(ambiguousFailure(http.StatusServiceUnavailable, nil))
+	check.True(ambiguousFailure(0, &accounterrors.TimeoutError{Err: errors.New("deadline")}))
+	check.False(ambiguousFailure(0, &net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+}
+
+// panickingTransport - round tripper panicking on every request
+type panickingTransport struct{}
+
+// RoundTrip - panics
+func (panickingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	panic("transport failed")
+}
+
+// TestMakeRequestPanic - tests panics of the transport and the attempt callback are returned as errors
+func TestMakeRequestPanic(t *testing.T) {
+	check := assert.New(t)
+	_, _, _, err := NewRequestHandler(&http.Client{Transport: panickingTransport{}}).MakeRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodGet,
+		URL:        "http://localhost",
+	})
+	panicError := &accounterrors.PanicError{}
+	check.True(errors.As(err, &panicError))
+	check.Equal("transport failed", panicError.Value)
+	check.Contains(string(panicError.Stack), "RoundTrip")
+
+	// the observer sees the panic as error of the request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	observer := &recordingObserver{}
+	statusCode, _, _, err := NewRequestHandler(nil).MakeRequest(&RequestSpecifications{
+		HTTPMethod: http.MethodGet,
+		URL:        server.URL,
+		OnAttempt:  func(Attempt) { panic("callback failed") },
+		Observer:   observer,
+	})
+	check.True(errors.Is(err, accounterrors.ErrPanic))
+	check.Equal(0, statusCode)
+	check.Equal([]string{"start  GET", "end"}, observer.calls)
+	check.True(errors.Is(observer.result.Err, accounterrors.ErrPanic))
+	check.Equal(0, observer.result.StatusCode)
+}
+
+// TestSend - tests the response holds the outcome of the last attempt, the attempt count, duration and trace info
+func TestSend(t *testing.T) {
+	check := assert.New(t)
+	statusCodes := []int{http.StatusServiceUnavailable, http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "request")
+		w.WriteHeader(statusCodes[0])
+		statusCodes = statusCodes[1:]
+		_, _ = w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+
+	response, err := NewRequestHandler(nil).Send(&RequestSpecifications{HTTPMethod: http.MethodGet, URL: server.URL})
+	check.Nil(err)
+	check.Equal(http.StatusOK, response.StatusCode)
+	check.Equal(`{"data": {}}`, string(response.Body))
+	check.Equal("request", response.Headers.Get("X-Request-Id"))
+	check.Equal(2, response.Attempts)
+	check.True(response.Duration >= defaultBackOff)
+	check.True(response.Trace.ConnectionReused)
+	check.True(response.Trace.TimeToFirstByte > 0)
+
+	// invalid specifications return an empty response
+	response, err = NewRequestHandler(nil).Send(&RequestSpecifications{HTTPMethod: http.MethodGet})
+	check.True(errors.Is(err, accounterrors.ErrInvalidSpecifications))
+	check.Equal(0, response.Attempts)
+}
@@ -0,0 +1,115 @@
+package httprequest
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Multipart - multipart/form-data body of a POST or PATCH request, sent instead of Params
+// The body is streamed, fields are written before files. Every attempt writes the body again, so files are reopened on retries
+type Multipart struct {
+	Fields []FormField
+	Files  []FormFile
+}
+
+// FormField - text field of a multipart body
+type FormField struct {
+	Name  string
+	Value string
+}
+
+// FormFile - file part of a multipart body, Open is called for every attempt of the request
+// ContentType defaults to application/octet-stream
+type FormFile struct {
+	FieldName   string
+	FileName    string
+	ContentType string
+	Open        func() (io.ReadCloser, error)
+}
+
+// NewFormFile - returns a file part reading the file at path, named after the base name of the path
+func NewFormFile(fieldName, path string) FormFile {
+	return FormFile{
+		FieldName: fieldName,
+		FileName:  filepath.Base(path),
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}
+}
+
+// multipartBody - sets the streamed multipart body of a request, including the body for retries and the content type
+func multipartBody(req *http.Request, form *Multipart) {
+	boundary := multipart.NewWriter(ioutil.Discard).Boundary()
+	req.Body = form.open(boundary)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return form.open(boundary), nil
+	}
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+}
+
+// open - returns a reader of the body, which is written by a goroutine while the transport reads it
+func (form *Multipart) open(boundary string) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(form.write(writer, boundary))
+	}()
+	return reader
+}
+
+// write - writes the fields and files of the body
+func (form *Multipart) write(w io.Writer, boundary string) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(boundary); err != nil {
+		return err
+	}
+	for _, field := range form.Fields {
+		if err := writer.WriteField(field.Name, field.Value); err != nil {
+			return err
+		}
+	}
+	for _, file := range form.Files {
+		if err := writeFile(writer, file); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// writeFile - writes a file part of the body
+func writeFile(writer *multipart.Writer, file FormFile) error {
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(file.FieldName), escapeQuotes(file.FileName)))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	content, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("unable to open file %s. error: %w", file.FileName, err)
+	}
+	defer content.Close()
+	_, err = io.Copy(part, content)
+	return err
+}
+
+// quoteEscaper - escapes quotes and backslashes of header parameters, like the standard library does
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// escapeQuotes - escapes a header parameter value
+func escapeQuotes(value string) string {
+	return quoteEscaper.Replace(value)
+}
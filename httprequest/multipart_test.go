@@ -0,0 +1,71 @@
+package httprequest
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSendMultipart - tests multipart bodies are streamed with their fields and files and sent again on retries
+func TestSendMultipart(t *testing.T) {
+	check := assert.New(t)
+	dir, err := ioutil.TempDir("", "httprequest")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "accounts.csv")
+	check.Nil(ioutil.WriteFile(path, []byte("id,bic\n7eb322ba-57f6-465c-b600-79f26ac7fdc3,NWBKGB22\n"), 0600))
+
+	var uploads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		check.Nil(r.ParseMultipartForm(1 << 20))
+		check.Equal("csv", r.FormValue("format"))
+		file, header, err := r.FormFile("accounts")
+		check.Nil(err)
+		content, _ := ioutil.ReadAll(file)
+		uploads = append(uploads, header.Filename+":"+header.Header.Get("Content-Type")+":"+string(content))
+		if len(uploads) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	file := NewFormFile("accounts", path)
+	file.ContentType = "text/csv"
+	response, err := NewRequestHandler(nil).Send(&RequestSpecifications{
+		HTTPMethod: http.MethodPost,
+		URL:        server.URL,
+		Multipart:  &Multipart{Fields: []FormField{{Name: "format", Value: "csv"}}, Files: []FormFile{file}},
+	})
+	check.Nil(err)
+	check.Equal(http.StatusOK, response.StatusCode)
+	check.Equal(2, response.Attempts)
+	check.Len(uploads, 2)
+	check.Equal("accounts.csv:text/csv:id,bic\n7eb322ba-57f6-465c-b600-79f26ac7fdc3,NWBKGB22\n", uploads[1])
+}
+
+// TestSendMultipartOpenError - tests files failing to open abort the request
+func TestSendMultipartOpenError(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	openError := errors.New("permission denied")
+	_, err := NewRequestHandler(nil).Send(&RequestSpecifications{
+		HTTPMethod:     http.MethodPost,
+		URL:            server.URL,
+		DisableRetries: true,
+		Multipart: &Multipart{Files: []FormFile{{FieldName: "accounts", FileName: "accounts.csv", Open: func() (io.ReadCloser, error) {
+			return nil, openError
+		}}}},
+	})
+	check.True(errors.Is(err, openError))
+	check.True(strings.Contains(err.Error(), "unable to open file accounts.csv"))
+}
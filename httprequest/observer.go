@@ -0,0 +1,61 @@
+package httprequest
+
+import (
+	"context"
+	"time"
+)
+
+// RequestObserver - instrumentation point for the requests of a handler, like metrics or tracing integrations
+// OnRequestStart is called before the first attempt of a request, the returned context is used for the request, so
+// tracers can attach their span to it. OnAttempt is called after every attempt and OnRequestEnd once the request is
+// done. Observers are called by concurrent requests concurrently
+type RequestObserver interface {
+	OnRequestStart(ctx context.Context, request RequestInfo) context.Context
+	OnAttempt(ctx context.Context, request RequestInfo, attempt Attempt)
+	OnRequestEnd(ctx context.Context, request RequestInfo, result RequestResult)
+}
+
+// RequestInfo - describes an observed request, URL holds the query of the request
+type RequestInfo struct {
+	Operation string
+	Method    string
+	URL       string
+}
+
+// RequestResult - outcome of an observed request, StatusCode is the status code of the last attempt
+type RequestResult struct {
+	StatusCode int
+	Err        error
+	Attempts   int
+	Duration   time.Duration
+}
+
+// Observers - returns an observer passing every call on to the observers in order
+func Observers(observers ...RequestObserver) RequestObserver {
+	return multiObserver(append([]RequestObserver(nil), observers...))
+}
+
+// multiObserver - passes calls on to several observers
+type multiObserver []RequestObserver
+
+// OnRequestStart - passes the start on, each observer receives the context returned by the observer before it
+func (observers multiObserver) OnRequestStart(ctx context.Context, request RequestInfo) context.Context {
+	for _, observer := range observers {
+		ctx = observer.OnRequestStart(ctx, request)
+	}
+	return ctx
+}
+
+// OnAttempt - passes the attempt on
+func (observers multiObserver) OnAttempt(ctx context.Context, request RequestInfo, attempt Attempt) {
+	for _, observer := range observers {
+		observer.OnAttempt(ctx, request, attempt)
+	}
+}
+
+// OnRequestEnd - passes the end on
+func (observers multiObserver) OnRequestEnd(ctx context.Context, request RequestInfo, result RequestResult) {
+	for _, observer := range observers {
+		observer.OnRequestEnd(ctx, request, result)
+	}
+}
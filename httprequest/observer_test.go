@@ -0,0 +1,72 @@
+package httprequest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// observerKey - context key of the recording observer
+type observerKey struct{}
+
+// recordingObserver - observer recording the calls it receives
+type recordingObserver struct {
+	name     string
+	calls    []string
+	attempts []Attempt
+	contexts []context.Context
+	result   RequestResult
+}
+
+// OnRequestStart - records the start and adds the name of the observer to the context
+func (observer *recordingObserver) OnRequestStart(ctx context.Context, request RequestInfo) context.Context {
+	observer.calls = append(observer.calls, "start "+request.Operation+" "+request.Method)
+	return context.WithValue(ctx, observerKey{}, observer.name)
+}
+
+// OnAttempt - records the attempt
+func (observer *recordingObserver) OnAttempt(ctx context.Context, request RequestInfo, attempt Attempt) {
+	observer.calls = append(observer.calls, "attempt")
+	observer.attempts = append(observer.attempts, attempt)
+	observer.contexts = append(observer.contexts, ctx)
+}
+
+// OnRequestEnd - records the result
+func (observer *recordingObserver) OnRequestEnd(ctx context.Context, request RequestInfo, result RequestResult) {
+	observer.calls = append(observer.calls, "end")
+	observer.contexts = append(observer.contexts, ctx)
+	observer.result = result
+}
+
+// TestObserver - tests the observer is notified of the start, the attempts and the end of a request
+func TestObserver(t *testing.T) {
+	check := assert.New(t)
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	first, second := &recordingObserver{name: "first"}, &recordingObserver{name: "second"}
+	ctx := context.Background()
+	specs := &RequestSpecifications{Operation: "fetch", HTTPMethod: http.MethodGet, URL: server.URL, Context: ctx, Observer: Observers(first, second)}
+
+	statusCode, _, _, err := NewRequestHandler(nil).MakeRequest(specs)
+	check.Nil(err)
+	check.Equal(http.StatusOK, statusCode)
+	check.Equal([]string{"start fetch GET", "attempt", "attempt", "end"}, first.calls)
+	check.Equal(first.calls, second.calls)
+	check.Equal(http.StatusServiceUnavailable, first.attempts[0].StatusCode)
+	check.True(first.attempts[0].BackOff > 0)
+	check.Equal(RequestResult{StatusCode: http.StatusOK, Attempts: 2, Duration: first.result.Duration}, first.result)
+	check.Equal("second", first.contexts[0].Value(observerKey{}))
+	check.Equal("second", second.contexts[2].Value(observerKey{}))
+	check.Equal(ctx, specs.Context)
+}
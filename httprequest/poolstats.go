@@ -0,0 +1,83 @@
+package httprequest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// PoolStats - live statistics of the connection pool of a handler, for telling pool exhaustion from slow servers
+// OpenConnections counts the connections dialed by the default transport which are not closed yet, it is zero for
+// custom http clients. IdleConnections estimates the open connections without an attempt in flight.
+// NewConnections and ReusedConnections count the connections the attempts were sent on, ReuseRatio is the share
+// of attempts sent on reused connections
+type PoolStats struct {
+	OpenConnections   int64
+	IdleConnections   int64
+	InFlightRequests  int64
+	NewConnections    int64
+	ReusedConnections int64
+	ReuseRatio        float64
+}
+
+// poolStats - counters of the pool statistics, updated atomically by concurrent requests
+type poolStats struct {
+	open     int64
+	inflight int64
+	created  int64
+	reused   int64
+}
+
+// PoolStats - returns the live statistics of the connection pool of the handler
+func (r *RequestHandler) PoolStats() PoolStats {
+	stats := PoolStats{
+		OpenConnections:   atomic.LoadInt64(&r.stats.open),
+		InFlightRequests:  atomic.LoadInt64(&r.stats.inflight),
+		NewConnections:    atomic.LoadInt64(&r.stats.created),
+		ReusedConnections: atomic.LoadInt64(&r.stats.reused),
+	}
+	if idle := stats.OpenConnections - stats.InFlightRequests; idle > 0 {
+		stats.IdleConnections = idle
+	}
+	if used := stats.NewConnections + stats.ReusedConnections; used > 0 {
+		stats.ReuseRatio = float64(stats.ReusedConnections) / float64(used)
+	}
+	return stats
+}
+
+// countConnection - counts the connection an attempt was sent on
+func (stats *poolStats) countConnection(reused bool) {
+	if reused {
+		atomic.AddInt64(&stats.reused, 1)
+	} else {
+		atomic.AddInt64(&stats.created, 1)
+	}
+}
+
+// countingDialer - wraps a dial function, counting the connections until they are closed
+func (stats *poolStats) countingDialer(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		atomic.AddInt64(&stats.open, 1)
+		return &countedConn{Conn: conn, stats: stats}, nil
+	}
+}
+
+// countedConn - connection which is uncounted once it is closed
+type countedConn struct {
+	net.Conn
+	stats *poolStats
+	once  sync.Once
+}
+
+// Close - closes the connection and uncounts it
+func (conn *countedConn) Close() error {
+	conn.once.Do(func() {
+		atomic.AddInt64(&conn.stats.open, -1)
+	})
+	return conn.Conn.Close()
+}
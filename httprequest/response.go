@@ -0,0 +1,95 @@
+package httprequest
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Response - outcome of a request sent with Send
+// StatusCode, Body and Headers are those of the last attempt, Attempts counts the attempts sent and Duration is the
+// time spent on the request including back offs. Trace holds the connection timings of the last attempt
+type Response struct {
+	StatusCode int
+	Body       []byte
+	Headers    http.Header
+	Attempts   int
+	Duration   time.Duration
+	Trace      TraceInfo
+}
+
+// TraceInfo - connection timings of an attempt, durations of steps skipped for reused connections are zero
+// TimeToFirstByte is measured from the start of the attempt to the first response byte
+type TraceInfo struct {
+	DNSLookup        time.Duration
+	Connect          time.Duration
+	TLSHandshake     time.Duration
+	TimeToFirstByte  time.Duration
+	ConnectionReused bool
+}
+
+// tracer - records the trace info of an attempt, the hooks of the http client may run on other goroutines
+type tracer struct {
+	mutex        sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	gotConn      bool
+	info         TraceInfo
+}
+
+// newTracer - returns a tracer of an attempt starting now
+func newTracer() *tracer {
+	return &tracer{start: time.Now()}
+}
+
+// clientTrace - returns the hooks recording the trace info
+func (t *tracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.record(func() { t.dnsStart = time.Now() }) },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.record(func() { t.info.DNSLookup = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(string, string) { t.record(func() { t.connectStart = time.Now() }) },
+		ConnectDone: func(string, string, error) {
+			t.record(func() { t.info.Connect = time.Since(t.connectStart) })
+		},
+		TLSHandshakeStart: func() { t.record(func() { t.tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.record(func() { t.info.TLSHandshake = time.Since(t.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.record(func() {
+				t.gotConn = true
+				t.info.ConnectionReused = info.Reused
+			})
+		},
+		GotFirstResponseByte: func() {
+			t.record(func() { t.info.TimeToFirstByte = time.Since(t.start) })
+		},
+	}
+}
+
+// record - changes the tracer under its mutex
+func (t *tracer) record(change func()) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	change()
+}
+
+// traceInfo - returns the recorded trace info
+func (t *tracer) traceInfo() TraceInfo {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.info
+}
+
+// connection - reports whether the attempt got a connection and whether it was reused
+func (t *tracer) connection() (bool, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.gotConn, t.info.ConnectionReused
+}
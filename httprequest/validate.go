@@ -0,0 +1,75 @@
+package httprequest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"accountlib/errors"
+)
+
+// minTimeout - shortest timeout of an attempt accepted by Validate
+const minTimeout = time.Millisecond
+
+// supportedMethods - http methods of the requests sent by the handler
+var supportedMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodHead:   true,
+	http.MethodPost:   true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// Validate - checks the specifications before a request is sent, returning a *accounterrors.SpecificationError for
+// an empty or malformed url, an unsupported method, a body of a method without body, a multipart body together with
+// params, negative retries or timeouts and timeouts below a millisecond
+func (specs *RequestSpecifications) Validate() error {
+	if specs.URL == "" {
+		return &accounterrors.SpecificationError{Field: "URL", Message: "is empty"}
+	}
+	parsed, err := url.Parse(specs.URL)
+	if err != nil {
+		return &accounterrors.SpecificationError{Field: "URL", Message: fmt.Sprintf("is malformed: %s", err.Error())}
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return &accounterrors.SpecificationError{Field: "URL", Message: fmt.Sprintf("%q is not an absolute http or https url", specs.URL)}
+	}
+	if !supportedMethods[specs.HTTPMethod] {
+		return &accounterrors.SpecificationError{Field: "HTTPMethod", Message: fmt.Sprintf("%q is not supported", specs.HTTPMethod)}
+	}
+	if len(specs.Params) > 0 && !hasBody(specs.HTTPMethod) {
+		return &accounterrors.SpecificationError{Field: "Params", Message: fmt.Sprintf("are not allowed for %s requests", specs.HTTPMethod)}
+	}
+	if specs.Multipart != nil && !hasBody(specs.HTTPMethod) {
+		return &accounterrors.SpecificationError{Field: "Multipart", Message: fmt.Sprintf("is not allowed for %s requests", specs.HTTPMethod)}
+	}
+	if specs.Multipart != nil && len(specs.Params) > 0 {
+		return &accounterrors.SpecificationError{Field: "Multipart", Message: "is not allowed together with Params"}
+	}
+	if specs.RetryCount < 0 {
+		return &accounterrors.SpecificationError{Field: "RetryCount", Message: "is negative"}
+	}
+	if specs.Timeout < 0 {
+		return &accounterrors.SpecificationError{Field: "Timeout", Message: "is negative"}
+	}
+	if specs.TimeoutDuration < 0 {
+		return &accounterrors.SpecificationError{Field: "TimeoutDuration", Message: "is negative"}
+	}
+	if specs.TimeoutDuration > 0 && specs.TimeoutDuration < minTimeout {
+		// such timeouts fail every attempt, they are durations mistaken for seconds
+		return &accounterrors.SpecificationError{Field: "TimeoutDuration", Message: fmt.Sprintf("is below %s", minTimeout)}
+	}
+	if policy := specs.RetryPolicy; policy != nil {
+		if policy.Attempts < 0 {
+			return &accounterrors.SpecificationError{Field: "RetryPolicy.Attempts", Message: "is negative"}
+		}
+		if policy.BackOff < 0 {
+			return &accounterrors.SpecificationError{Field: "RetryPolicy.BackOff", Message: "is negative"}
+		}
+		if policy.Budget < 0 {
+			return &accounterrors.SpecificationError{Field: "RetryPolicy.Budget", Message: "is negative"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package accountlib
+
+import "github.com/google/uuid"
+
+// IDGenerator - generates the identifiers created by the library, like the account ids of builders and the ids of
+// queued mutations. Account ids have to be uuids, other ids may use any format like ulids
+// Generators are called by concurrent calls concurrently
+type IDGenerator func() string
+
+// UUIDGenerator - default id generator returning random uuids
+func UUIDGenerator() string {
+	return uuid.New().String()
+}
+
+// newID - returns an id of the generator of the client
+func (client *Client) newID() string {
+	if client.idGenerator == nil {
+		return UUIDGenerator()
+	}
+	return client.idGenerator()
+}
@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package accountlib_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"accountlib"
+)
+
+// integration test constants
+const (
+	integrationURLEnv     = "ACCOUNTLIB_INTEGRATION_URL"
+	defaultIntegrationURL = "http://localhost:8080"
+	startupTimeout        = 60 * time.Second
+	startupPollInterval   = time.Second
+)
+
+// TestIntegration - runs the contract test suite against the account api container of docker-compose
+// Run it using make integration
+func TestIntegration(t *testing.T) {
+	baseURL := os.Getenv(integrationURLEnv)
+	if baseURL == "" {
+		baseURL = defaultIntegrationURL
+	}
+	waitForAPI(t, baseURL)
+	suite.Run(t, &ContractTestSuite{
+		client: accountlib.NewClient(&accountlib.ClientOptions{BaseURL: baseURL, StrictDecoding: true}),
+	})
+}
+
+// waitForAPI - waits for the health endpoint of the api, since the container takes a while to start
+func waitForAPI(t *testing.T, baseURL string) {
+	deadline := time.Now().Add(startupTimeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(baseURL + "/v1/health")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+		time.Sleep(startupPollInterval)
+	}
+	t.Fatalf("account api at %s did not become healthy within %s", baseURL, startupTimeout)
+}
@@ -0,0 +1,32 @@
+package listfilter
+
+import "accountlib"
+
+// Filters - filters supported by the list endpoint, mapped to the account value they match
+// The fakes of the api share them, so the fake server and the in-memory client filter alike
+var Filters = map[string]func(account *accountlib.AccountData) string{
+	"organisation_id": func(account *accountlib.AccountData) string { return account.OrganisationID },
+	"bank_id": func(account *accountlib.AccountData) string {
+		if account.Attributes == nil {
+			return ""
+		}
+		return account.Attributes.BankID
+	},
+	"country": func(account *accountlib.AccountData) string {
+		if account.Attributes == nil || account.Attributes.Country == nil {
+			return ""
+		}
+		return string(*account.Attributes.Country)
+	},
+}
+
+// Match - reports whether the account matches all supported filters, filter returns the expected value of a filter
+// by name and an empty value for filters which are not set
+func Match(account *accountlib.AccountData, filter func(name string) string) bool {
+	for name, value := range Filters {
+		if expected := filter(name); expected != "" && value(account) != expected {
+			return false
+		}
+	}
+	return true
+}
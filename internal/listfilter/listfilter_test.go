@@ -0,0 +1,46 @@
+package listfilter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+)
+
+// TestMatch - tests accounts match when every set filter matches and unset filters are ignored
+func TestMatch(t *testing.T) {
+	check := assert.New(t)
+	country := accountlib.CountryCode("GB")
+	account := &accountlib.AccountData{
+		OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		Attributes:     &accountlib.AccountAttributes{BankID: "400300", Country: &country},
+	}
+	for filter, expected := range map[string]bool{
+		"":                  true,
+		"country=GB":        true,
+		"country=FR":        false,
+		"bank_id=400300":    true,
+		"bank_id=400301":    false,
+		"unknown=something": true,
+	} {
+		name, value := "", ""
+		if filter != "" {
+			parts := strings.SplitN(filter, "=", 2)
+			name, value = parts[0], parts[1]
+		}
+		check.Equal(expected, Match(account, func(filterName string) string {
+			if filterName == name {
+				return value
+			}
+			return ""
+		}), filter)
+	}
+
+	// accounts without attributes only match filters on the organisation
+	check.False(Match(&accountlib.AccountData{}, func(name string) string { return map[string]string{"country": "GB"}[name] }))
+	check.True(Match(&accountlib.AccountData{OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"}, func(name string) string {
+		return map[string]string{"organisation_id": "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"}[name]
+	}))
+}
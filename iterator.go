@@ -0,0 +1,144 @@
+package accountlib
+
+import (
+	"context"
+)
+
+// AccountIterator - iterates over the accounts of every page of a list, pages are listed on demand
+// With ClientOptions.PrefetchPages the next page is listed in the background while the current one is processed
+type AccountIterator struct {
+	pager   *pager
+	page    []AccountData
+	index   int
+	account *AccountData
+	err     error
+}
+
+// Iterate - returns an iterator over the accounts of every page starting at the page of the list params
+// Call options apply to every page, Close releases a prefetched page when the iteration is stopped early
+//
+//	iterator := client.Iterate(ctx, listParams)
+//	defer iterator.Close()
+//	for iterator.Next() {
+//		account := iterator.Account()
+//	}
+//	if err := iterator.Err(); err != nil {
+func (client *Client) Iterate(ctx context.Context, listParams ListParams, options ...CallOption) *AccountIterator {
+	return &AccountIterator{pager: client.newPager(ctx, listParams, options)}
+}
+
+// Next - advances to the next account, returning false at the end of the list or when listing a page failed
+func (iterator *AccountIterator) Next() bool {
+	for iterator.index >= len(iterator.page) {
+		if iterator.err != nil {
+			return false
+		}
+		page, err := iterator.pager.next()
+		if err != nil {
+			iterator.err = err
+			return false
+		}
+		if page == nil {
+			return false
+		}
+		iterator.page, iterator.index = page.accountList.Data, 0
+	}
+	iterator.account = &iterator.page[iterator.index]
+	iterator.index++
+	return true
+}
+
+// Account - returns the current account
+func (iterator *AccountIterator) Account() *AccountData {
+	return iterator.account
+}
+
+// Err - returns the error listing a page, nil when the iteration ended at the end of the list
+func (iterator *AccountIterator) Err() error {
+	return iterator.err
+}
+
+// Close - stops the iteration, cancelling the listing of a prefetched page
+func (iterator *AccountIterator) Close() {
+	iterator.pager.close()
+	iterator.page, iterator.index = nil, 0
+}
+
+// page - a listed page with its page number, or the error listing it
+type page struct {
+	accountList *AccountList
+	pageNumber  int
+	err         error
+}
+
+// pager - lists the pages of a list one after another, at most one page is prefetched
+type pager struct {
+	client     *Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	listParams ListParams
+	options    []CallOption
+	prefetched chan page
+	done       bool
+}
+
+// newPager - returns a pager starting at the page of the list params, a zero page size lists pages of 100 accounts
+func (client *Client) newPager(ctx context.Context, listParams ListParams, options []CallOption) *pager {
+	if listParams.PageSize <= 0 {
+		listParams.PageSize = listAllPageSize
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &pager{client: client, ctx: ctx, cancel: cancel, listParams: listParams, options: options}
+}
+
+// next - returns the next page, nil after the last page
+func (pager *pager) next() (*page, error) {
+	if pager.done {
+		return nil, nil
+	}
+	var current page
+	if pager.prefetched != nil {
+		current = <-pager.prefetched
+		pager.prefetched = nil
+	} else {
+		current = pager.list(pager.listParams)
+	}
+	if current.err != nil {
+		pager.close()
+		return nil, current.err
+	}
+
+	pager.listParams.PageNumber++
+	if lastPage(current.accountList, pager.listParams.PageSize) {
+		pager.close()
+	} else if pager.client.prefetchPages {
+		prefetched := make(chan page, 1)
+		listParams := pager.listParams
+		go func() {
+			prefetched <- pager.list(listParams)
+		}()
+		pager.prefetched = prefetched
+	}
+	return &current, nil
+}
+
+// list - lists a page
+func (pager *pager) list(listParams ListParams) page {
+	accountList, err := pager.client.List(pager.ctx, listParams, pager.options...)
+	return page{accountList: accountList, pageNumber: listParams.PageNumber, err: err}
+}
+
+// close - ends the paging, cancelling the listing of a prefetched page
+func (pager *pager) close() {
+	pager.done = true
+	pager.prefetched = nil
+	pager.cancel()
+}
+
+// lastPage - reports whether a page is the last one, by its next link or else by a page not being full
+func lastPage(accountList *AccountList, pageSize int) bool {
+	if accountList.Links != nil && (accountList.Links.Next != "" || accountList.Links.Last != "") {
+		return accountList.Links.Next == ""
+	}
+	return len(accountList.Data) < pageSize
+}
@@ -0,0 +1,84 @@
+package accountlib_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/fakeserver"
+)
+
+// TestIterate - tests the iterator returns the accounts of every page, with and without prefetching
+func TestIterate(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	for i := 0; i < 25; i++ {
+		server.AddAccount(accountlib.AccountData{ID: fmt.Sprintf("%08d-0000-4000-8000-000000000000", i), OrganisationID: "org"})
+	}
+
+	for _, prefetch := range []bool{false, true} {
+		client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL, PrefetchPages: prefetch})
+		iterator := client.Iterate(context.Background(), accountlib.ListParams{PageSize: 10})
+		var ids []string
+		for iterator.Next() {
+			ids = append(ids, iterator.Account().ID)
+		}
+		iterator.Close()
+		check.Nil(iterator.Err())
+		check.Len(ids, 25)
+		check.Equal("00000024-0000-4000-8000-000000000000", ids[24])
+	}
+}
+
+// TestIteratePrefetch - tests the next page is listed while the current one is processed
+func TestIteratePrefetch(t *testing.T) {
+	check := assert.New(t)
+	var mutex sync.Mutex
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		pages = append(pages, r.URL.Query().Get("page[number]"))
+		mutex.Unlock()
+		if r.URL.Query().Get("page[number]") == "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"id": "1"}, {"id": "2"}]}`))
+	}))
+	defer server.Close()
+	requestedPages := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string(nil), pages...)
+	}
+
+	// without prefetching pages are listed when they are needed
+	iterator := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL}).Iterate(context.Background(), accountlib.ListParams{PageSize: 2})
+	check.True(iterator.Next())
+	time.Sleep(20 * time.Millisecond)
+	check.Equal([]string{"0"}, requestedPages())
+	iterator.Close()
+	check.False(iterator.Next())
+
+	// with prefetching the next page is listed right away, errors are returned when the page is reached
+	pages = nil
+	iterator = accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL, PrefetchPages: true}).Iterate(context.Background(), accountlib.ListParams{PageSize: 2})
+	defer iterator.Close()
+	check.True(iterator.Next())
+	check.Eventually(func() bool { return len(requestedPages()) == 2 }, time.Second, time.Millisecond)
+	check.Equal([]string{"0", "1"}, requestedPages())
+	for i := 0; i < 3; i++ {
+		check.True(iterator.Next())
+	}
+	check.False(iterator.Next())
+	check.Contains(iterator.Err().Error(), "bad request")
+	check.Equal([]string{"0", "1", "2"}, requestedPages())
+}
@@ -0,0 +1,186 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrPoolShutdown - returned by Enqueue once the pool is shut down
+var ErrPoolShutdown = errors.New("job pool is shut down")
+
+// Job - a unit of work run by a pool, Run receives a context cancelled when the job is cancelled
+type Job struct {
+	Name  string
+	Run   func(ctx context.Context) error
+	Retry RetryPolicy
+}
+
+// RetryPolicy - controls how often a failed job is run again
+// Attempts is the number of runs of a job, a zero policy runs it once. BackOff is the wait before the next attempt,
+// doubling after every attempt. Retryable decides which errors are retried, by default every error except the
+// errors of a cancelled context
+type RetryPolicy struct {
+	Attempts  int
+	BackOff   time.Duration
+	Retryable func(err error) bool
+}
+
+// Handle - tracks an enqueued job
+type Handle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	job    Job
+	done   chan struct{}
+	err    error
+}
+
+// Done - returns a channel closed once the job finished, successfully or not
+func (handle *Handle) Done() <-chan struct{} {
+	return handle.done
+}
+
+// Err - waits for the job to finish and returns the error of its last attempt
+func (handle *Handle) Err() error {
+	<-handle.done
+	return handle.err
+}
+
+// Cancel - cancels the job, a queued job fails without running and a running job has its context cancelled
+func (handle *Handle) Cancel() {
+	handle.cancel()
+}
+
+// Pool - runs jobs with a bounded number of workers
+// Workers are started when jobs are enqueued and stop when the queue is empty, so an idle pool holds no goroutines
+type Pool struct {
+	mutex    sync.Mutex
+	workers  int
+	running  int
+	queue    []*Handle
+	pending  map[*Handle]struct{}
+	finished sync.WaitGroup
+	shutdown bool
+}
+
+// NewPool - returns a pool running at most workers jobs at a time, at least one
+func NewPool(workers int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Pool{workers: workers, pending: make(map[*Handle]struct{})}
+}
+
+// Enqueue - queues a job, it runs once a worker is free. Cancelling ctx cancels the job
+func (pool *Pool) Enqueue(ctx context.Context, job Job) (*Handle, error) {
+	if job.Run == nil {
+		return nil, errors.New("invalid job: run must not be nil")
+	}
+	jobCtx, cancel := context.WithCancel(ctx)
+	handle := &Handle{ctx: jobCtx, cancel: cancel, job: job, done: make(chan struct{})}
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	if pool.shutdown {
+		cancel()
+		return nil, ErrPoolShutdown
+	}
+	pool.queue = append(pool.queue, handle)
+	pool.pending[handle] = struct{}{}
+	pool.finished.Add(1)
+	if pool.running < pool.workers {
+		pool.running++
+		go pool.work()
+	}
+	return handle, nil
+}
+
+// Shutdown - stops accepting jobs and waits for the queued and running jobs to finish
+// When ctx is done first, the remaining jobs are cancelled and the error of ctx is returned
+func (pool *Pool) Shutdown(ctx context.Context) error {
+	pool.mutex.Lock()
+	pool.shutdown = true
+	pool.mutex.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		pool.finished.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		pool.mutex.Lock()
+		for handle := range pool.pending {
+			handle.cancel()
+		}
+		pool.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// work - runs queued jobs until the queue is empty
+func (pool *Pool) work() {
+	for {
+		pool.mutex.Lock()
+		if len(pool.queue) == 0 {
+			pool.running--
+			pool.mutex.Unlock()
+			return
+		}
+		handle := pool.queue[0]
+		pool.queue[0] = nil
+		pool.queue = pool.queue[1:]
+		pool.mutex.Unlock()
+
+		handle.err = run(handle.ctx, handle.job)
+		handle.cancel()
+		close(handle.done)
+
+		pool.mutex.Lock()
+		delete(pool.pending, handle)
+		pool.mutex.Unlock()
+		pool.finished.Done()
+	}
+}
+
+// run - runs a job, retrying it according to its retry policy
+func run(ctx context.Context, job Job) error {
+	attempts := job.Retry.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backOff := job.Retry.BackOff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err == nil {
+				err = ctxErr
+			}
+			return err
+		}
+		if err = job.Run(ctx); err == nil || attempt >= attempts || !retryable(job.Retry, err) {
+			return err
+		}
+
+		timer := time.NewTimer(backOff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("job %s cancelled after %d attempts. error: %w", job.Name, attempt, err)
+		}
+		backOff *= 2
+	}
+}
+
+// retryable - reports whether the retry policy retries the error
+func retryable(policy RetryPolicy, err error) bool {
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
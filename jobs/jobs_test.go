@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPoolWorkers - tests no more jobs than workers run at a time and every job runs
+func TestPoolWorkers(t *testing.T) {
+	check := assert.New(t)
+	pool := NewPool(3)
+	running, maxRunning, runs := int32(0), int32(0), int32(0)
+
+	var handles []*Handle
+	for i := 0; i < 20; i++ {
+		handle, err := pool.Enqueue(context.Background(), Job{Run: func(ctx context.Context) error {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&runs, 1)
+			return nil
+		}})
+		check.Nil(err)
+		handles = append(handles, handle)
+	}
+	for _, handle := range handles {
+		check.Nil(handle.Err())
+	}
+	check.Equal(int32(20), atomic.LoadInt32(&runs))
+	check.True(atomic.LoadInt32(&maxRunning) <= 3)
+}
+
+// TestPoolRetry - tests failed jobs are run again according to their retry policy
+func TestPoolRetry(t *testing.T) {
+	check := assert.New(t)
+	pool := NewPool(1)
+	attempts := 0
+	jobError := errors.New("failed")
+
+	handle, err := pool.Enqueue(context.Background(), Job{
+		Run: func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return jobError
+			}
+			return nil
+		},
+		Retry: RetryPolicy{Attempts: 3, BackOff: time.Millisecond},
+	})
+	check.Nil(err)
+	check.Nil(handle.Err())
+	check.Equal(3, attempts)
+
+	attempts = 0
+	handle, _ = pool.Enqueue(context.Background(), Job{
+		Run: func(ctx context.Context) error {
+			attempts++
+			return jobError
+		},
+		Retry: RetryPolicy{Attempts: 3, Retryable: func(err error) bool { return false }},
+	})
+	check.Equal(jobError, handle.Err())
+	check.Equal(1, attempts)
+
+	_, err = pool.Enqueue(context.Background(), Job{})
+	check.EqualError(err, "invalid job: run must not be nil")
+}
+
+// TestPoolCancel - tests cancelled jobs fail with the error of their context
+func TestPoolCancel(t *testing.T) {
+	check := assert.New(t)
+	pool := NewPool(1)
+	release := make(chan struct{})
+
+	blocking, _ := pool.Enqueue(context.Background(), Job{Run: func(ctx context.Context) error {
+		<-release
+		return nil
+	}})
+	runs := int32(0)
+	queued, _ := pool.Enqueue(context.Background(), Job{Run: func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}})
+	queued.Cancel()
+	close(release)
+	check.Nil(blocking.Err())
+	check.Equal(context.Canceled, queued.Err())
+	check.Equal(int32(0), atomic.LoadInt32(&runs))
+}
+
+// TestPoolShutdown - tests shutdown waits for queued jobs and rejects new ones
+func TestPoolShutdown(t *testing.T) {
+	check := assert.New(t)
+	pool := NewPool(1)
+	runs := int32(0)
+	for i := 0; i < 5; i++ {
+		_, err := pool.Enqueue(context.Background(), Job{Run: func(ctx context.Context) error {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&runs, 1)
+			return nil
+		}})
+		check.Nil(err)
+	}
+
+	check.Nil(pool.Shutdown(context.Background()))
+	check.Equal(int32(5), atomic.LoadInt32(&runs))
+	_, err := pool.Enqueue(context.Background(), Job{Run: func(ctx context.Context) error { return nil }})
+	check.Equal(ErrPoolShutdown, err)
+}
+
+// TestPoolShutdownTimeout - tests running jobs are cancelled when shutdown runs out of time
+func TestPoolShutdownTimeout(t *testing.T) {
+	check := assert.New(t)
+	pool := NewPool(1)
+	handle, _ := pool.Enqueue(context.Background(), Job{Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	check.Equal(context.DeadlineExceeded, pool.Shutdown(ctx))
+	check.Equal(context.Canceled, handle.Err())
+}
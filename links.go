@@ -0,0 +1,188 @@
+package accountlib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Links - json:api links of a resource or a response, unset links are empty
+// Lists hold the links for paging through the pages, First, Last, Next and Prev
+// Resources hold the link of the resource in Self and, where the api provides it, the link of the fetched version in Version
+type Links struct {
+	Self    string `json:"self,omitempty"`
+	Version string `json:"version,omitempty"`
+	First   string `json:"first,omitempty"`
+	Last    string `json:"last,omitempty"`
+	Next    string `json:"next,omitempty"`
+	Prev    string `json:"prev,omitempty"`
+}
+
+// Relationship - json:api relationship of a resource to other resources, like the master account of a virtual account
+type Relationship struct {
+	Data  []ResourceIdentifier `json:"data"`
+	Links *Links               `json:"links,omitempty"`
+}
+
+// masterAccountRelationship - name of the relationship of a virtual account to its master account
+const masterAccountRelationship = "master_account"
+
+// AccountRelationships - typed relationships of accounts to create
+type AccountRelationships struct {
+	// MasterAccount - relationship of a virtual account to its master account
+	MasterAccount *Relationship `json:"master_account,omitempty"`
+}
+
+// NewMasterAccountRelationship - returns the relationship to the master account with the given id
+func NewMasterAccountRelationship(accountID string) *Relationship {
+	return &Relationship{Data: []ResourceIdentifier{{Type: defaultAccountType, ID: accountID}}}
+}
+
+// ResourceIdentifier - identifies a related resource by its type and id
+type ResourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// UnmarshalJSON - decodes a relationship, the data of relationships to a single resource is decoded into a one item slice
+func (relationship *Relationship) UnmarshalJSON(data []byte) error {
+	var decoded struct {
+		Data  json.RawMessage `json:"data"`
+		Links *Links          `json:"links"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	relationship.Links = decoded.Links
+	relationship.Data = nil
+
+	switch raw := bytes.TrimSpace(decoded.Data); {
+	case len(raw) == 0 || bytes.Equal(raw, []byte("null")):
+		return nil
+	case raw[0] == '{':
+		identifier := ResourceIdentifier{}
+		if err := json.Unmarshal(raw, &identifier); err != nil {
+			return err
+		}
+		relationship.Data = []ResourceIdentifier{identifier}
+		return nil
+	default:
+		return json.Unmarshal(raw, &relationship.Data)
+	}
+}
+
+// Clone - returns a deep copy of the links
+func (links *Links) Clone() *Links {
+	if links == nil {
+		return nil
+	}
+	clone := *links
+	return &clone
+}
+
+// Clone - returns a deep copy of the relationships
+func (relationships *AccountRelationships) Clone() *AccountRelationships {
+	if relationships == nil {
+		return nil
+	}
+	clone := *relationships
+	clone.MasterAccount = relationships.MasterAccount.Clone()
+	return &clone
+}
+
+// Clone - returns a deep copy of the relationship
+func (relationship *Relationship) Clone() *Relationship {
+	if relationship == nil {
+		return nil
+	}
+	clone := *relationship
+	if relationship.Data != nil {
+		clone.Data = append([]ResourceIdentifier(nil), relationship.Data...)
+	}
+	clone.Links = relationship.Links.Clone()
+	return &clone
+}
+
+// MasterAccount - returns the master account of a virtual account, nil for accounts without master account
+func (data *AccountData) MasterAccount() *ResourceIdentifier {
+	if data == nil {
+		return nil
+	}
+	relationship, ok := data.Relationships[masterAccountRelationship]
+	if !ok || len(relationship.Data) == 0 {
+		return nil
+	}
+	identifier := relationship.Data[0]
+	return &identifier
+}
+
+// createRelationships - returns the typed relationships of the account for recreating it
+func (data *AccountData) createRelationships() *AccountRelationships {
+	relationship, ok := data.Relationships[masterAccountRelationship]
+	if !ok || len(relationship.Data) == 0 {
+		return nil
+	}
+	return &AccountRelationships{MasterAccount: &Relationship{Data: append([]ResourceIdentifier(nil), relationship.Data...)}}
+}
+
+// cloneRelationships - copies relationships by name
+func cloneRelationships(relationships map[string]Relationship) map[string]Relationship {
+	if relationships == nil {
+		return nil
+	}
+	clone := make(map[string]Relationship, len(relationships))
+	for name, relationship := range relationships {
+		clone[name] = *relationship.Clone()
+	}
+	return clone
+}
+
+// SelfLink - returns the link of the account, empty when the api did not provide it
+func (data *AccountData) SelfLink() string {
+	if data == nil || data.Links == nil {
+		return ""
+	}
+	return data.Links.Self
+}
+
+// VersionLink - returns the link of the fetched version of the account, empty when the api did not provide it
+func (data *AccountData) VersionLink() string {
+	if data == nil || data.Links == nil {
+		return ""
+	}
+	return data.Links.Version
+}
+
+// FetchLink - returns the account details of an account link, like the self link of an account
+// Relative links are resolved against the base url, absolute links have to point to the host of the base url,
+// so credentials of the client are never sent elsewhere
+func (client *Client) FetchLink(ctx context.Context, link string, options ...CallOption) (*AccountData, error) {
+	requestURL, err := client.resolveLink(link)
+	if err != nil {
+		return nil, err
+	}
+	accountData, _, err := client.fetchURL(ctx, requestURL, options)
+	return accountData, err
+}
+
+// resolveLink - returns the url of a link relative to the base url
+func (client *Client) resolveLink(link string) (string, error) {
+	if link == "" {
+		return "", fmt.Errorf("invalid link: empty")
+	}
+	base, err := url.Parse(client.baseURL + "/")
+	if err != nil {
+		return "", fmt.Errorf("invalid base url: %w", err)
+	}
+	reference, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid link %q: %w", link, err)
+	}
+	resolved := base.ResolveReference(reference)
+	if resolved.Scheme != base.Scheme || resolved.Host != base.Host {
+		return "", fmt.Errorf("invalid link %q: not below the base url %s", link, client.baseURL)
+	}
+	return resolved.String(), nil
+}
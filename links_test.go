@@ -0,0 +1,176 @@
+package accountlib
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRelationshipUnmarshal - tests relationships to one and to many resources
+func TestRelationshipUnmarshal(t *testing.T) {
+	check := assert.New(t)
+	relationships := map[string]Relationship{}
+	err := json.Unmarshal([]byte(`{
+		"master_account": {"data": [{"type": "accounts", "id": "a52d13a4-f435-4c00-cfad-f5e7ac5972df"}]},
+		"owner": {"data": {"type": "organisations", "id": "35eedc2c-0318-40dc-a090-d6f42e7b2754"}, "links": {"self": "/v1/organisations/35eedc2c"}},
+		"empty": {"data": null}
+	}`), &relationships)
+	check.Nil(err)
+	check.Equal(map[string]Relationship{
+		"master_account": {Data: []ResourceIdentifier{{Type: "accounts", ID: "a52d13a4-f435-4c00-cfad-f5e7ac5972df"}}},
+		"owner": {
+			Data:  []ResourceIdentifier{{Type: "organisations", ID: "35eedc2c-0318-40dc-a090-d6f42e7b2754"}},
+			Links: &Links{Self: "/v1/organisations/35eedc2c"},
+		},
+		"empty": {},
+	}, relationships)
+	check.NotNil(json.Unmarshal([]byte(`{"data": "accounts"}`), &Relationship{}))
+}
+
+// TestLinks - tests the links of account and list responses are decoded
+func TestLinks(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/organisation/accounts" {
+			_, _ = w.Write([]byte(`{"data": [{"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "links": {"self": "/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}],
+				"links": {"self": "/v1/organisation/accounts?page[number]=1", "first": "/v1/organisation/accounts?page[number]=0",
+				"last": "/v1/organisation/accounts?page[number]=2", "next": "/v1/organisation/accounts?page[number]=2",
+				"prev": "/v1/organisation/accounts?page[number]=0"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+			"relationships": {"master_account": {"data": [{"type": "accounts", "id": "a52d13a4-f435-4c00-cfad-f5e7ac5972df"}]}}},
+			"links": {"self": "/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, StrictDecoding: true})
+
+	accountData, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal(&Links{Self: "/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3"}, accountData.Links)
+	check.Equal([]ResourceIdentifier{{Type: "accounts", ID: "a52d13a4-f435-4c00-cfad-f5e7ac5972df"}}, accountData.Relationships["master_account"].Data)
+
+	accountList, err := client.List(context.Background(), ListParams{PageNumber: 1})
+	check.Nil(err)
+	check.Equal(&Links{
+		Self:  "/v1/organisation/accounts?page[number]=1",
+		First: "/v1/organisation/accounts?page[number]=0",
+		Last:  "/v1/organisation/accounts?page[number]=2",
+		Next:  "/v1/organisation/accounts?page[number]=2",
+		Prev:  "/v1/organisation/accounts?page[number]=0",
+	}, accountList.Links)
+	check.Equal("/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3", accountList.Data[0].Links.Self)
+}
+
+// TestLinksCloneDiff - tests links and relationships are deep copied and only relationships are compared
+func TestLinksCloneDiff(t *testing.T) {
+	check := assert.New(t)
+	account := &AccountData{
+		ID:            "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		Relationships: map[string]Relationship{"master_account": {Data: []ResourceIdentifier{{Type: "accounts", ID: "a52d13a4-f435-4c00-cfad-f5e7ac5972df"}}}},
+		Links:         &Links{Self: "/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3"},
+	}
+	clone := account.Clone()
+	clone.Links.Self = "/elsewhere"
+	check.Equal("/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3", account.Links.Self)
+	check.True(account.Equal(clone))
+
+	clone.Relationships["master_account"].Data[0].ID = "5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a"
+	check.Equal("a52d13a4-f435-4c00-cfad-f5e7ac5972df", account.Relationships["master_account"].Data[0].ID)
+	changes := Diff(account, clone)
+	check.Len(changes, 1)
+	check.Equal("relationships.master_account", changes[0].Path)
+}
+
+// TestFetchLink - tests accounts are fetched by their self and version links
+func TestFetchLink(t *testing.T) {
+	check := assert.New(t)
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "version": 2,
+			"links": {"self": "/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+			"version": "/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3?version=2"}}}`))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, StrictDecoding: true})
+
+	accountData, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal("/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3", accountData.SelfLink())
+	check.Equal("/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3?version=2", accountData.VersionLink())
+
+	fetched, err := client.FetchLink(context.Background(), accountData.VersionLink())
+	check.Nil(err)
+	check.Equal(accountData.ID, fetched.ID)
+	_, err = client.FetchLink(context.Background(), server.URL+accountData.SelfLink())
+	check.Nil(err)
+	check.Equal([]string{
+		"/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		"/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3?version=2",
+		"/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+	}, paths)
+
+	_, err = client.FetchLink(context.Background(), "https://example.com/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.NotNil(err)
+	_, err = client.FetchLink(context.Background(), "")
+	check.NotNil(err)
+	check.Len(paths, 3)
+
+	check.Equal("", (&AccountData{}).SelfLink())
+	check.Equal("", (*AccountData)(nil).VersionLink())
+}
+
+// TestMasterAccountRelationship - tests virtual accounts are created with and decoded with their master account
+func TestMasterAccountRelationship(t *testing.T) {
+	check := assert.New(t)
+	params := NewAccountBuilder().
+		ID("7eb322ba-57f6-465c-b600-79f26ac7fdc3").
+		OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").
+		Country("GB").BankID("400300").BankIDCode("GBDSC").Bic("NWBKGB22").
+		MasterAccount("a52d13a4-f435-4c00-cfad-f5e7ac5972df").
+		Build()
+	check.Nil(params.Validate())
+
+	request, err := MarshalCreateRequest(params)
+	check.Nil(err)
+	check.JSONEq(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "organisation_id": "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+		"type": "accounts", "attributes": {"country": "GB", "bank_id": "400300", "bank_id_code": "GBDSC", "bic": "NWBKGB22"},
+		"relationships": {"master_account": {"data": [{"type": "accounts", "id": "a52d13a4-f435-4c00-cfad-f5e7ac5972df"}]}}}}`, string(request))
+
+	clone := params.Clone()
+	clone.Relationships.MasterAccount.Data[0].ID = "5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a"
+	check.Equal("a52d13a4-f435-4c00-cfad-f5e7ac5972df", params.Relationships.MasterAccount.Data[0].ID)
+
+	account := &AccountData{}
+	check.Nil(json.Unmarshal(request[len(`{"data":`):len(request)-1], account))
+	check.Equal(&ResourceIdentifier{Type: "accounts", ID: "a52d13a4-f435-4c00-cfad-f5e7ac5972df"}, account.MasterAccount())
+	check.Equal(params.Relationships, account.createRelationships())
+	check.Nil((&AccountData{}).MasterAccount())
+	check.Nil((&AccountData{}).createRelationships())
+}
+
+// TestMasterAccountValidation - tests the master account of create params is validated
+func TestMasterAccountValidation(t *testing.T) {
+	check := assert.New(t)
+	builder := NewAccountBuilder().
+		ID("7eb322ba-57f6-465c-b600-79f26ac7fdc3").
+		OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").
+		Country("GB").BankID("400300").BankIDCode("GBDSC").Bic("NWBKGB22")
+
+	params := builder.MasterAccount("7eb322ba-57f6-465c-b600-79f26ac7fdc3").Build()
+	check.EqualError(params.Validate(), "invalid create params: relationships.master_account must not be the account itself")
+
+	params = builder.MasterAccount("master").Build()
+	check.EqualError(params.Validate(), `invalid create params: relationships.master_account.id "master" is not a valid uuid`)
+
+	params.Relationships.MasterAccount = &Relationship{Data: []ResourceIdentifier{{Type: "organisations", ID: "a52d13a4-f435-4c00-cfad-f5e7ac5972df"}}}
+	check.EqualError(params.Validate(), `invalid create params: relationships.master_account.type "organisations" is not "accounts"`)
+
+	params.Relationships.MasterAccount = &Relationship{}
+	check.EqualError(params.Validate(), "invalid create params: relationships.master_account requires exactly one account")
+}
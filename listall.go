@@ -0,0 +1,49 @@
+package accountlib
+
+import (
+	"context"
+
+	"accountlib/errors"
+)
+
+// list all constants
+const (
+	defaultMaxListAllRecords = 10000
+	listAllPageSize          = 100
+)
+
+// ListAll - lists the accounts of every page starting at the page of the list params, call options apply to every page
+// It fails with a *accounterrors.MaxRecordsExceededError once the accounts exceed ClientOptions.MaxListAllRecords,
+// after the first page already when the total count reported by the api tells so. A zero page size lists pages of 100 accounts
+func (client *Client) ListAll(ctx context.Context, listParams ListParams, options ...CallOption) ([]AccountData, error) {
+	maxRecords := client.maxListAllRecords
+	if maxRecords <= 0 {
+		maxRecords = defaultMaxListAllRecords
+	}
+	pager := client.newPager(ctx, listParams, options)
+	defer pager.close()
+
+	var accounts []AccountData
+	for {
+		page, err := pager.next()
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			return accounts, nil
+		}
+		accountList := page.accountList
+
+		// the total count tells the accounts left to list up front
+		if accountList.TotalCount != nil {
+			remaining := *accountList.TotalCount - int64(page.pageNumber*pager.listParams.PageSize)
+			if int64(len(accounts))+remaining > int64(maxRecords) {
+				return nil, &accounterrors.MaxRecordsExceededError{MaxRecords: maxRecords}
+			}
+		}
+		if len(accounts)+len(accountList.Data) > maxRecords {
+			return nil, &accounterrors.MaxRecordsExceededError{MaxRecords: maxRecords}
+		}
+		accounts = append(accounts, accountList.Data...)
+	}
+}
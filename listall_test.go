@@ -0,0 +1,78 @@
+package accountlib_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/errors"
+	"accountlib/fakeserver"
+)
+
+// TestListAll - tests the accounts of every page are listed
+func TestListAll(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	for i := 0; i < 250; i++ {
+		server.AddAccount(accountlib.AccountData{ID: fmt.Sprintf("%08d-0000-4000-8000-000000000000", i), OrganisationID: "org"})
+	}
+	server.AddAccount(accountlib.AccountData{ID: "99999999-0000-4000-8000-000000000000", OrganisationID: "other"})
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	accounts, err := client.ListAll(context.Background(), accountlib.ListParams{Filter: map[string]string{"organisation_id": "org"}})
+	check.Nil(err)
+	check.Len(accounts, 250)
+	check.Equal("00000249-0000-4000-8000-000000000000", accounts[249].ID)
+
+	accounts, err = client.ListAll(context.Background(), accountlib.ListParams{PageNumber: 2, PageSize: 50})
+	check.Nil(err)
+	check.Len(accounts, 151)
+}
+
+// TestListAllMaxRecords - tests listing fails once the accounts exceed the maximum record count
+func TestListAllMaxRecords(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	for i := 0; i < 30; i++ {
+		server.AddAccount(accountlib.AccountData{ID: fmt.Sprintf("%08d-0000-4000-8000-000000000000", i), OrganisationID: "org"})
+	}
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL, MaxListAllRecords: 25})
+
+	_, err := client.ListAll(context.Background(), accountlib.ListParams{PageSize: 10})
+	maxRecordsError := &accounterrors.MaxRecordsExceededError{}
+	check.True(errors.As(err, &maxRecordsError))
+	check.Equal(25, maxRecordsError.MaxRecords)
+
+	accounts, err := client.ListAll(context.Background(), accountlib.ListParams{PageNumber: 1, PageSize: 10})
+	check.Nil(err)
+	check.Len(accounts, 20)
+}
+
+// TestListAllWithoutLinks - tests pages are listed until a page is not full when the api returns neither links nor counts
+func TestListAllWithoutLinks(t *testing.T) {
+	check := assert.New(t)
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page[number]"))
+		if r.URL.Query().Get("page[number]") == "0" {
+			_, _ = w.Write([]byte(`{"data": [{"id": "1"}, {"id": "2"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"id": "3"}]}`))
+	}))
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL, MaxListAllRecords: 3})
+
+	accounts, err := client.ListAll(context.Background(), accountlib.ListParams{PageSize: 2})
+	check.Nil(err)
+	check.Len(accounts, 3)
+	check.Equal([]string{"0", "1"}, pages)
+}
@@ -0,0 +1,206 @@
+package accountlib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"accountlib/errors"
+)
+
+// QueuedMutation - a create, update or delete which failed with a retryable error and waits for replay
+// Creates and updates hold their params, deletes the account id and version. Call options are not queued
+type QueuedMutation struct {
+	ID        string               `json:"id"`
+	Operation string               `json:"operation"`
+	QueuedOn  time.Time            `json:"queued_on"`
+	Create    *AccountCreateParams `json:"create,omitempty"`
+	Update    *AccountUpdateParams `json:"update,omitempty"`
+	AccountID string               `json:"account_id,omitempty"`
+	Version   *int64               `json:"version,omitempty"`
+	Error     string               `json:"error,omitempty"`
+}
+
+// QueueStore - durable store of queued mutations, set it as ClientOptions.OfflineQueue
+// Mutations returns the mutations in the order they were queued, Remove ignores unknown ids
+type QueueStore interface {
+	Enqueue(mutation QueuedMutation) error
+	Mutations() ([]QueuedMutation, error)
+	Remove(id string) error
+}
+
+// FileQueueStore - queue store keeping every mutation in a json file of a directory
+// Files are written to a temporary file first and renamed, so a crash never leaves a partially written mutation
+type FileQueueStore struct {
+	mutex sync.Mutex
+	dir   string
+}
+
+// NewFileQueueStore - returns a queue store in the directory, creating the directory when it does not exist
+func NewFileQueueStore(dir string) (*FileQueueStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create queue directory. error: %w", err)
+	}
+	return &FileQueueStore{dir: dir}, nil
+}
+
+// Enqueue - writes the mutation to a file named by the time it was queued and its id
+func (store *FileQueueStore) Enqueue(mutation QueuedMutation) error {
+	data, err := json.Marshal(mutation)
+	if err != nil {
+		return err
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	file, err := ioutil.TempFile(store.dir, ".queued-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%019d-%s.json", mutation.QueuedOn.UnixNano(), mutation.ID)
+	return os.Rename(file.Name(), filepath.Join(store.dir, name))
+}
+
+// Mutations - reads the queued mutations, ordered by the time they were queued
+func (store *FileQueueStore) Mutations() ([]QueuedMutation, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	files, err := ioutil.ReadDir(store.dir)
+	if err != nil {
+		return nil, err
+	}
+	var mutations []QueuedMutation
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(store.dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		mutation := QueuedMutation{}
+		if err = json.Unmarshal(data, &mutation); err != nil {
+			return nil, fmt.Errorf("invalid queued mutation %s. error: %w", file.Name(), err)
+		}
+		mutations = append(mutations, mutation)
+	}
+	return mutations, nil
+}
+
+// Remove - deletes the file of the mutation
+func (store *FileQueueStore) Remove(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	names, err := filepath.Glob(filepath.Join(store.dir, "*-"+id+".json"))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err = os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueue - queues a mutation failing after every retry, returning a *accounterrors.QueuedError
+// Other errors are returned unchanged, as are mutations of clients without offline queue
+func (client *Client) enqueue(mutation QueuedMutation, err error) error {
+	var retriesExhaustedError *accounterrors.RetriesExhaustedError
+	if client.offlineQueue == nil || !errors.As(err, &retriesExhaustedError) {
+		return err
+	}
+	mutation.ID = client.newID()
+	mutation.QueuedOn = time.Now().UTC()
+	mutation.Error = err.Error()
+	if queueErr := client.offlineQueue.Enqueue(mutation); queueErr != nil {
+		return fmt.Errorf("unable to queue mutation: %s. error: %w", queueErr.Error(), err)
+	}
+	return &accounterrors.QueuedError{QueueID: mutation.ID, Err: err}
+}
+
+// DrainResult - outcome of draining the offline queue
+// Failed holds the mutations which failed permanently, like updates of changed accounts, they are removed from the
+// queue. Remaining counts the mutations still queued
+type DrainResult struct {
+	Replayed  int
+	Failed    []FailedMutation
+	Remaining int
+}
+
+// FailedMutation - a queued mutation which failed permanently when it was replayed
+type FailedMutation struct {
+	Mutation QueuedMutation
+	Err      error
+}
+
+// Drain - replays the mutations of the offline queue in the order they were queued
+// Replayed mutations and mutations failing permanently are removed from the queue. Draining stops at the first
+// mutation failing with a retryable error again, it stays queued along with the mutations after it
+func (client *Client) Drain(ctx context.Context) (*DrainResult, error) {
+	if client.offlineQueue == nil {
+		return nil, errors.New("client has no offline queue")
+	}
+	mutations, err := client.offlineQueue.Mutations()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read offline queue. error: %w", err)
+	}
+
+	// replayed mutations are sent by a copy of the client without queue, so they are not queued again
+	replayer := *client
+	replayer.offlineQueue = nil
+	result := &DrainResult{}
+	for i, mutation := range mutations {
+		err = replayer.replay(ctx, mutation)
+		var retriesExhaustedError *accounterrors.RetriesExhaustedError
+		if errors.As(err, &retriesExhaustedError) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			result.Remaining = len(mutations) - i
+			return result, fmt.Errorf("unable to replay queued mutation %s. error: %w", mutation.ID, err)
+		}
+		if removeErr := client.offlineQueue.Remove(mutation.ID); removeErr != nil {
+			result.Remaining = len(mutations) - i
+			return result, fmt.Errorf("unable to remove queued mutation %s. error: %w", mutation.ID, removeErr)
+		}
+		if err != nil {
+			result.Failed = append(result.Failed, FailedMutation{Mutation: mutation, Err: err})
+			continue
+		}
+		result.Replayed++
+	}
+	return result, nil
+}
+
+// replay - sends a queued mutation
+func (client *Client) replay(ctx context.Context, mutation QueuedMutation) error {
+	var err error
+	switch {
+	case mutation.Operation == OperationCreate && mutation.Create != nil:
+		_, err = client.Create(ctx, *mutation.Create)
+	case mutation.Operation == OperationUpdate && mutation.Update != nil:
+		_, err = client.Update(ctx, *mutation.Update)
+	case mutation.Operation == OperationDelete:
+		err = client.Delete(ctx, mutation.AccountID, mutation.Version)
+	default:
+		err = fmt.Errorf("invalid queued mutation: unknown operation %q", mutation.Operation)
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package accountlib_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/errors"
+	"accountlib/fakeserver"
+)
+
+// outageServer - returns a server answering 503 while down is set and proxying to the fake server otherwise
+func outageServer(server *fakeserver.Server, down *int32) *httptest.Server {
+	target, _ := url.Parse(server.URL)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+}
+
+// TestOfflineQueue - tests mutations failing during an outage are queued and replayed once the api is back
+func TestOfflineQueue(t *testing.T) {
+	check := assert.New(t)
+	dir, err := ioutil.TempDir("", "queue")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	store, err := accountlib.NewFileQueueStore(dir)
+	check.Nil(err)
+
+	server := fakeserver.New()
+	defer server.Close()
+	down := int32(1)
+	outage := outageServer(server, &down)
+	defer outage.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: outage.URL, DisableRetries: true, OfflineQueue: store})
+	ctx := context.Background()
+
+	createParams := accountlib.AccountCreateParams{ID: "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", Type: "accounts"}
+	_, err = client.Create(ctx, createParams)
+	check.True(errors.Is(err, accounterrors.ErrQueued))
+	queuedError := &accounterrors.QueuedError{}
+	check.True(errors.As(err, &queuedError))
+	version := int64(5)
+	_, err = client.Update(ctx, accountlib.AccountUpdateParams{ID: createParams.ID, OrganisationID: createParams.OrganisationID, Type: "accounts", Version: &version})
+	check.True(errors.Is(err, accounterrors.ErrQueued))
+	_, err = client.Create(ctx, accountlib.AccountCreateParams{ID: "invalid"})
+	check.False(errors.Is(err, accounterrors.ErrQueued))
+
+	mutations, err := store.Mutations()
+	check.Nil(err)
+	check.Len(mutations, 2)
+	check.Equal(queuedError.QueueID, mutations[0].ID)
+	check.Equal(accountlib.OperationCreate, mutations[0].Operation)
+	check.Equal(createParams, *mutations[0].Create)
+	check.Equal(accountlib.OperationUpdate, mutations[1].Operation)
+
+	result, err := client.Drain(ctx)
+	check.NotNil(err)
+	check.Equal(2, result.Remaining)
+	check.Len(server.Accounts(), 0)
+
+	atomic.StoreInt32(&down, 0)
+	result, err = client.Drain(ctx)
+	check.Nil(err)
+	check.Equal(1, result.Replayed)
+	check.Len(result.Failed, 1)
+	check.Equal(accountlib.OperationUpdate, result.Failed[0].Mutation.Operation)
+	check.Equal(0, result.Remaining)
+	check.Len(server.Accounts(), 1)
+
+	mutations, err = store.Mutations()
+	check.Nil(err)
+	check.Len(mutations, 0)
+}
+
+// TestOfflineQueueDelete - tests deletes are queued with their version
+func TestOfflineQueueDelete(t *testing.T) {
+	check := assert.New(t)
+	dir, err := ioutil.TempDir("", "queue")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	store, err := accountlib.NewFileQueueStore(dir)
+	check.Nil(err)
+
+	server := fakeserver.New()
+	defer server.Close()
+	server.AddAccount(accountlib.AccountData{ID: "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"})
+	down := int32(1)
+	outage := outageServer(server, &down)
+	defer outage.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: outage.URL, DisableRetries: true, OfflineQueue: store})
+
+	err = client.Delete(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", new(int64))
+	check.True(errors.Is(err, accounterrors.ErrQueued))
+
+	atomic.StoreInt32(&down, 0)
+	result, err := client.Drain(context.Background())
+	check.Nil(err)
+	check.Equal(1, result.Replayed)
+	check.Len(server.Accounts(), 0)
+}
+
+// TestDrainWithoutQueue - tests clients without offline queue can not be drained
+func TestDrainWithoutQueue(t *testing.T) {
+	check := assert.New(t)
+	client := accountlib.NewClient(nil)
+
+	_, err := client.Drain(context.Background())
+	check.EqualError(err, "client has no offline queue")
+}
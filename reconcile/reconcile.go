@@ -0,0 +1,279 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"accountlib"
+)
+
+// default page size used for reading the remote state
+const defaultPageSize = 100
+
+// ActionKind - kind of change needed to converge an account
+type ActionKind string
+
+// action kinds, actions are planned and applied in this order
+const (
+	ActionCreate ActionKind = "create"
+	ActionUpdate ActionKind = "update"
+	ActionDelete ActionKind = "delete"
+)
+
+// actionOrder - position of the action kinds in a plan
+var actionOrder = map[ActionKind]int{ActionCreate: 0, ActionUpdate: 1, ActionDelete: 2}
+
+// Client - account operations needed for reconciling, implemented by accountlib.Client
+type Client interface {
+	accountlib.AccountCreator
+	accountlib.AccountUpdater
+	accountlib.AccountDeleter
+	accountlib.AccountLister
+}
+
+// AccountSpec - desired state of an account
+// Relationships, like the master account of a virtual account, are set when the account is created, updates cannot
+// change them
+type AccountSpec struct {
+	Attributes     *accountlib.AccountCreateAttributes `json:"attributes,omitempty"`
+	ID             string                              `json:"id,omitempty"`
+	OrganisationID string                              `json:"organisation_id,omitempty"`
+	Type           string                              `json:"type,omitempty"`
+	Relationships  *accountlib.AccountRelationships    `json:"relationships,omitempty"`
+}
+
+// Options - controls a sync
+// DryRun only plans the actions, DisableDeletes keeps remote accounts missing from the desired accounts
+// ContinueOnError applies the remaining actions after a failed one, PageSize is used for listing the remote accounts
+type Options struct {
+	DryRun          bool
+	DisableDeletes  bool
+	ContinueOnError bool
+	PageSize        int
+}
+
+// Action - change needed to converge one account
+// Changes lists the differing fields of updates, Remote is the remote account of updates and deletes
+type Action struct {
+	Kind      ActionKind
+	AccountID string
+	Spec      *AccountSpec
+	Remote    *accountlib.AccountData
+	Changes   []accountlib.FieldChange
+}
+
+// ActionError - error of an action which failed to apply
+type ActionError struct {
+	Action Action
+	Err    error
+}
+
+// Error - returns the error message
+func (err *ActionError) Error() string {
+	return fmt.Sprintf("%s %s failed. error: %s", err.Action.Kind, err.Action.AccountID, err.Err.Error())
+}
+
+// Unwrap - returns the error of the failed action
+func (err *ActionError) Unwrap() error {
+	return err.Err
+}
+
+// Result - holds the plan of a sync and the outcome of applying it
+// Applied lists the applied actions in plan order, Results holds the account returned for each of them, nil for deletes
+type Result struct {
+	Plan    []Action
+	Applied []Action
+	Failed  []*ActionError
+	Results []*accountlib.AccountData
+}
+
+// Sync - converges the accounts of the organisations of the desired accounts to the desired accounts
+// Accounts missing remotely are created, differing ones updated and remote accounts missing from the desired ones deleted
+// The returned error is the first failed action unless ContinueOnError is set, failures are also listed in the result
+func Sync(ctx context.Context, client Client, desired []AccountSpec, options Options) (*Result, error) {
+	actions, err := Plan(ctx, client, desired, options)
+	if err != nil {
+		return nil, err
+	}
+	if options.DryRun {
+		return &Result{Plan: actions}, nil
+	}
+	return Apply(ctx, client, actions, options)
+}
+
+// Plan - returns the actions converging the remote accounts to the desired accounts without applying them
+func Plan(ctx context.Context, client Client, desired []AccountSpec, options Options) ([]Action, error) {
+	if err := checkSpecs(desired); err != nil {
+		return nil, err
+	}
+	remote, err := remoteAccounts(ctx, client, desired, options.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return plan(desired, remote, options.DisableDeletes), nil
+}
+
+// Apply - applies planned actions in order, the version of the remote accounts must not have changed since planning
+func Apply(ctx context.Context, client Client, actions []Action, options Options) (*Result, error) {
+	result := &Result{Plan: actions}
+	for _, action := range actions {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		accountData, err := apply(ctx, client, action)
+		if err != nil {
+			actionError := &ActionError{Action: action, Err: err}
+			result.Failed = append(result.Failed, actionError)
+			if !options.ContinueOnError {
+				return result, actionError
+			}
+			continue
+		}
+		result.Applied = append(result.Applied, action)
+		result.Results = append(result.Results, accountData)
+	}
+	return result, nil
+}
+
+// checkSpecs - checks every desired account has an id and an organisation id and is defined once
+func checkSpecs(desired []AccountSpec) error {
+	seen := make(map[string]bool, len(desired))
+	for i, spec := range desired {
+		if spec.ID == "" || spec.OrganisationID == "" {
+			return fmt.Errorf("account %d needs an id and an organisation id", i)
+		}
+		if seen[spec.ID] {
+			return fmt.Errorf("account %s is defined twice", spec.ID)
+		}
+		seen[spec.ID] = true
+	}
+	return nil
+}
+
+// remoteAccounts - lists the remote accounts of every organisation of the desired accounts
+func remoteAccounts(ctx context.Context, client Client, desired []AccountSpec, pageSize int) ([]accountlib.AccountData, error) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	organisations := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, spec := range desired {
+		if !seen[spec.OrganisationID] {
+			seen[spec.OrganisationID] = true
+			organisations = append(organisations, spec.OrganisationID)
+		}
+	}
+
+	var remote []accountlib.AccountData
+	for _, organisationID := range organisations {
+		for pageNumber := 0; ; pageNumber++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			accountList, err := client.List(ctx, accountlib.ListParams{
+				PageNumber: pageNumber,
+				PageSize:   pageSize,
+				Filter:     map[string]string{"organisation_id": organisationID},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unable to list accounts of organisation %s. error: %s", organisationID, err.Error())
+			}
+			remote = append(remote, accountList.Data...)
+			if len(accountList.Data) < pageSize {
+				break
+			}
+		}
+	}
+	return remote, nil
+}
+
+// plan - returns the actions converging the remote accounts to the desired ones, sorted by kind and account id
+func plan(desired []AccountSpec, remote []accountlib.AccountData, disableDeletes bool) []Action {
+	remoteByID := make(map[string]*accountlib.AccountData, len(remote))
+	for i := range remote {
+		remoteByID[remote[i].ID] = &remote[i]
+	}
+
+	actions := make([]Action, 0)
+	desiredIDs := make(map[string]bool, len(desired))
+	for i := range desired {
+		spec := &desired[i]
+		desiredIDs[spec.ID] = true
+		current, ok := remoteByID[spec.ID]
+		if !ok {
+			actions = append(actions, Action{Kind: ActionCreate, AccountID: spec.ID, Spec: spec})
+			continue
+		}
+		if changes := specChanges(spec, current); len(changes) > 0 {
+			actions = append(actions, Action{Kind: ActionUpdate, AccountID: spec.ID, Spec: spec, Remote: current, Changes: changes})
+		}
+	}
+	if !disableDeletes {
+		for _, current := range remoteByID {
+			if !desiredIDs[current.ID] {
+				actions = append(actions, Action{Kind: ActionDelete, AccountID: current.ID, Remote: current})
+			}
+		}
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].Kind != actions[j].Kind {
+			return actionOrder[actions[i].Kind] < actionOrder[actions[j].Kind]
+		}
+		return actions[i].AccountID < actions[j].AccountID
+	})
+	return actions
+}
+
+// specChanges - returns the fields of a remote account differing from its spec
+// Fields a spec cannot hold, like the version, status and the timestamps set by the api, and the relationships, which
+// updates cannot change, are taken from the remote account
+func specChanges(spec *AccountSpec, current *accountlib.AccountData) []accountlib.FieldChange {
+	desired := &accountlib.AccountData{}
+	encoded, err := json.Marshal(spec)
+	if err == nil {
+		err = json.Unmarshal(encoded, desired)
+	}
+	if err != nil {
+		return []accountlib.FieldChange{{Path: "attributes", Old: current.Attributes, New: spec.Attributes}}
+	}
+	desired.Version = current.Version
+	desired.CreatedOn, desired.ModifiedOn = current.CreatedOn, current.ModifiedOn
+	desired.Relationships = current.Relationships
+	if desired.Type == "" {
+		desired.Type = current.Type
+	}
+	if current.Attributes != nil {
+		if desired.Attributes == nil {
+			desired.Attributes = &accountlib.AccountAttributes{}
+		}
+		desired.Attributes.Status = current.Attributes.Status
+		desired.Attributes.StatusReason = current.Attributes.StatusReason
+	}
+	return accountlib.Diff(current, desired)
+}
+
+// apply - applies a single action of the plan
+func apply(ctx context.Context, client Client, action Action) (*accountlib.AccountData, error) {
+	switch action.Kind {
+	case ActionCreate:
+		return client.Create(ctx, accountlib.AccountCreateParams{
+			Attributes:     action.Spec.Attributes,
+			ID:             action.Spec.ID,
+			OrganisationID: action.Spec.OrganisationID,
+			Type:           action.Spec.Type,
+			Relationships:  action.Spec.Relationships,
+		})
+	case ActionUpdate:
+		return client.Update(ctx, accountlib.AccountUpdateParams{
+			Attributes:     action.Spec.Attributes,
+			ID:             action.Spec.ID,
+			OrganisationID: action.Spec.OrganisationID,
+			Type:           action.Spec.Type,
+			Version:        action.Remote.Version,
+		})
+	}
+	return nil, client.Delete(ctx, action.AccountID, action.Remote.Version)
+}
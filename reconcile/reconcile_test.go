@@ -0,0 +1,201 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/accountlibtest"
+	"accountlib/fakeserver"
+)
+
+// account ids used by the reconcile tests
+const (
+	unchangedAccountID = "7eb322ba-57f6-465c-b600-79f26ac7fdc3"
+	changedAccountID   = "5b1a8c9e-5e4f-4a39-9c5e-0f0e1c2d3b4a"
+	removedAccountID   = "0d209d7f-d07a-4542-947f-5885fddddae2"
+	addedAccountID     = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+// specOf - returns the spec of a fixture
+func specOf(fixture *accountlibtest.AccountFixture) AccountSpec {
+	params := fixture.CreateParams()
+	return AccountSpec{Attributes: params.Attributes, ID: params.ID, OrganisationID: params.OrganisationID, Type: params.Type}
+}
+
+// syncServer - returns a fake server holding the unchanged, changed and removed accounts, and the desired accounts
+func syncServer() (*fakeserver.Server, []AccountSpec) {
+	server := fakeserver.New()
+	for _, id := range []string{unchangedAccountID, changedAccountID, removedAccountID} {
+		server.AddAccount(accountlibtest.ValidGBAccount().WithID(id).AccountData())
+	}
+	desired := []AccountSpec{
+		specOf(accountlibtest.ValidGBAccount().WithID(unchangedAccountID)),
+		specOf(accountlibtest.ValidGBAccount().WithID(changedAccountID).WithBic("BARCGB22")),
+		specOf(accountlibtest.ValidAccount("FR").WithID(addedAccountID)),
+	}
+	return server, desired
+}
+
+// actionSummary - returns the kind and account id of every action
+func actionSummary(actions []Action) []string {
+	summary := make([]string, len(actions))
+	for i, action := range actions {
+		summary[i] = string(action.Kind) + " " + action.AccountID
+	}
+	return summary
+}
+
+// TestSyncDryRun - tests a dry run returns the plan without changing the remote accounts
+func TestSyncDryRun(t *testing.T) {
+	check := assert.New(t)
+	server, desired := syncServer()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	result, err := Sync(context.Background(), client, desired, Options{DryRun: true, PageSize: 2})
+	check.Nil(err)
+	check.Equal([]string{"create " + addedAccountID, "update " + changedAccountID, "delete " + removedAccountID}, actionSummary(result.Plan))
+	check.Equal([]accountlib.FieldChange{{Path: "attributes.bic", Old: "NWBKGB22", New: "BARCGB22"}}, result.Plan[1].Changes)
+	check.Empty(result.Applied)
+	check.Len(server.Accounts(), 3)
+}
+
+// TestSyncConverges - tests a sync creates, updates and deletes accounts and a second sync has nothing to do
+func TestSyncConverges(t *testing.T) {
+	check := assert.New(t)
+	server, desired := syncServer()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	result, err := Sync(context.Background(), client, desired, Options{})
+	check.Nil(err)
+	check.Equal(actionSummary(result.Plan), actionSummary(result.Applied))
+	check.Equal(addedAccountID, result.Results[0].ID)
+	check.Equal(int64(1), *result.Results[1].Version)
+	check.Nil(result.Results[2])
+
+	accounts := server.Accounts()
+	check.Equal([]string{changedAccountID, unchangedAccountID, addedAccountID}, []string{accounts[0].ID, accounts[1].ID, accounts[2].ID})
+	check.Equal("BARCGB22", accounts[0].Attributes.Bic)
+
+	result, err = Sync(context.Background(), client, desired, Options{})
+	check.Nil(err)
+	check.Empty(result.Plan)
+}
+
+// TestSyncDisableDeletes - tests remote accounts missing from the desired accounts are kept
+func TestSyncDisableDeletes(t *testing.T) {
+	check := assert.New(t)
+	server, desired := syncServer()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	result, err := Sync(context.Background(), client, desired, Options{DisableDeletes: true})
+	check.Nil(err)
+	check.Equal([]string{"create " + addedAccountID, "update " + changedAccountID}, actionSummary(result.Applied))
+	check.Len(server.Accounts(), 4)
+}
+
+// failingClient - client failing creates, used for checking the error handling of apply
+type failingClient struct {
+	Client
+	err error
+}
+
+// Create - fails with the error of the client
+func (client *failingClient) Create(context.Context, accountlib.AccountCreateParams, ...accountlib.CallOption) (*accountlib.AccountData, error) {
+	return nil, client.err
+}
+
+// TestSyncActionErrors - tests a failed action stops the sync unless ContinueOnError is set
+func TestSyncActionErrors(t *testing.T) {
+	check := assert.New(t)
+	server, desired := syncServer()
+	defer server.Close()
+	injected := errors.New("injected")
+	client := &failingClient{Client: accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL}), err: injected}
+
+	result, err := Sync(context.Background(), client, desired, Options{})
+	check.True(errors.Is(err, injected))
+	check.Equal("create "+addedAccountID+" failed. error: injected", err.Error())
+	check.Empty(result.Applied)
+	check.Len(result.Failed, 1)
+
+	result, err = Sync(context.Background(), client, desired, Options{ContinueOnError: true})
+	check.Nil(err)
+	check.Equal([]string{"update " + changedAccountID, "delete " + removedAccountID}, actionSummary(result.Applied))
+	check.Len(result.Failed, 1)
+}
+
+// TestSyncInvalidSpecs - tests specs without ids and duplicated specs are rejected before listing
+func TestSyncInvalidSpecs(t *testing.T) {
+	check := assert.New(t)
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: "http://127.0.0.1:1"})
+
+	_, err := Sync(context.Background(), client, []AccountSpec{{ID: addedAccountID}}, Options{})
+	check.Contains(err.Error(), "needs an id and an organisation id")
+
+	spec := specOf(accountlibtest.ValidGBAccount())
+	_, err = Sync(context.Background(), client, []AccountSpec{spec, spec}, Options{})
+	check.Contains(err.Error(), "defined twice")
+}
+
+// TestSyncCanceled - tests a canceled context stops the sync
+func TestSyncCanceled(t *testing.T) {
+	check := assert.New(t)
+	server, desired := syncServer()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Sync(ctx, client, desired, Options{})
+	check.Equal(context.Canceled, err)
+	check.Len(server.Accounts(), 3)
+}
+
+// TestPlanTimestamps - tests the timestamps set by the api do not plan updates of unchanged accounts
+func TestPlanTimestamps(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	createdOn := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	modifiedOn := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+	account := accountlibtest.ValidGBAccount().WithID(unchangedAccountID).AccountData()
+	account.CreatedOn, account.ModifiedOn = &createdOn, &modifiedOn
+	server.AddAccount(account)
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	actions, err := Plan(context.Background(), client, []AccountSpec{specOf(accountlibtest.ValidGBAccount().WithID(unchangedAccountID))}, Options{})
+	check.Nil(err)
+	check.Empty(actions)
+}
+
+// TestSyncRelationships - tests relationships are sent with creates and do not plan updates of unchanged accounts
+func TestSyncRelationships(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	server.AddAccount(accountlibtest.ValidGBAccount().WithID(unchangedAccountID).AccountData())
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	relationships := &accountlib.AccountRelationships{MasterAccount: accountlib.NewMasterAccountRelationship(unchangedAccountID)}
+	master := specOf(accountlibtest.ValidGBAccount().WithID(unchangedAccountID))
+	virtual := specOf(accountlibtest.ValidGBAccount().WithID(addedAccountID))
+	virtual.Relationships = relationships
+
+	result, err := Sync(context.Background(), client, []AccountSpec{master, virtual}, Options{})
+	check.Nil(err)
+	check.Equal([]string{"create " + addedAccountID}, actionSummary(result.Applied))
+	check.Equal(unchangedAccountID, result.Results[0].Relationships["master_account"].Data[0].ID)
+
+	// specs leaving out the relationships of a remote account do not change it either
+	virtual.Relationships = nil
+	actions, err := Plan(context.Background(), client, []AccountSpec{master, virtual}, Options{})
+	check.Nil(err)
+	check.Empty(actions)
+}
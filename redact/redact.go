@@ -0,0 +1,24 @@
+package redact
+
+import "strings"
+
+// redaction constants
+const (
+	maskCharacter  = "*"
+	visibleSuffix  = 4
+	minMaskedRunes = 8
+)
+
+// Mask - hides all but the last few characters of a sensitive value such as an account number or iban
+// Short values are masked completely, since their suffix would reveal most of the value
+func Mask(value string) string {
+	runes := []rune(value)
+	if len(runes) == 0 {
+		return ""
+	}
+	if len(runes) < minMaskedRunes {
+		return strings.Repeat(maskCharacter, len(runes))
+	}
+	hidden := len(runes) - visibleSuffix
+	return strings.Repeat(maskCharacter, hidden) + string(runes[hidden:])
+}
@@ -0,0 +1,21 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMask - tests all but the last four characters are masked
+func TestMask(t *testing.T) {
+	check := assert.New(t)
+	check.Equal(Mask("41426819"), "****6819")
+	check.Equal(Mask("GB11NWBK40030041426819"), "******************6819")
+}
+
+// TestMaskShortValue - tests short values are masked completely
+func TestMaskShortValue(t *testing.T) {
+	check := assert.New(t)
+	check.Equal(Mask("1234"), "****")
+	check.Equal(Mask(""), "")
+}
@@ -0,0 +1,58 @@
+package redact
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// redactedName - replaces names, which are left out completely
+const redactedName = "[redacted]"
+
+// nameFields - json fields holding names, their values are replaced completely
+var nameFields = map[string]bool{"name": true, "alternative_names": true}
+
+// sensitiveField - matches json fields holding personal data along with their string or string array value
+var sensitiveField = regexp.MustCompile(`"(account_number|iban|secondary_identification|name|alternative_names)"(\s*:\s*)("(?:[^"\\]|\\.)*"|\[(?:\s*"(?:[^"\\]|\\.)*"\s*,?)*\s*\])`)
+
+// jsonString - matches a json string literal
+var jsonString = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// sensitiveText - matches ibans and account numbers of seven or more digits in free text
+// Uuids are matched first, so digit groups of ids are not taken for account numbers
+var sensitiveText = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|\b[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}\b|\b[0-9]{7,}\b`)
+
+// uuidText - matches a complete uuid
+var uuidText = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Scrub - removes personal data from a response body before it is included in errors and logs, keeping its format
+// Names of json name fields are replaced, account numbers, ibans and secondary identifications of json fields are
+// masked, and ibans and account numbers in other text are masked as well
+func Scrub(body []byte) []byte {
+	scrubbed := sensitiveField.ReplaceAllFunc(body, func(field []byte) []byte {
+		match := sensitiveField.FindSubmatch(field)
+		name, separator, value := string(match[1]), match[2], match[3]
+		value = jsonString.ReplaceAllFunc(value, func(literal []byte) []byte {
+			if nameFields[name] {
+				return quote(redactedName)
+			}
+			var text string
+			if err := json.Unmarshal(literal, &text); err != nil {
+				return quote(redactedName)
+			}
+			return quote(Mask(text))
+		})
+		return append(append([]byte(`"`+name+`"`), separator...), value...)
+	})
+	return sensitiveText.ReplaceAllFunc(scrubbed, func(text []byte) []byte {
+		if uuidText.Match(text) {
+			return text
+		}
+		return []byte(Mask(string(text)))
+	})
+}
+
+// quote - returns the json string literal of a value
+func quote(value string) []byte {
+	literal, _ := json.Marshal(value)
+	return literal
+}
@@ -0,0 +1,25 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestScrubJSONFields - tests names are replaced and identifiers masked in json fields, keeping the format
+func TestScrubJSONFields(t *testing.T) {
+	check := assert.New(t)
+	body := []byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "attributes": {"name": ["Samantha Holder", "S. \"Sam\" Holder"],
+		"alternative_names": [], "account_number" : "41426819", "iban": "GB11NWBK40030041426819", "bank_id": "400300"}}}`)
+	check.Equal(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "attributes": {"name": ["[redacted]", "[redacted]"],
+		"alternative_names": [], "account_number" : "****6819", "iban": "******************6819", "bank_id": "400300"}}}`, string(Scrub(body)))
+}
+
+// TestScrubText - tests ibans and account numbers are masked in free text while ids stay readable
+func TestScrubText(t *testing.T) {
+	check := assert.New(t)
+	check.Equal(`{"error_message": "account ****6819 with iban ******************6819 conflicts with 7eb322ba-57f6-465c-b600-79f26ac7fdc3"}`,
+		string(Scrub([]byte(`{"error_message": "account 41426819 with iban GB11NWBK40030041426819 conflicts with 7eb322ba-57f6-465c-b600-79f26ac7fdc3"}`))))
+	check.Equal("sort code 400300 at 2021-03-04T10:11:12Z", string(Scrub([]byte("sort code 400300 at 2021-03-04T10:11:12Z"))))
+	check.Equal("", string(Scrub(nil)))
+}
@@ -0,0 +1,53 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// Tokenizer - replaces sensitive values with deterministic tokens, equal values and keys give equal tokens
+type Tokenizer struct {
+	key []byte
+}
+
+// NewTokenizer - returns a tokenizer deriving tokens from the key, tokens can not be reversed without the key
+func NewTokenizer(key []byte) *Tokenizer {
+	return &Tokenizer{key: append([]byte(nil), key...)}
+}
+
+// Token - returns the token of a value, keeping its format
+// Digits are replaced with digits, upper and lower case letters with letters of the same case and other characters,
+// like spaces and dashes, are kept, so tokens of account numbers and names look like account numbers and names
+func (tokenizer *Tokenizer) Token(value string) string {
+	if value == "" {
+		return ""
+	}
+	runes := []rune(value)
+	stream := tokenizer.stream(value, len(runes))
+	for i, character := range runes {
+		switch {
+		case character >= '0' && character <= '9':
+			runes[i] = '0' + rune(stream[i]%10)
+		case character >= 'A' && character <= 'Z':
+			runes[i] = 'A' + rune(stream[i]%26)
+		case character >= 'a' && character <= 'z':
+			runes[i] = 'a' + rune(stream[i]%26)
+		}
+	}
+	return string(runes)
+}
+
+// stream - returns length pseudo random bytes derived from the key and the value
+func (tokenizer *Tokenizer) stream(value string, length int) []byte {
+	stream := make([]byte, 0, length+sha256.Size)
+	counter := make([]byte, 8)
+	for block := uint64(0); len(stream) < length; block++ {
+		mac := hmac.New(sha256.New, tokenizer.key)
+		binary.BigEndian.PutUint64(counter, block)
+		mac.Write(counter)
+		mac.Write([]byte(value))
+		stream = mac.Sum(stream)
+	}
+	return stream
+}
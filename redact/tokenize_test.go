@@ -0,0 +1,31 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestToken - tests tokens are deterministic per key and keep the format of the value
+func TestToken(t *testing.T) {
+	check := assert.New(t)
+	tokenizer := NewTokenizer([]byte("key"))
+
+	token := tokenizer.Token("Jane Doe-Smith 42")
+	check.Equal(token, tokenizer.Token("Jane Doe-Smith 42"))
+	check.NotEqual(token, "Jane Doe-Smith 42")
+	check.Regexp(`^[A-Z][a-z]{3} [A-Z][a-z]{2}-[A-Z][a-z]{4} [0-9]{2}$`, token)
+	check.NotEqual(token, NewTokenizer([]byte("other key")).Token("Jane Doe-Smith 42"))
+	check.NotEqual(token, tokenizer.Token("Jane Doe-Smith 43"))
+}
+
+// TestTokenLongValue - tests values longer than a hash are tokenized completely
+func TestTokenLongValue(t *testing.T) {
+	check := assert.New(t)
+	value := "1234567890123456789012345678901234567890123456789012345678901234567890"
+
+	token := NewTokenizer([]byte("key")).Token(value)
+	check.Regexp(`^[0-9]{70}$`, token)
+	check.NotEqual(token[40:], value[40:])
+	check.Equal(NewTokenizer(nil).Token(""), "")
+}
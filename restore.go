@@ -0,0 +1,126 @@
+package accountlib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// CollisionPolicy - the way Restore handles accounts of a snapshot which exist already
+type CollisionPolicy string
+
+// collision policies
+const (
+	// CollisionFail - fails the restore before changing any account, the default
+	CollisionFail CollisionPolicy = "fail"
+	// CollisionSkip - keeps the existing account
+	CollisionSkip CollisionPolicy = "skip"
+	// CollisionOverwrite - deletes the existing account and recreates it from the snapshot
+	CollisionOverwrite CollisionPolicy = "overwrite"
+)
+
+// RestoreAction - the change Restore makes for an account of a snapshot
+type RestoreAction string
+
+// restore actions
+const (
+	RestoreCreate    RestoreAction = "create"
+	RestoreSkip      RestoreAction = "skip"
+	RestoreOverwrite RestoreAction = "overwrite"
+)
+
+// RestoreOptions - options of Restore, a zero value fails on collisions and restores the accounts
+type RestoreOptions struct {
+	Collision CollisionPolicy
+	DryRun    bool
+}
+
+// RestoreStep - the action for an account of the snapshot, Existing is the account found before the restore
+// Applied reports whether the action was carried out, it is false for skips and for dry runs
+type RestoreStep struct {
+	Action   RestoreAction
+	Account  AccountData
+	Existing *AccountData
+	Applied  bool
+}
+
+// RestorePlan - the header of the restored snapshot and a step for each of its accounts, in the order of the snapshot
+type RestorePlan struct {
+	Header SnapshotHeader
+	Steps  []RestoreStep
+}
+
+// Restore - recreates the accounts of a snapshot written by Snapshot
+// Every account is looked up first and the plan of the restore is made before any account is changed, so a
+// collision with CollisionFail or a dry run changes nothing. Recreated accounts start at a new version, the
+// versions of the snapshot can not be restored, neither can the status of the accounts. When a step fails the
+// plan is returned with the applied steps along with the error
+func (client *Client) Restore(ctx context.Context, r io.Reader, restoreOptions RestoreOptions) (*RestorePlan, error) {
+	collision := restoreOptions.Collision
+	if collision == "" {
+		collision = CollisionFail
+	}
+	if collision != CollisionFail && collision != CollisionSkip && collision != CollisionOverwrite {
+		return nil, fmt.Errorf("invalid collision policy: %q", collision)
+	}
+
+	header, accounts, err := readSnapshot(r)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &RestorePlan{Header: *header, Steps: make([]RestoreStep, len(accounts))}
+	var collisions []string
+	for i, account := range accounts {
+		existing, statusCode, err := client.fetch(ctx, account.ID, nil)
+		switch {
+		case statusCode == http.StatusNotFound:
+			plan.Steps[i] = RestoreStep{Action: RestoreCreate, Account: account}
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("unable to plan restore of account %s. error: %w", account.ID, err)
+		}
+
+		plan.Steps[i] = RestoreStep{Action: RestoreOverwrite, Account: account, Existing: existing}
+		if collision == CollisionSkip {
+			plan.Steps[i].Action = RestoreSkip
+		}
+		collisions = append(collisions, account.ID)
+	}
+	if collision == CollisionFail && len(collisions) > 0 {
+		return plan, fmt.Errorf("restore collides with %d existing accounts: %s", len(collisions), strings.Join(collisions, ", "))
+	}
+	if restoreOptions.DryRun {
+		return plan, nil
+	}
+
+	for i := range plan.Steps {
+		if err := client.applyRestoreStep(ctx, plan.Steps[i]); err != nil {
+			return plan, fmt.Errorf("unable to restore account %s. error: %w", plan.Steps[i].Account.ID, err)
+		}
+		plan.Steps[i].Applied = plan.Steps[i].Action != RestoreSkip
+	}
+	return plan, nil
+}
+
+// applyRestoreStep - carries out the action of a restore step
+func (client *Client) applyRestoreStep(ctx context.Context, step RestoreStep) error {
+	switch step.Action {
+	case RestoreSkip:
+		return nil
+	case RestoreOverwrite:
+		if err := client.Delete(ctx, step.Existing.ID, step.Existing.Version); err != nil {
+			return err
+		}
+	}
+	_, err := client.Create(ctx, AccountCreateParams{
+		Attributes:     step.Account.Attributes.CreateAttributes(),
+		ID:             step.Account.ID,
+		OrganisationID: step.Account.OrganisationID,
+		Type:           step.Account.Type,
+		Relationships:  step.Account.createRelationships(),
+	})
+	return err
+}
@@ -0,0 +1,124 @@
+package accountlib_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/fakeserver"
+)
+
+// restoreOrganisationID - organisation of the accounts of the restore tests
+const restoreOrganisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+
+// restoreSnapshot - returns a snapshot of three accounts, the accounts are named by their index
+func restoreSnapshot(t *testing.T) []byte {
+	server := fakeserver.New()
+	defer server.Close()
+	for i := 0; i < 3; i++ {
+		server.AddAccount(restoreAccount(i, fmt.Sprintf("account %d", i)))
+	}
+	buffer := &bytes.Buffer{}
+	if err := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL}).Snapshot(context.Background(), restoreOrganisationID, buffer); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// restoreAccount - returns the account with the index and name
+func restoreAccount(i int, name string) accountlib.AccountData {
+	return accountlib.AccountData{
+		ID:             fmt.Sprintf("%08d-0000-4000-8000-000000000000", i),
+		OrganisationID: restoreOrganisationID,
+		Type:           "accounts",
+		Attributes:     &accountlib.AccountAttributes{Name: []string{name}},
+	}
+}
+
+// TestRestore - tests the accounts of a snapshot are recreated
+func TestRestore(t *testing.T) {
+	check := assert.New(t)
+	snapshot := restoreSnapshot(t)
+	server := fakeserver.New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	plan, err := client.Restore(context.Background(), bytes.NewReader(snapshot), accountlib.RestoreOptions{})
+	check.Nil(err)
+	check.Equal(restoreOrganisationID, plan.Header.OrganisationID)
+	check.Len(plan.Steps, 3)
+	for _, step := range plan.Steps {
+		check.Equal(accountlib.RestoreCreate, step.Action)
+		check.True(step.Applied)
+	}
+	accounts := server.Accounts()
+	check.Len(accounts, 3)
+	check.Equal([]string{"account 2"}, accounts[2].Attributes.Name)
+}
+
+// TestRestoreCollisions - tests existing accounts fail, skip or overwrite the restore and dry runs change nothing
+func TestRestoreCollisions(t *testing.T) {
+	check := assert.New(t)
+	snapshot := restoreSnapshot(t)
+	server := fakeserver.New()
+	defer server.Close()
+	server.AddAccount(restoreAccount(1, "changed"))
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	plan, err := client.Restore(context.Background(), bytes.NewReader(snapshot), accountlib.RestoreOptions{})
+	check.EqualError(err, "restore collides with 1 existing accounts: 00000001-0000-4000-8000-000000000000")
+	check.Equal(accountlib.RestoreOverwrite, plan.Steps[1].Action)
+	check.Len(server.Accounts(), 1)
+
+	plan, err = client.Restore(context.Background(), bytes.NewReader(snapshot), accountlib.RestoreOptions{Collision: accountlib.CollisionSkip, DryRun: true})
+	check.Nil(err)
+	check.Equal(accountlib.RestoreCreate, plan.Steps[0].Action)
+	check.Equal(accountlib.RestoreSkip, plan.Steps[1].Action)
+	check.Equal([]string{"changed"}, plan.Steps[1].Existing.Attributes.Name)
+	check.False(plan.Steps[0].Applied)
+	check.Len(server.Accounts(), 1)
+
+	_, err = client.Restore(context.Background(), bytes.NewReader(snapshot), accountlib.RestoreOptions{Collision: accountlib.CollisionSkip})
+	check.Nil(err)
+	accounts := server.Accounts()
+	check.Len(accounts, 3)
+	check.Equal([]string{"changed"}, accounts[1].Attributes.Name)
+
+	plan, err = client.Restore(context.Background(), bytes.NewReader(snapshot), accountlib.RestoreOptions{Collision: accountlib.CollisionOverwrite})
+	check.Nil(err)
+	for _, step := range plan.Steps {
+		check.Equal(accountlib.RestoreOverwrite, step.Action)
+		check.True(step.Applied)
+	}
+	accounts = server.Accounts()
+	check.Len(accounts, 3)
+	check.Equal([]string{"account 1"}, accounts[1].Attributes.Name)
+}
+
+// TestRestoreInvalidSnapshot - tests incomplete and unknown snapshots are not restored
+func TestRestoreInvalidSnapshot(t *testing.T) {
+	check := assert.New(t)
+	snapshot := string(restoreSnapshot(t))
+	server := fakeserver.New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	lines := strings.SplitAfter(snapshot, "\n")
+	_, err := client.Restore(context.Background(), strings.NewReader(strings.Join(lines[:3], "")), accountlib.RestoreOptions{})
+	check.EqualError(err, "invalid snapshot: missing trailer, the snapshot is incomplete")
+
+	_, err = client.Restore(context.Background(), strings.NewReader(strings.Replace(snapshot, `"version":1`, `"version":2`, 1)), accountlib.RestoreOptions{})
+	check.EqualError(err, "invalid snapshot: unsupported version 2")
+
+	_, err = client.Restore(context.Background(), strings.NewReader(strings.Join(lines[1:], "")), accountlib.RestoreOptions{})
+	check.EqualError(err, "invalid snapshot: missing header")
+
+	_, err = client.Restore(context.Background(), strings.NewReader(snapshot), accountlib.RestoreOptions{Collision: "merge"})
+	check.EqualError(err, `invalid collision policy: "merge"`)
+	check.Len(server.Accounts(), 0)
+}
@@ -0,0 +1,100 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/errors"
+)
+
+// TestRetriesExhausted - tests the error of a request failing on every attempt holds the attempt history
+func TestRetriesExhausted(t *testing.T) {
+	check := assert.New(t)
+	statusCodes := []int{http.StatusGatewayTimeout, http.StatusServiceUnavailable}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCodes[0])
+		statusCodes = statusCodes[1:]
+		_, _ = w.Write([]byte(`{"error_message": "maintenance"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(&ClientOptions{BaseURL: server.URL, RetryCount: 2}).Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	exhausted := &accounterrors.RetriesExhaustedError{}
+	check.True(errors.As(err, &exhausted))
+	check.Equal([]accounterrors.Attempt{{StatusCode: http.StatusGatewayTimeout}, {StatusCode: http.StatusServiceUnavailable}}, exhausted.Attempts)
+	check.Equal(`request failed after 2 attempts (504, 503). error: service unavailable: {"error_message": "maintenance"}`, exhausted.Error())
+}
+
+// TestRetriesExhaustedTransportError - tests transport errors of every attempt are kept
+func TestRetriesExhaustedTransportError(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	_, err := NewClient(&ClientOptions{BaseURL: server.URL, RetryCount: 2}).Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	exhausted := &accounterrors.RetriesExhaustedError{}
+	check.True(errors.As(err, &exhausted))
+	check.Len(exhausted.Attempts, 2)
+	check.NotNil(exhausted.Attempts[1].Err)
+	check.Equal(exhausted.Attempts[1].Err, exhausted.Err)
+}
+
+// TestRetriesExhaustedAfterThrottling - tests attempts before a resend after 429 do not count towards exhausted retries
+func TestRetriesExhaustedAfterThrottling(t *testing.T) {
+	check := assert.New(t)
+	statusCodes := []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCodes[0])
+		statusCodes = statusCodes[1:]
+	}))
+	defer server.Close()
+
+	// the 504 of the create is ambiguous, so the handler stops after two of its three attempts
+	_, err := NewClient(&ClientOptions{BaseURL: server.URL, RetryCount: 3}).Create(context.Background(), AccountCreateParams{
+		ID:             "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		Type:           "accounts",
+	})
+	exhausted := &accounterrors.RetriesExhaustedError{}
+	check.False(errors.As(err, &exhausted))
+	statusCode, _ := accounterrors.StatusCode(err)
+	check.Equal(http.StatusGatewayTimeout, statusCode)
+}
+
+// TestFailedOnce - tests errors which are not retried are returned without attempt history
+func TestFailedOnce(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(&ClientOptions{BaseURL: server.URL}).Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	exhausted := &accounterrors.RetriesExhaustedError{}
+	check.False(errors.As(err, &exhausted))
+	check.Contains(err.Error(), "resource not found")
+}
+
+// TestRetryIf - tests the retry predicate of the client decides about retries and exhausted retries
+func TestRetryIf(t *testing.T) {
+	check := assert.New(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{BaseURL: server.URL, RetryCount: 2, RetryIf: func(statusCode int, err error) bool {
+		return statusCode == http.StatusBadGateway
+	}})
+	_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	exhausted := &accounterrors.RetriesExhaustedError{}
+	check.True(errors.As(err, &exhausted))
+	check.Equal(2, requests)
+}
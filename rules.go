@@ -0,0 +1,117 @@
+package accountlib
+
+import (
+	"fmt"
+
+	"accountlib/errors"
+)
+
+// CountryRule - checks create attributes against an account rule of a country
+type CountryRule func(attributes *AccountCreateAttributes) []accounterrors.FieldError
+
+// RulesEngine - holds account rules per country
+type RulesEngine struct {
+	rules map[CountryCode][]CountryRule
+}
+
+// countryRequirements - describes the bank identification documented for a country
+type countryRequirements struct {
+	bankIDLengths   []int
+	bankIDRequired  bool
+	bankIDForbidden bool
+	bankIDCode      string
+	bicRequired     bool
+	ibanForbidden   bool
+}
+
+// documentedCountryRequirements - bank identification rules documented by the api
+var documentedCountryRequirements = map[CountryCode]countryRequirements{
+	"AU": {bankIDLengths: []int{6}, bankIDCode: "AUBSB", bicRequired: true, ibanForbidden: true},
+	"BE": {bankIDLengths: []int{3}, bankIDRequired: true, bankIDCode: "BE"},
+	"CA": {bankIDLengths: []int{9}, bankIDCode: "CACPA", bicRequired: true, ibanForbidden: true},
+	"CH": {bankIDLengths: []int{5}, bankIDRequired: true, bankIDCode: "CHBCC"},
+	"DE": {bankIDLengths: []int{8}, bankIDRequired: true, bankIDCode: "DEBLZ"},
+	"ES": {bankIDLengths: []int{8}, bankIDRequired: true, bankIDCode: "ESNCC"},
+	"FR": {bankIDLengths: []int{10}, bankIDRequired: true, bankIDCode: "FR"},
+	"GB": {bankIDLengths: []int{6}, bankIDRequired: true, bankIDCode: "GBDSC", bicRequired: true},
+	"GR": {bankIDLengths: []int{7}, bankIDRequired: true, bankIDCode: "GRBIC"},
+	"HK": {bankIDLengths: []int{3}, bankIDCode: "HKNCC", bicRequired: true, ibanForbidden: true},
+	"IT": {bankIDLengths: []int{10, 11}, bankIDRequired: true, bankIDCode: "ITNCC"},
+	"LU": {bankIDLengths: []int{3}, bankIDRequired: true, bankIDCode: "LULUX"},
+	"NL": {bankIDForbidden: true, bicRequired: true},
+	"PL": {bankIDLengths: []int{8}, bankIDRequired: true, bankIDCode: "PLKNR"},
+	"PT": {bankIDLengths: []int{8}, bankIDRequired: true, bankIDCode: "PTNCC"},
+	"US": {bankIDLengths: []int{9}, bankIDRequired: true, bankIDCode: "USABA", bicRequired: true, ibanForbidden: true},
+}
+
+// defaultRulesEngine - rules engine used by Validate
+var defaultRulesEngine = NewRulesEngine()
+
+// NewRulesEngine - returns a rules engine holding the country rules documented by the api
+func NewRulesEngine() *RulesEngine {
+	engine := &RulesEngine{rules: make(map[CountryCode][]CountryRule)}
+	for country, requirements := range documentedCountryRequirements {
+		engine.Register(country, requirements.rule())
+	}
+	return engine
+}
+
+// Register - adds a rule for a country, rules are checked in the order of registration
+func (engine *RulesEngine) Register(country CountryCode, rule CountryRule) {
+	engine.rules[country] = append(engine.rules[country], rule)
+}
+
+// Check - returns the field errors of the create params according to the rules of its country
+func (engine *RulesEngine) Check(params *AccountCreateParams) []accounterrors.FieldError {
+	if params.Attributes == nil || params.Attributes.Country == nil {
+		return nil
+	}
+	var fieldErrors []accounterrors.FieldError
+	for _, rule := range engine.rules[*params.Attributes.Country] {
+		fieldErrors = append(fieldErrors, rule(params.Attributes)...)
+	}
+	return fieldErrors
+}
+
+// rule - converts the country requirements into a country rule
+func (requirements countryRequirements) rule() CountryRule {
+	return func(attributes *AccountCreateAttributes) []accounterrors.FieldError {
+		var fieldErrors []accounterrors.FieldError
+		addError := func(field, message string) {
+			fieldErrors = append(fieldErrors, accounterrors.FieldError{Field: field, Message: message})
+		}
+
+		switch {
+		case requirements.bankIDForbidden && attributes.BankID != "":
+			addError("attributes.bank_id", "is not supported for this country")
+		case requirements.bankIDRequired && attributes.BankID == "":
+			addError("attributes.bank_id", "is required for this country")
+		case attributes.BankID != "" && !containsLength(requirements.bankIDLengths, len(attributes.BankID)):
+			addError("attributes.bank_id", fmt.Sprintf("must have a length of %v characters", requirements.bankIDLengths))
+		}
+		if attributes.BankIDCode != requirements.bankIDCode && (attributes.BankIDCode != "" || requirements.bankIDRequired) {
+			if requirements.bankIDCode == "" {
+				addError("attributes.bank_id_code", "is not supported for this country")
+			} else {
+				addError("attributes.bank_id_code", fmt.Sprintf("must be %s", requirements.bankIDCode))
+			}
+		}
+		if requirements.bicRequired && attributes.Bic == "" {
+			addError("attributes.bic", "is required for this country")
+		}
+		if requirements.ibanForbidden && attributes.Iban != "" {
+			addError("attributes.iban", "is not supported for this country")
+		}
+		return fieldErrors
+	}
+}
+
+// containsLength - reports whether the length is one of the allowed lengths
+func containsLength(lengths []int, length int) bool {
+	for _, allowed := range lengths {
+		if allowed == length {
+			return true
+		}
+	}
+	return false
+}
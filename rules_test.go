@@ -0,0 +1,57 @@
+package accountlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/errors"
+)
+
+// TestRulesGBRequirements - tests GB requires bank id, bic and the GBDSC bank id code
+func TestRulesGBRequirements(t *testing.T) {
+	check := assert.New(t)
+	params := NewAccountBuilder().Country("GB").BankIDCode("GBSC").Build()
+
+	fieldErrors := NewRulesEngine().Check(&params)
+	check.Equal([]accounterrors.FieldError{
+		{Field: "attributes.bank_id", Message: "is required for this country"},
+		{Field: "attributes.bank_id_code", Message: "must be GBDSC"},
+		{Field: "attributes.bic", Message: "is required for this country"},
+	}, fieldErrors)
+}
+
+// TestRulesAUForbidsIban - tests AU rejects an iban on create
+func TestRulesAUForbidsIban(t *testing.T) {
+	check := assert.New(t)
+	params := NewAccountBuilder().Country("AU").BankIDCode("AUBSB").Bic("NATAAU33").Iban("AU1234").Build()
+
+	fieldErrors := NewRulesEngine().Check(&params)
+	check.Equal([]accounterrors.FieldError{{Field: "attributes.iban", Message: "is not supported for this country"}}, fieldErrors)
+}
+
+// TestRulesBankIDLength - tests the bank id length of a country
+func TestRulesBankIDLength(t *testing.T) {
+	check := assert.New(t)
+	params := NewAccountBuilder().Country("DE").BankID("1234").BankIDCode("DEBLZ").Build()
+
+	fieldErrors := NewRulesEngine().Check(&params)
+	check.Equal([]accounterrors.FieldError{{Field: "attributes.bank_id", Message: "must have a length of [8] characters"}}, fieldErrors)
+}
+
+// TestRulesRegister - tests custom rules are checked in addition to the documented ones
+func TestRulesRegister(t *testing.T) {
+	check := assert.New(t)
+	engine := NewRulesEngine()
+	engine.Register("NL", func(attributes *AccountCreateAttributes) []accounterrors.FieldError {
+		if attributes.AccountNumber == "" {
+			return []accounterrors.FieldError{{Field: "attributes.account_number", Message: "is required"}}
+		}
+		return nil
+	})
+	params := NewAccountBuilder().OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").Country("NL").Bic("ABNANL2A").Build()
+
+	check.Equal([]accounterrors.FieldError{{Field: "attributes.account_number", Message: "is required"}}, engine.Check(&params))
+	check.Contains(params.ValidateWithRules(engine).Error(), "attributes.account_number is required")
+	check.Equal(params.ValidateWithRules(NewRulesEngine()), nil)
+}
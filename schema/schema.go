@@ -0,0 +1,323 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema - compiled json schema supporting the keywords type, properties, required, additionalProperties, items,
+// minItems, maxItems, enum, pattern, minLength, maxLength, minimum and maximum, other keywords are ignored
+type Schema struct {
+	types                []string
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	items                *Schema
+	minItems             *int
+	maxItems             *int
+	enum                 []interface{}
+	pattern              *regexp.Regexp
+	minLength            *int
+	maxLength            *int
+	minimum              *float64
+	maximum              *float64
+}
+
+// document - holds the keywords of a json schema document
+type document struct {
+	Type                 json.RawMessage      `json:"type"`
+	Properties           map[string]*document `json:"properties"`
+	Required             []string             `json:"required"`
+	AdditionalProperties json.RawMessage      `json:"additionalProperties"`
+	Items                *document            `json:"items"`
+	MinItems             *int                 `json:"minItems"`
+	MaxItems             *int                 `json:"maxItems"`
+	Enum                 []interface{}        `json:"enum"`
+	Pattern              string               `json:"pattern"`
+	MinLength            *int                 `json:"minLength"`
+	MaxLength            *int                 `json:"maxLength"`
+	Minimum              *float64             `json:"minimum"`
+	Maximum              *float64             `json:"maximum"`
+}
+
+// Violation - a value not matching the schema, Path is the json pointer of the value, empty for the document itself
+type Violation struct {
+	Path    string
+	Message string
+}
+
+// String - returns the path and message of the violation
+func (violation Violation) String() string {
+	path := violation.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + violation.Message
+}
+
+// ValidationError - returned by Validate when a document does not match the schema
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error - returns the violations of the validation error
+func (e *ValidationError) Error() string {
+	violations := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		violations[i] = violation.String()
+	}
+	return "schema validation failed: " + strings.Join(violations, ", ")
+}
+
+// Compile - compiles a json schema document
+func Compile(schemaDocument []byte) (*Schema, error) {
+	doc := &document{}
+	if err := decode(schemaDocument, doc); err != nil {
+		return nil, fmt.Errorf("invalid schema: %s", err.Error())
+	}
+	return compile(doc, "")
+}
+
+// MustCompile - compiles a json schema document, panicking when it is invalid
+func MustCompile(schemaDocument []byte) *Schema {
+	schema, err := Compile(schemaDocument)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// compile - compiles the keywords of a schema document, path is the location of the document used in errors
+func compile(doc *document, path string) (*Schema, error) {
+	schema := &Schema{
+		required:  doc.Required,
+		minItems:  doc.MinItems,
+		maxItems:  doc.MaxItems,
+		enum:      doc.Enum,
+		minLength: doc.MinLength,
+		maxLength: doc.MaxLength,
+		minimum:   doc.Minimum,
+		maximum:   doc.Maximum,
+	}
+
+	var err error
+	if len(doc.Type) > 0 {
+		if schema.types, err = compileTypes(doc.Type); err != nil {
+			return nil, fmt.Errorf("invalid schema: %s/type: %s", path, err.Error())
+		}
+	}
+	if doc.Pattern != "" {
+		if schema.pattern, err = regexp.Compile(doc.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid schema: %s/pattern: %s", path, err.Error())
+		}
+	}
+	if len(doc.Properties) > 0 {
+		schema.properties = make(map[string]*Schema, len(doc.Properties))
+		for name, property := range doc.Properties {
+			if schema.properties[name], err = compile(property, path+"/properties/"+escape(name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if doc.Items != nil {
+		if schema.items, err = compile(doc.Items, path+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	switch additional := bytes.TrimSpace(doc.AdditionalProperties); {
+	case len(additional) == 0 || bytes.Equal(additional, []byte("true")):
+	case bytes.Equal(additional, []byte("false")):
+		schema.noAdditional = true
+	default:
+		additionalDocument := &document{}
+		if err = decode(additional, additionalDocument); err != nil {
+			return nil, fmt.Errorf("invalid schema: %s/additionalProperties: %s", path, err.Error())
+		}
+		if schema.additionalProperties, err = compile(additionalDocument, path+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+// compileTypes - reads the type keyword, which holds a type name or a list of them
+func compileTypes(raw json.RawMessage) ([]string, error) {
+	var types []string
+	if err := json.Unmarshal(raw, &types); err != nil {
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return nil, errors.New("must be a type name or a list of type names")
+		}
+		types = []string{name}
+	}
+	for _, name := range types {
+		switch name {
+		case "object", "array", "string", "number", "integer", "boolean", "null":
+		default:
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	return types, nil
+}
+
+// Validate - validates a json document against the schema, returning a *ValidationError holding every violation
+// Documents which are not valid json, like html error pages or truncated responses, fail with a single violation
+func (schema *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected data after the document")
+	}
+	if err != nil {
+		return &ValidationError{Violations: []Violation{{Message: "invalid json: " + err.Error()}}}
+	}
+
+	if violations := schema.validate(value, "", nil); len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// validate - appends the violations of a decoded value at path to violations
+func (schema *Schema) validate(value interface{}, path string, violations []Violation) []Violation {
+	if len(schema.types) > 0 && !matchesType(value, schema.types) {
+		return append(violations, Violation{Path: path, Message: fmt.Sprintf("expected %s, got %s", strings.Join(schema.types, " or "), typeName(value))})
+	}
+	if len(schema.enum) > 0 && !schema.inEnum(value) {
+		violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("value %s is not one of the allowed values", encode(value))})
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		violations = schema.validateObject(value, path, violations)
+	case []interface{}:
+		if schema.minItems != nil && len(value) < *schema.minItems {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("expected at least %d items, got %d", *schema.minItems, len(value))})
+		}
+		if schema.maxItems != nil && len(value) > *schema.maxItems {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("expected at most %d items, got %d", *schema.maxItems, len(value))})
+		}
+		if schema.items != nil {
+			for i, item := range value {
+				violations = schema.items.validate(item, fmt.Sprintf("%s/%d", path, i), violations)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(value)
+		if schema.minLength != nil && length < *schema.minLength {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("expected at least %d characters, got %d", *schema.minLength, length)})
+		}
+		if schema.maxLength != nil && length > *schema.maxLength {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("expected at most %d characters, got %d", *schema.maxLength, length)})
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(value) {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("value %q does not match pattern %q", value, schema.pattern.String())})
+		}
+	case json.Number:
+		number, _ := value.Float64()
+		if schema.minimum != nil && number < *schema.minimum {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("value %s is less than the minimum %v", value, *schema.minimum)})
+		}
+		if schema.maximum != nil && number > *schema.maximum {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("value %s is greater than the maximum %v", value, *schema.maximum)})
+		}
+	}
+	return violations
+}
+
+// validateObject - appends the violations of the required, properties and additionalProperties keywords
+func (schema *Schema) validateObject(object map[string]interface{}, path string, violations []Violation) []Violation {
+	for _, name := range schema.required {
+		if _, ok := object[name]; !ok {
+			violations = append(violations, Violation{Path: path + "/" + escape(name), Message: "required property is missing"})
+		}
+	}
+
+	// properties are validated in order, so violations are reported deterministically
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertyPath := path + "/" + escape(name)
+		if property, ok := schema.properties[name]; ok {
+			violations = property.validate(object[name], propertyPath, violations)
+		} else if schema.noAdditional {
+			violations = append(violations, Violation{Path: propertyPath, Message: "additional property is not allowed"})
+		} else if schema.additionalProperties != nil {
+			violations = schema.additionalProperties.validate(object[name], propertyPath, violations)
+		}
+	}
+	return violations
+}
+
+// inEnum - reports whether the value is one of the values of the enum keyword
+func (schema *Schema) inEnum(value interface{}) bool {
+	for _, allowed := range schema.enum {
+		if reflect.DeepEqual(value, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesType - reports whether a decoded value has one of the types
+func matchesType(value interface{}, types []string) bool {
+	name := typeName(value)
+	for _, allowed := range types {
+		if allowed == name || (allowed == "number" && name == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName - returns the json schema type of a decoded value, numbers without fraction are integers
+func typeName(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// decode - decodes json into v, keeping numbers as json.Number so they compare equal to validated values
+func decode(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// encode - returns the json encoding of a decoded value for messages
+func encode(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// escape - escapes a property name for use in a json pointer
+func escape(name string) string {
+	return strings.Replace(strings.Replace(name, "~", "~0", -1), "/", "~1", -1)
+}
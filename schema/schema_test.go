@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSchema - schema exercising every supported keyword
+const testSchema = `{
+  "type": "object",
+  "required": ["id", "tags"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "string", "pattern": "^[a-z]+$", "minLength": 2, "maxLength": 4},
+    "kind": {"type": "string", "enum": ["a", "b"]},
+    "count": {"type": "integer", "minimum": 0, "maximum": 10},
+    "ratio": {"type": "number"},
+    "tags": {"type": ["array", "null"], "minItems": 1, "maxItems": 2, "items": {"type": "string"}},
+    "labels": {"type": "object", "additionalProperties": {"type": "boolean"}}
+  }
+}`
+
+// TestValidateValid - tests a document matching the schema
+func TestValidateValid(t *testing.T) {
+	check := assert.New(t)
+	schema := MustCompile([]byte(testSchema))
+	check.Nil(schema.Validate([]byte(`{"id": "abc", "kind": "a", "count": 10, "ratio": 0.5, "tags": ["x"], "labels": {"y": true}}`)))
+	check.Nil(schema.Validate([]byte(`{"id": "ab", "tags": null}`)))
+}
+
+// TestValidateViolations - tests every violation is reported with the json pointer of the value
+func TestValidateViolations(t *testing.T) {
+	check := assert.New(t)
+	schema := MustCompile([]byte(testSchema))
+	err := schema.Validate([]byte(`{"id": "ABCDE", "kind": "c", "count": 1.5, "ratio": "1", "tags": [], "labels": {"a/b": 1}, "extra": 1}`))
+
+	validationError := &ValidationError{}
+	check.True(errors.As(err, &validationError))
+	check.Equal([]Violation{
+		{Path: "/count", Message: "expected integer, got number"},
+		{Path: "/extra", Message: "additional property is not allowed"},
+		{Path: "/id", Message: "expected at most 4 characters, got 5"},
+		{Path: "/id", Message: `value "ABCDE" does not match pattern "^[a-z]+$"`},
+		{Path: "/kind", Message: `value "c" is not one of the allowed values`},
+		{Path: "/labels/a~1b", Message: "expected boolean, got integer"},
+		{Path: "/ratio", Message: "expected number, got string"},
+		{Path: "/tags", Message: "expected at least 1 items, got 0"},
+	}, validationError.Violations)
+
+	err = schema.Validate([]byte(`{"count": -1, "tags": [1, "x", "y"]}`))
+	check.EqualError(err, "schema validation failed: /id: required property is missing, /count: value -1 is less than the minimum 0, "+
+		"/tags: expected at most 2 items, got 3, /tags/0: expected string, got integer")
+}
+
+// TestValidateInvalidJSON - tests documents which are not json, like html error pages and truncated responses
+func TestValidateInvalidJSON(t *testing.T) {
+	check := assert.New(t)
+	schema := MustCompile([]byte(testSchema))
+	check.EqualError(schema.Validate([]byte(`<html><body>502 Bad Gateway</body></html>`)),
+		"schema validation failed: /: invalid json: invalid character '<' looking for beginning of value")
+	check.EqualError(schema.Validate([]byte(`{"id": "abc", "tags": [`)), "schema validation failed: /: invalid json: unexpected EOF")
+	check.EqualError(schema.Validate([]byte(`{} {}`)), "schema validation failed: /: invalid json: unexpected data after the document")
+	check.EqualError(schema.Validate([]byte(`[]`)), "schema validation failed: /: expected object, got array")
+}
+
+// TestCompileInvalid - tests invalid schema documents are rejected
+func TestCompileInvalid(t *testing.T) {
+	check := assert.New(t)
+	_, err := Compile([]byte(`{"type": "thing"}`))
+	check.EqualError(err, `invalid schema: /type: unknown type "thing"`)
+	_, err = Compile([]byte(`{"properties": {"id": {"pattern": "("}}}`))
+	check.Contains(err.Error(), "invalid schema: /properties/id/pattern: ")
+	_, err = Compile([]byte(`{"type": `))
+	check.EqualError(err, "invalid schema: unexpected EOF")
+	check.Panics(func() { MustCompile([]byte(`[]`)) })
+}
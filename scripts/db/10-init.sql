@@ -0,0 +1,2 @@
+CREATE USER interview_accountapi_user WITH PASSWORD '123';
+CREATE DATABASE interview_accountapi WITH OWNER = interview_accountapi_user;
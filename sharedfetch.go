@@ -0,0 +1,103 @@
+package accountlib
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// fetchGroup - collapses concurrent fetches of an account into a single request
+// The request runs detached from the contexts of the callers, so a caller giving up does not fail the others. It is
+// cancelled once every caller has given up
+type fetchGroup struct {
+	mutex sync.Mutex
+	calls map[string]*fetchCall
+}
+
+// fetchCall - in flight fetch of an account shared by its waiting callers
+type fetchCall struct {
+	done        chan struct{}
+	accountData *AccountData
+	err         error
+	waiters     int
+	cancel      context.CancelFunc
+}
+
+// newFetchGroup - returns an empty fetch group
+func newFetchGroup() *fetchGroup {
+	return &fetchGroup{calls: make(map[string]*fetchCall)}
+}
+
+// detachedContext - context carrying the values of its parent, like trace spans, without its deadline and cancellation
+type detachedContext struct {
+	parent context.Context
+}
+
+// Deadline - returns no deadline
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done - returns nil, the context is never done
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err - returns nil, the context is never done
+func (detachedContext) Err() error {
+	return nil
+}
+
+// Value - returns the value of the parent
+func (ctx detachedContext) Value(key interface{}) interface{} {
+	return ctx.parent.Value(key)
+}
+
+// sharedFetch - fetches an account once for all concurrent callers, every caller gets its own copy of the account data
+// The request carries the context values of the first caller but not its cancellation, its attempts are bounded by the
+// timeout of the client and it is cancelled when every caller stopped waiting. Every caller stops waiting when its own context is done
+func (client *Client) sharedFetch(ctx context.Context, accountID string) (*AccountData, error) {
+	group := client.fetchGroup
+	group.mutex.Lock()
+	call, joined := group.calls[accountID]
+	if !joined {
+		fetchCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+		call = &fetchCall{done: make(chan struct{}), cancel: cancel}
+		group.calls[accountID] = call
+		go func() {
+			defer cancel()
+			accountData, _, err := client.fetch(fetchCtx, accountID, nil)
+			group.mutex.Lock()
+			if group.calls[accountID] == call {
+				delete(group.calls, accountID)
+			}
+			group.mutex.Unlock()
+			call.accountData, call.err = accountData, err
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	group.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		// callers joining a fetch started by another caller got a cache hit
+		if joined {
+			client.recordCacheHit(OperationFetch)
+		}
+		// the account data is copied for every caller, including the first, so callers modifying it do not race
+		return call.accountData.Clone(), call.err
+	case <-ctx.Done():
+		group.mutex.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// nobody waits for the fetch anymore, later callers start a fresh one
+			if group.calls[accountID] == call {
+				delete(group.calls, accountID)
+			}
+			call.cancel()
+		}
+		group.mutex.Unlock()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,117 @@
+package accountlib
+
+import (
+	"context"
+	"sync"
+
+	"accountlib/errors"
+)
+
+// admittedKey - context key marking requests of operations accepted before the client was shut down
+type admittedKey struct{}
+
+// lifecycle - tracks the requests and background goroutines of a client for shutting it down, shared by its copies
+type lifecycle struct {
+	mutex     sync.Mutex
+	closed    bool
+	inflight  int
+	done      chan struct{}
+	drained   chan struct{}
+	isDrained bool
+}
+
+// newLifecycle - returns the lifecycle of a running client
+func newLifecycle() *lifecycle {
+	return &lifecycle{done: make(chan struct{}), drained: make(chan struct{})}
+}
+
+// Shutdown - stops accepting operations and waits for in flight requests, asynchronous creates and background
+// goroutines, like Watch and StreamEvents, before closing the idle connections of the http client
+// Operations started afterwards fail with accounterrors.ErrClientShutdown, watches and event streams are stopped.
+// Asynchronous creates accepted before the shutdown still run. When ctx is done first, the remaining asynchronous
+// creates are cancelled and the error of ctx is returned
+func (client *Client) Shutdown(ctx context.Context) error {
+	client.lifecycle.close()
+	err := client.jobs.Shutdown(ctx)
+	if err == nil {
+		err = client.lifecycle.wait(ctx)
+	}
+	if client.httpClient != nil {
+		client.httpClient.CloseIdleConnections()
+	}
+	return err
+}
+
+// admit - marks the context of an operation accepted before the shutdown, its requests are sent during the shutdown
+func admit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, admittedKey{}, true)
+}
+
+// enter - counts a request as in flight, returning false when the client is shut down and the request not admitted
+func (lifecycle *lifecycle) enter(ctx context.Context) bool {
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+	if lifecycle.closed && (ctx == nil || ctx.Value(admittedKey{}) == nil) {
+		return false
+	}
+	lifecycle.inflight++
+	return true
+}
+
+// leave - counts a request as done
+func (lifecycle *lifecycle) leave() {
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+	lifecycle.inflight--
+	lifecycle.drain()
+}
+
+// background - returns the context of a background goroutine, which is cancelled when the client shuts down
+// The goroutine counts as in flight until the returned release function is called
+func (lifecycle *lifecycle) background(ctx context.Context) (context.Context, func(), error) {
+	if !lifecycle.enter(ctx) {
+		return nil, nil, accounterrors.ErrClientShutdown
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-lifecycle.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		cancel()
+		lifecycle.leave()
+	}, nil
+}
+
+// close - stops accepting requests and cancels the background goroutines
+func (lifecycle *lifecycle) close() {
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+	if lifecycle.closed {
+		return
+	}
+	lifecycle.closed = true
+	close(lifecycle.done)
+	lifecycle.drain()
+}
+
+// drain - signals a closed lifecycle without requests in flight, it is called with the mutex held
+func (lifecycle *lifecycle) drain() {
+	if lifecycle.closed && lifecycle.inflight == 0 && !lifecycle.isDrained {
+		lifecycle.isDrained = true
+		close(lifecycle.drained)
+	}
+}
+
+// wait - waits until no request is in flight after the lifecycle was closed, returning early with the error of ctx
+func (lifecycle *lifecycle) wait(ctx context.Context) error {
+	select {
+	case <-lifecycle.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,82 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/errors"
+)
+
+// TestShutdown - tests the shutdown waits for in flight requests, stops watches and rejects new operations
+func TestShutdown(t *testing.T) {
+	check := assert.New(t)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc") {
+			started <- struct{}{}
+			<-release
+		}
+		fmt.Fprint(w, `{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "version": 0}}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	changes, err := client.Watch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", time.Millisecond)
+	check.Nil(err)
+	inflight := make(chan error, 1)
+	go func() {
+		_, err := client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+		inflight <- err
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- client.Shutdown(context.Background())
+	}()
+	for range changes {
+	}
+	select {
+	case <-shutdown:
+		t.Fatal("shutdown returned before the in flight request finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	check.Nil(<-inflight)
+	check.Nil(<-shutdown)
+
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.True(errors.Is(err, accounterrors.ErrClientShutdown))
+	_, err = client.Watch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", time.Millisecond)
+	check.True(errors.Is(err, accounterrors.ErrClientShutdown))
+	check.Nil(client.Shutdown(context.Background()))
+}
+
+// TestShutdownContext - tests the shutdown returns the error of its context when requests are still in flight
+func TestShutdownContext(t *testing.T) {
+	check := assert.New(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+	go client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	check.Equal(context.DeadlineExceeded, client.Shutdown(ctx))
+}
@@ -0,0 +1,31 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+
+	"accountlib/credentials"
+)
+
+// LoadKey - returns a signing key with the key id holding the PEM encoded private key of a credential of the store
+func LoadKey(ctx context.Context, store credentials.CredentialStore, name string, keyID string) (Key, error) {
+	data, err := store.Credential(ctx, name)
+	if err != nil {
+		return Key{}, fmt.Errorf("unable to load signing key %s. error: %w", keyID, err)
+	}
+	privateKey, err := ParsePrivateKey(data)
+	if err != nil {
+		return Key{}, fmt.Errorf("unable to load signing key %s. error: %w", keyID, err)
+	}
+	return Key{ID: keyID, PrivateKey: privateKey}, nil
+}
+
+// LoadPublicKey - returns the PEM encoded public key of a credential of the store, for verifying responses
+func LoadPublicKey(ctx context.Context, store credentials.CredentialStore, name string) (crypto.PublicKey, error) {
+	data, err := store.Credential(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load public key %s. error: %w", name, err)
+	}
+	return ParsePublicKey(data)
+}
@@ -0,0 +1,266 @@
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// signature header constants
+const (
+	SignatureHeader = "Signature"
+	DigestHeader    = "Digest"
+	DateHeader      = "Date"
+	requestTarget   = "(request-target)"
+)
+
+// algorithm names of the signature header
+const (
+	AlgorithmRSASHA256 = "rsa-sha256"
+	AlgorithmEd25519   = "ed25519"
+)
+
+// ErrUnknownKey - matches errors returned for key ids which are not configured
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Key - private key used for signing requests, ID is sent as keyId of the signature so the api finds the public key
+// RSA and Ed25519 private keys are supported
+type Key struct {
+	ID         string
+	PrivateKey crypto.Signer
+}
+
+// algorithm - returns the signature algorithm of the key
+func (key Key) algorithm() (string, error) {
+	switch key.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		return AlgorithmRSASHA256, nil
+	case ed25519.PrivateKey:
+		return AlgorithmEd25519, nil
+	default:
+		return "", fmt.Errorf("unsupported signing key %s: %T", key.ID, key.PrivateKey)
+	}
+}
+
+// sign - signs the signing string with the key
+func (key Key) sign(signingString []byte) ([]byte, error) {
+	if _, ok := key.PrivateKey.(ed25519.PrivateKey); ok {
+		return key.PrivateKey.Sign(rand.Reader, signingString, crypto.Hash(0))
+	}
+	hash := sha256.Sum256(signingString)
+	return key.PrivateKey.Sign(rand.Reader, hash[:], crypto.SHA256)
+}
+
+// ParsePrivateKey - parses a PEM encoded RSA or Ed25519 private key, in PKCS #1 or PKCS #8 form
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid private key: no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key. error: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("invalid private key: unsupported type %T", key)
+	}
+	return signer, nil
+}
+
+// Signer - signs requests with the active one of its keys, following the http signatures draft
+// Keys can be added, removed and activated while the signer is in use, so keys are rotated without recreating clients
+type Signer struct {
+	mutex   sync.RWMutex
+	keys    map[string]Key
+	active  string
+	headers []string
+	now     func() time.Time
+}
+
+// NewSigner - returns a signer holding the keys, the first key is active
+func NewSigner(keys ...Key) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("invalid signer: at least one key is required")
+	}
+	signer := &Signer{
+		keys:    make(map[string]Key, len(keys)),
+		headers: []string{requestTarget, "host", "date", "digest"},
+		now:     time.Now,
+	}
+	for _, key := range keys {
+		if err := signer.AddKey(key); err != nil {
+			return nil, err
+		}
+	}
+	signer.active = keys[0].ID
+	return signer, nil
+}
+
+// AddKey - adds a key or replaces the key with the same id, the active key stays active
+func (signer *Signer) AddKey(key Key) error {
+	if key.ID == "" {
+		return errors.New("invalid signing key: id is required")
+	}
+	if key.PrivateKey == nil {
+		return fmt.Errorf("invalid signing key %s: private key is required", key.ID)
+	}
+	if _, err := key.algorithm(); err != nil {
+		return err
+	}
+	signer.mutex.Lock()
+	defer signer.mutex.Unlock()
+	signer.keys[key.ID] = key
+	return nil
+}
+
+// RemoveKey - removes a key which is not active, like a retired key after a rotation
+func (signer *Signer) RemoveKey(keyID string) error {
+	signer.mutex.Lock()
+	defer signer.mutex.Unlock()
+	if _, ok := signer.keys[keyID]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	if keyID == signer.active {
+		return fmt.Errorf("signing key %s is active, activate another key before removing it", keyID)
+	}
+	delete(signer.keys, keyID)
+	return nil
+}
+
+// UseKey - activates a key, requests signed afterwards carry its signature
+func (signer *Signer) UseKey(keyID string) error {
+	signer.mutex.Lock()
+	defer signer.mutex.Unlock()
+	if _, ok := signer.keys[keyID]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	signer.active = keyID
+	return nil
+}
+
+// ActiveKeyID - returns the id of the active key
+func (signer *Signer) ActiveKeyID() string {
+	signer.mutex.RLock()
+	defer signer.mutex.RUnlock()
+	return signer.active
+}
+
+// activeKey - returns the active key
+func (signer *Signer) activeKey() Key {
+	signer.mutex.RLock()
+	defer signer.mutex.RUnlock()
+	return signer.keys[signer.active]
+}
+
+// Sign - signs a request with the active key, setting the Date header when it is missing, the Digest header of the
+// body and the Signature header. The body is read into memory for the digest and replaced by a copy
+func (signer *Signer) Sign(request *http.Request) error {
+	var body []byte
+	if request.Body != nil && request.Body != http.NoBody {
+		var err error
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return fmt.Errorf("unable to read request body for signing. error: %w", err)
+		}
+		_ = request.Body.Close()
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	if request.Header.Get(DateHeader) == "" {
+		request.Header.Set(DateHeader, signer.now().UTC().Format(http.TimeFormat))
+	}
+	request.Header.Set(DigestHeader, Digest(body))
+
+	key := signer.activeKey()
+	algorithm, err := key.algorithm()
+	if err != nil {
+		return err
+	}
+	signature, err := key.sign(requestSigningString(request, signer.headers))
+	if err != nil {
+		return fmt.Errorf("unable to sign request with key %s. error: %w", key.ID, err)
+	}
+	request.Header.Set(SignatureHeader, fmt.Sprintf(`keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		key.ID, algorithm, strings.Join(signer.headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// Digest - returns the value of the Digest header of a body
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// requestSigningString - returns the string signed for the headers of a request
+func requestSigningString(request *http.Request, headers []string) []byte {
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	return signingString(headers, request.Header, map[string]string{
+		requestTarget: strings.ToLower(request.Method) + " " + request.URL.RequestURI(),
+		"host":        host,
+	})
+}
+
+// signingString - returns the string signed for the headers, pseudo headers like the request target are taken from pseudo
+func signingString(headers []string, header http.Header, pseudo map[string]string) []byte {
+	lines := make([]string, len(headers))
+	for i, name := range headers {
+		value, ok := pseudo[name]
+		if !ok {
+			value = header.Get(name)
+		}
+		lines[i] = name + ": " + value
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// Transport - returns a round tripper signing every request with the signer before passing it to base
+// A nil base uses http.DefaultTransport. Requests are cloned before they are signed
+func (signer *Signer) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{signer: signer, base: base}
+}
+
+// transport - round tripper signing requests
+type transport struct {
+	signer *Signer
+	base   http.RoundTripper
+}
+
+// RoundTrip - signs a clone of the request and sends it
+func (transport *transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	signed := request.Clone(request.Context())
+	if err := transport.signer.Sign(signed); err != nil {
+		if request.Body != nil {
+			_ = request.Body.Close()
+		}
+		return nil, err
+	}
+	return transport.base.RoundTrip(signed)
+}
+
+// CloseIdleConnections - closes the idle connections of the base round tripper
+func (transport *transport) CloseIdleConnections() {
+	if closer, ok := transport.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
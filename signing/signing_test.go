@@ -0,0 +1,130 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// signaturePattern - extracts the fields of a signature header
+var signaturePattern = regexp.MustCompile(`^keyId="([^"]+)",algorithm="([^"]+)",headers="([^"]+)",signature="([^"]+)"$`)
+
+// verifySignature - verifies the signature header of a request with the public key
+func verifySignature(t *testing.T, request *http.Request, publicKey crypto.PublicKey) string {
+	match := signaturePattern.FindStringSubmatch(request.Header.Get(SignatureHeader))
+	if !assert.NotNil(t, match) {
+		return ""
+	}
+	signature, err := base64.StdEncoding.DecodeString(match[4])
+	assert.Nil(t, err)
+	signed := requestSigningString(request, strings.Split(match[3], " "))
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		hash := sha256.Sum256(signed)
+		assert.Nil(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature))
+	case ed25519.PublicKey:
+		assert.True(t, ed25519.Verify(key, signed, signature))
+	}
+	return match[1]
+}
+
+// TestSignRequest - tests requests are signed over the request target, host, date and digest of the body
+func TestSignRequest(t *testing.T) {
+	check := assert.New(t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	check.Nil(err)
+	signer, err := NewSigner(Key{ID: "rsa-key", PrivateKey: rsaKey})
+	check.Nil(err)
+	signer.now = func() time.Time { return time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC) }
+
+	request := httptest.NewRequest(http.MethodPost, "https://api.example.com/v1/organisation/accounts?page=1", strings.NewReader(`{"data": {}}`))
+	check.Nil(signer.Sign(request))
+	check.Equal("Thu, 04 Mar 2021 10:11:12 GMT", request.Header.Get(DateHeader))
+	check.Equal(Digest([]byte(`{"data": {}}`)), request.Header.Get(DigestHeader))
+	check.Equal("rsa-key", verifySignature(t, request, &rsaKey.PublicKey))
+	check.Contains(request.Header.Get(SignatureHeader), `algorithm="rsa-sha256",headers="(request-target) host date digest"`)
+	check.Equal("(request-target): post /v1/organisation/accounts?page=1\nhost: api.example.com\ndate: Thu, 04 Mar 2021 10:11:12 GMT\ndigest: "+Digest([]byte(`{"data": {}}`)),
+		string(requestSigningString(request, signer.headers)))
+	body, _ := ioutil.ReadAll(request.Body)
+	check.Equal(`{"data": {}}`, string(body))
+}
+
+// TestKeyRotation - tests keys are added, activated and removed while the signer is in use
+func TestKeyRotation(t *testing.T) {
+	check := assert.New(t)
+	oldPublic, oldPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	newPublic, newPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	signer, err := NewSigner(Key{ID: "2021-q1", PrivateKey: oldPrivate})
+	check.Nil(err)
+
+	var mutex sync.Mutex
+	var keyIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyID := signaturePattern.FindStringSubmatch(r.Header.Get(SignatureHeader))[1]
+		publicKey := map[string]ed25519.PublicKey{"2021-q1": oldPublic, "2021-q2": newPublic}[keyID]
+		mutex.Lock()
+		keyIDs = append(keyIDs, verifySignature(t, r, publicKey))
+		mutex.Unlock()
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: signer.Transport(nil)}
+
+	response, err := client.Get(server.URL + "/v1/organisation/accounts")
+	check.Nil(err)
+	response.Body.Close()
+	check.Nil(signer.AddKey(Key{ID: "2021-q2", PrivateKey: newPrivate}))
+	check.Equal("2021-q1", signer.ActiveKeyID())
+	check.Nil(signer.UseKey("2021-q2"))
+	response, err = client.Get(server.URL + "/v1/organisation/accounts")
+	check.Nil(err)
+	response.Body.Close()
+	check.Equal([]string{"2021-q1", "2021-q2"}, keyIDs)
+
+	check.NotNil(signer.RemoveKey("2021-q2"))
+	check.Nil(signer.RemoveKey("2021-q1"))
+	check.True(errors.Is(signer.UseKey("2021-q1"), ErrUnknownKey))
+	check.True(errors.Is(signer.RemoveKey("2021-q1"), ErrUnknownKey))
+}
+
+// TestSignerKeys - tests invalid keys are rejected and PEM keys are parsed
+func TestSignerKeys(t *testing.T) {
+	check := assert.New(t)
+	_, err := NewSigner()
+	check.NotNil(err)
+	_, err = NewSigner(Key{PrivateKey: ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))})
+	check.NotNil(err)
+	_, err = NewSigner(Key{ID: "missing"})
+	check.NotNil(err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	check.Nil(err)
+	parsed, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	check.Nil(err)
+	check.Equal(rsaKey.D, parsed.(*rsa.PrivateKey).D)
+
+	edKey := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	encoded, err := x509.MarshalPKCS8PrivateKey(edKey)
+	check.Nil(err)
+	parsed, err = ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encoded}))
+	check.Nil(err)
+	check.Equal(edKey, parsed)
+
+	_, err = ParsePrivateKey([]byte("not a key"))
+	check.NotNil(err)
+}
@@ -0,0 +1,219 @@
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"accountlib/errors"
+)
+
+// eventStreamType - content type of event streams, which are not verified since they never end
+const eventStreamType = "text/event-stream"
+
+// signatureParameter - matches a parameter of a signature header, like keyId="2021-q1"
+var signatureParameter = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// ParsePublicKey - parses a PEM encoded RSA or Ed25519 public key, in PKIX or PKCS #1 form
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid public key: no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key. error: %w", err)
+	}
+	return key, nil
+}
+
+// Verifier - verifies the signatures and digests of responses signed by the api with the public keys of the api
+// Strict verifiers reject unsigned responses as well. Keys can be added and removed while the verifier is in use
+type Verifier struct {
+	mutex  sync.RWMutex
+	keys   map[string]crypto.PublicKey
+	strict bool
+}
+
+// NewVerifier - returns a verifier of responses signed with the public keys by key id
+// Strict verifiers reject unsigned responses, others only verify responses carrying a signature
+func NewVerifier(keys map[string]crypto.PublicKey, strict bool) (*Verifier, error) {
+	verifier := &Verifier{keys: make(map[string]crypto.PublicKey, len(keys)), strict: strict}
+	for keyID, publicKey := range keys {
+		if err := verifier.AddKey(keyID, publicKey); err != nil {
+			return nil, err
+		}
+	}
+	return verifier, nil
+}
+
+// AddKey - adds the public key of a key id or replaces it
+func (verifier *Verifier) AddKey(keyID string, publicKey crypto.PublicKey) error {
+	switch publicKey.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+	default:
+		return fmt.Errorf("unsupported public key %s: %T", keyID, publicKey)
+	}
+	verifier.mutex.Lock()
+	defer verifier.mutex.Unlock()
+	verifier.keys[keyID] = publicKey
+	return nil
+}
+
+// RemoveKey - removes the public key of a key id, responses signed with it fail verification afterwards
+func (verifier *Verifier) RemoveKey(keyID string) {
+	verifier.mutex.Lock()
+	defer verifier.mutex.Unlock()
+	delete(verifier.keys, keyID)
+}
+
+// key - returns the public key of a key id
+func (verifier *Verifier) key(keyID string) (crypto.PublicKey, bool) {
+	verifier.mutex.RLock()
+	defer verifier.mutex.RUnlock()
+	publicKey, ok := verifier.keys[keyID]
+	return publicKey, ok
+}
+
+// Verify - verifies the signature of response headers and the digest of the body, returning a
+// *accounterrors.SignatureError matching accounterrors.ErrBadSignature when they do not verify
+// The signature has to cover the digest, so the body can not be changed without breaking the signature
+func (verifier *Verifier) Verify(header http.Header, body []byte) error {
+	value := header.Get(SignatureHeader)
+	if value == "" {
+		if verifier.strict {
+			return &accounterrors.SignatureError{Reason: "response is not signed"}
+		}
+		return nil
+	}
+	parameters := make(map[string]string)
+	for _, match := range signatureParameter.FindAllStringSubmatch(value, -1) {
+		parameters[match[1]] = match[2]
+	}
+	keyID := parameters["keyId"]
+	badSignature := func(reason string) error {
+		return &accounterrors.SignatureError{KeyID: keyID, Reason: reason}
+	}
+
+	publicKey, ok := verifier.key(keyID)
+	if !ok {
+		return badSignature("unknown key")
+	}
+	headers := strings.Fields(parameters["headers"])
+	if !containsHeader(headers, "digest") {
+		return badSignature("digest is not signed")
+	}
+	if header.Get(DigestHeader) != Digest(body) {
+		return badSignature("digest does not match the body")
+	}
+	signature, err := base64.StdEncoding.DecodeString(parameters["signature"])
+	if err != nil {
+		return badSignature("signature is not base64 encoded")
+	}
+	signed := signingString(headers, header, nil)
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		hash := sha256.Sum256(signed)
+		if parameters["algorithm"] != AlgorithmRSASHA256 || rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) != nil {
+			return badSignature("signature does not verify")
+		}
+	case ed25519.PublicKey:
+		if parameters["algorithm"] != AlgorithmEd25519 || !ed25519.Verify(key, signed, signature) {
+			return badSignature("signature does not verify")
+		}
+	}
+	return nil
+}
+
+// containsHeader - reports whether the signed headers hold the header
+func containsHeader(headers []string, name string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignResponse - signs response headers and body with the active key, setting the Date header when it is missing,
+// the Digest header of the body and the Signature header over the date and digest, like the api signs its responses
+func (signer *Signer) SignResponse(header http.Header, body []byte) error {
+	if header.Get(DateHeader) == "" {
+		header.Set(DateHeader, signer.now().UTC().Format(http.TimeFormat))
+	}
+	header.Set(DigestHeader, Digest(body))
+	headers := []string{"date", "digest"}
+
+	key := signer.activeKey()
+	algorithm, err := key.algorithm()
+	if err != nil {
+		return err
+	}
+	signature, err := key.sign(signingString(headers, header, nil))
+	if err != nil {
+		return fmt.Errorf("unable to sign response with key %s. error: %w", key.ID, err)
+	}
+	header.Set(SignatureHeader, fmt.Sprintf(`keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		key.ID, algorithm, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// Transport - returns a round tripper verifying every response of base, responses failing verification are
+// returned as error. The body of a response is read into memory for verification, event streams are not verified
+// A nil base uses http.DefaultTransport
+func (verifier *Verifier) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &verifyingTransport{verifier: verifier, base: base}
+}
+
+// verifyingTransport - round tripper verifying responses
+type verifyingTransport struct {
+	verifier *Verifier
+	base     http.RoundTripper
+}
+
+// RoundTrip - sends the request and verifies the response
+func (transport *verifyingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := transport.base.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type")); mediaType == eventStreamType {
+		return response, nil
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err = transport.verifier.Verify(response.Header, body); err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return response, nil
+}
+
+// CloseIdleConnections - closes the idle connections of the base round tripper
+func (transport *verifyingTransport) CloseIdleConnections() {
+	if closer, ok := transport.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,154 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/credentials"
+	"accountlib/errors"
+)
+
+// TestVerifyResponse - tests signatures of responses are verified along with the digest of the body
+func TestVerifyResponse(t *testing.T) {
+	check := assert.New(t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	check.Nil(err)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	signer, err := NewSigner(Key{ID: "rsa-key", PrivateKey: rsaKey}, Key{ID: "ed-key", PrivateKey: edPrivate})
+	check.Nil(err)
+	verifier, err := NewVerifier(map[string]crypto.PublicKey{"rsa-key": &rsaKey.PublicKey, "ed-key": edPublic}, false)
+	check.Nil(err)
+	body := []byte(`{"data": {}}`)
+
+	for _, keyID := range []string{"rsa-key", "ed-key"} {
+		check.Nil(signer.UseKey(keyID))
+		header := http.Header{}
+		check.Nil(signer.SignResponse(header, body))
+		check.Nil(verifier.Verify(header, body))
+
+		err = verifier.Verify(header, []byte(`{"data": {"id": "x"}}`))
+		check.True(errors.Is(err, accounterrors.ErrBadSignature))
+		check.Equal("bad response signature of key "+keyID+": digest does not match the body", err.Error())
+
+		header.Set(DateHeader, "Thu, 01 Jan 1970 00:00:00 GMT")
+		err = verifier.Verify(header, body)
+		check.True(errors.Is(err, accounterrors.ErrBadSignature))
+		check.Contains(err.Error(), "signature does not verify")
+	}
+
+	header := http.Header{}
+	check.Nil(signer.SignResponse(header, body))
+	verifier.RemoveKey("ed-key")
+	var signatureError *accounterrors.SignatureError
+	check.True(errors.As(verifier.Verify(header, body), &signatureError))
+	check.Equal("ed-key", signatureError.KeyID)
+	check.Equal("unknown key", signatureError.Reason)
+
+	check.Nil(verifier.Verify(http.Header{}, body))
+	strict, err := NewVerifier(nil, true)
+	check.Nil(err)
+	check.True(errors.Is(strict.Verify(http.Header{}, body), accounterrors.ErrBadSignature))
+
+	_, err = NewVerifier(map[string]crypto.PublicKey{"rsa-key": rsaKey}, false)
+	check.NotNil(err)
+}
+
+// TestVerifyingTransport - tests responses are verified by the transport and event streams are passed through
+func TestVerifyingTransport(t *testing.T) {
+	check := assert.New(t)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	signer, err := NewSigner(Key{ID: "ed-key", PrivateKey: edPrivate})
+	check.Nil(err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/signed":
+			check.Nil(signer.SignResponse(w.Header(), []byte("signed")))
+			_, _ = w.Write([]byte("signed"))
+		case "/events":
+			w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+			_, _ = w.Write([]byte("data: {}\n\n"))
+		default:
+			_, _ = w.Write([]byte("unsigned"))
+		}
+	}))
+	defer server.Close()
+	verifier, err := NewVerifier(map[string]crypto.PublicKey{"ed-key": edPublic}, true)
+	check.Nil(err)
+	client := &http.Client{Transport: verifier.Transport(nil)}
+
+	response, err := client.Get(server.URL + "/signed")
+	check.Nil(err)
+	body, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	check.Equal("signed", string(body))
+
+	response, err = client.Get(server.URL + "/events")
+	check.Nil(err)
+	response.Body.Close()
+
+	_, err = client.Get(server.URL + "/unsigned")
+	check.True(errors.Is(err, accounterrors.ErrBadSignature))
+}
+
+// TestParsePublicKey - tests PEM encoded public keys are parsed
+func TestParsePublicKey(t *testing.T) {
+	check := assert.New(t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	check.Nil(err)
+	parsed, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)}))
+	check.Nil(err)
+	check.Equal(&rsaKey.PublicKey, parsed)
+
+	edPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	encoded, err := x509.MarshalPKIXPublicKey(edPublic)
+	check.Nil(err)
+	parsed, err = ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encoded}))
+	check.Nil(err)
+	check.Equal(edPublic, parsed)
+
+	_, err = ParsePublicKey([]byte("not a key"))
+	check.NotNil(err)
+}
+
+// TestLoadKeys - tests signing and public keys are loaded from credential stores
+func TestLoadKeys(t *testing.T) {
+	check := assert.New(t)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	check.Nil(err)
+	publicDER, err := x509.MarshalPKIXPublicKey(edPublic)
+	check.Nil(err)
+	store := credentials.StoreFunc(func(ctx context.Context, name string) ([]byte, error) {
+		switch name {
+		case "signing-key":
+			return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), nil
+		case "api-key":
+			return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), nil
+		}
+		return nil, credentials.ErrNotFound
+	})
+
+	key, err := LoadKey(context.Background(), store, "signing-key", "2021-q1")
+	check.Nil(err)
+	check.Equal(Key{ID: "2021-q1", PrivateKey: edPrivate}, key)
+	publicKey, err := LoadPublicKey(context.Background(), store, "api-key")
+	check.Nil(err)
+	check.Equal(edPublic, publicKey)
+
+	_, err = LoadKey(context.Background(), store, "missing", "2021-q1")
+	check.True(errors.Is(err, credentials.ErrNotFound))
+	_, err = LoadKey(context.Background(), store, "api-key", "2021-q1")
+	check.NotNil(err)
+}
@@ -0,0 +1,151 @@
+package accountlib
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// snapshot format written by Snapshot, the version is raised on incompatible changes of the format
+const (
+	SnapshotFormat  = "accountlib-snapshot"
+	SnapshotVersion = 1
+)
+
+// SnapshotHeader - describes a snapshot, it is the first record of the snapshot
+type SnapshotHeader struct {
+	Format         string    `json:"format"`
+	Version        int       `json:"version"`
+	OrganisationID string    `json:"organisation_id"`
+	CreatedOn      time.Time `json:"created_on"`
+	Anonymized     bool      `json:"anonymized,omitempty"`
+}
+
+// SnapshotTrailer - closes a snapshot with the number of accounts it holds, it is the last record of the snapshot
+// Snapshots without trailer are incomplete
+type SnapshotTrailer struct {
+	Count int64 `json:"count"`
+}
+
+// SnapshotOption - changes the way a snapshot is written
+type SnapshotOption func(options *snapshotOptions)
+
+// snapshotOptions - holds the options of a snapshot
+type snapshotOptions struct {
+	anonymizer *Anonymizer
+}
+
+// WithAnonymizer - anonymizes the accounts of the snapshot, marking the snapshot as anonymized in its header
+func WithAnonymizer(anonymizer *Anonymizer) SnapshotOption {
+	return func(options *snapshotOptions) {
+		options.anonymizer = anonymizer
+	}
+}
+
+// snapshotRecord - a line of a snapshot, holding the header, an account or the trailer
+type snapshotRecord struct {
+	Header  *SnapshotHeader  `json:"header,omitempty"`
+	Account *AccountData     `json:"account,omitempty"`
+	Trailer *SnapshotTrailer `json:"trailer,omitempty"`
+}
+
+// Snapshot - writes every account of the organisation, including its version, to w
+// A snapshot holds a json record per line, the header, an account per line sorted as listed by the api and the
+// trailer. Accounts changed while the snapshot is written may be captured in either state, pause writers for a
+// consistent point in time backup
+func (client *Client) Snapshot(ctx context.Context, organisationID string, w io.Writer, options ...SnapshotOption) error {
+	if err := validateID("organisation id", organisationID); err != nil {
+		return err
+	}
+	snapshotOptions := &snapshotOptions{}
+	for _, option := range options {
+		option(snapshotOptions)
+	}
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+
+	header := &SnapshotHeader{Format: SnapshotFormat, Version: SnapshotVersion, OrganisationID: organisationID, CreatedOn: time.Now().UTC(), Anonymized: snapshotOptions.anonymizer != nil}
+	if err := encoder.Encode(snapshotRecord{Header: header}); err != nil {
+		return fmt.Errorf("unable to write snapshot. error: %w", err)
+	}
+
+	iterator := client.Iterate(ctx, ListParams{Filter: map[string]string{organisationIDFilter: organisationID}})
+	defer iterator.Close()
+	count := int64(0)
+	for iterator.Next() {
+		account := iterator.Account()
+		if snapshotOptions.anonymizer != nil {
+			account = snapshotOptions.anonymizer.Anonymize(account)
+		}
+		if err := encoder.Encode(snapshotRecord{Account: account}); err != nil {
+			return fmt.Errorf("unable to write snapshot. error: %w", err)
+		}
+		count++
+	}
+	if err := iterator.Err(); err != nil {
+		return err
+	}
+
+	if err := encoder.Encode(snapshotRecord{Trailer: &SnapshotTrailer{Count: count}}); err != nil {
+		return fmt.Errorf("unable to write snapshot. error: %w", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("unable to write snapshot. error: %w", err)
+	}
+	return nil
+}
+
+// readSnapshot - reads the header and the accounts of a snapshot, failing for unknown formats and incomplete snapshots
+func readSnapshot(r io.Reader) (*SnapshotHeader, []AccountData, error) {
+	decoder := json.NewDecoder(r)
+	var header *SnapshotHeader
+	var trailer *SnapshotTrailer
+	var accounts []AccountData
+	for {
+		record := snapshotRecord{}
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid snapshot: %w", err)
+		}
+
+		switch {
+		case trailer != nil:
+			return nil, nil, errors.New("invalid snapshot: records after the trailer")
+		case header == nil:
+			if record.Header == nil {
+				return nil, nil, errors.New("invalid snapshot: missing header")
+			}
+			if record.Header.Format != SnapshotFormat {
+				return nil, nil, fmt.Errorf("invalid snapshot: unknown format %q", record.Header.Format)
+			}
+			if record.Header.Version < 1 || record.Header.Version > SnapshotVersion {
+				return nil, nil, fmt.Errorf("invalid snapshot: unsupported version %d", record.Header.Version)
+			}
+			header = record.Header
+		case record.Account != nil:
+			accounts = append(accounts, *record.Account)
+		case record.Trailer != nil:
+			trailer = record.Trailer
+		default:
+			return nil, nil, errors.New("invalid snapshot: empty record")
+		}
+	}
+
+	if header == nil {
+		return nil, nil, errors.New("invalid snapshot: missing header")
+	}
+	if trailer == nil {
+		return nil, nil, errors.New("invalid snapshot: missing trailer, the snapshot is incomplete")
+	}
+	if trailer.Count != int64(len(accounts)) {
+		return nil, nil, fmt.Errorf("invalid snapshot: holds %d accounts, the trailer counts %d", len(accounts), trailer.Count)
+	}
+	return header, accounts, nil
+}
@@ -0,0 +1,85 @@
+package accountlib_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/fakeserver"
+)
+
+// TestSnapshot - tests every account of the organisation is written with its version between the header and the trailer
+func TestSnapshot(t *testing.T) {
+	check := assert.New(t)
+	organisationID := "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	server := fakeserver.New()
+	defer server.Close()
+	for i := 0; i < 150; i++ {
+		version := int64(i % 3)
+		server.AddAccount(accountlib.AccountData{ID: fmt.Sprintf("%08d-0000-4000-8000-000000000000", i), OrganisationID: organisationID, Version: &version})
+	}
+	server.AddAccount(accountlib.AccountData{ID: "99999999-0000-4000-8000-000000000000", OrganisationID: "ee2fb143-6dfe-4787-b183-ca8ddd4164d2"})
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	buffer := &bytes.Buffer{}
+	check.Nil(client.Snapshot(context.Background(), organisationID, buffer))
+
+	var records []map[string]json.RawMessage
+	scanner := bufio.NewScanner(buffer)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		record := map[string]json.RawMessage{}
+		check.Nil(json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	check.Len(records, 152)
+
+	header := accountlib.SnapshotHeader{}
+	check.Nil(json.Unmarshal(records[0]["header"], &header))
+	check.Equal(accountlib.SnapshotFormat, header.Format)
+	check.Equal(accountlib.SnapshotVersion, header.Version)
+	check.Equal(organisationID, header.OrganisationID)
+	check.False(header.CreatedOn.IsZero())
+
+	account := accountlib.AccountData{}
+	check.Nil(json.Unmarshal(records[150]["account"], &account))
+	check.Equal("00000149-0000-4000-8000-000000000000", account.ID)
+	check.Equal(int64(2), *account.Version)
+
+	trailer := accountlib.SnapshotTrailer{}
+	check.Nil(json.Unmarshal(records[151]["trailer"], &trailer))
+	check.Equal(int64(150), trailer.Count)
+}
+
+// TestSnapshotInvalidOrganisationID - tests nothing is written for an invalid organisation id
+func TestSnapshotInvalidOrganisationID(t *testing.T) {
+	check := assert.New(t)
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: "http://localhost"})
+
+	buffer := &bytes.Buffer{}
+	check.NotNil(client.Snapshot(context.Background(), "invalid", buffer))
+	check.Equal(0, buffer.Len())
+}
+
+// TestSnapshotAnonymized - tests the accounts of an anonymized snapshot are anonymized
+func TestSnapshotAnonymized(t *testing.T) {
+	check := assert.New(t)
+	organisationID := "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	server := fakeserver.New()
+	defer server.Close()
+	server.AddAccount(accountlib.AccountData{ID: "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", OrganisationID: organisationID, Attributes: &accountlib.AccountAttributes{Name: []string{"Samantha Holder"}}})
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+
+	buffer := &bytes.Buffer{}
+	check.Nil(client.Snapshot(context.Background(), organisationID, buffer, accountlib.WithAnonymizer(accountlib.NewMaskingAnonymizer())))
+	check.Contains(buffer.String(), `"anonymized":true`)
+	check.Contains(buffer.String(), `"name":["***************"]`)
+	check.NotContains(buffer.String(), "Samantha")
+	check.Equal([]string{"Samantha Holder"}, server.Accounts()[0].Attributes.Name)
+}
@@ -0,0 +1,238 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"accountlib/errors"
+	"accountlib/httprequest"
+)
+
+// operation names used as keys of the stats and passed to the metrics
+const (
+	OperationFetch              = "fetch"
+	OperationCreate             = "create"
+	OperationUpdate             = "update"
+	OperationDelete             = "delete"
+	OperationList               = "list"
+	OperationHealth             = "health"
+	OperationCheckCompatibility = "check_compatibility"
+	OperationCreateSubscription = "create_subscription"
+	OperationListSubscriptions  = "list_subscriptions"
+	OperationDeleteSubscription = "delete_subscription"
+)
+
+// error classes counted in the stats
+const (
+	ErrorClassTimeout      = "timeout"
+	ErrorClassCanceled     = "canceled"
+	ErrorClassTransport    = "transport"
+	ErrorClassThrottled    = "throttled"
+	ErrorClassClient       = "client"
+	ErrorClassServer       = "server"
+	ErrorClassInvalid      = "invalid"
+	ErrorClassToken        = "token"
+	ErrorClassClockSkew    = "clock_skew"
+	ErrorClassBadSignature = "bad_signature"
+	ErrorClassPanic        = "panic"
+)
+
+// Metrics - receives the retries of the client, implement it for exporting them to a metrics system like prometheus
+// ObserveRetry is called before every retry with the status code of the failed attempt, 0 for transport errors,
+// and the back off before the retry
+type Metrics interface {
+	ObserveRetry(operation string, statusCode int, backOff time.Duration)
+}
+
+// Stats - snapshot of the cumulative counters of a client by operation, cheap enough for periodic logging
+type Stats struct {
+	Operations map[string]OperationStats
+}
+
+// OperationStats - cumulative counters of an operation
+// RetriedStatusCodes counts retries by the status code of the failed attempt, 0 counts transport errors
+// Errors counts failed requests by error class: timeout, canceled and transport errors, throttled, client and server
+// error status codes, invalid request specifications, token provider failures, clock skew, bad response signatures
+// and panics. CacheHits counts fetches answered by a concurrent fetch of the same account
+// BytesSent counts the request bodies of every attempt, BytesReceived the bodies of the final responses
+// Latency is the time spent on requests including retries, AverageLatency divides it by the requests
+type OperationStats struct {
+	Requests           int64
+	Retries            int64
+	RetriedStatusCodes map[int]int64
+	BackOff            time.Duration
+	Errors             map[string]int64
+	CacheHits          int64
+	BytesSent          int64
+	BytesReceived      int64
+	Latency            time.Duration
+}
+
+// AverageLatency - returns the average time spent on a request of the operation, zero without requests
+func (stats OperationStats) AverageLatency() time.Duration {
+	if stats.Requests == 0 {
+		return 0
+	}
+	return stats.Latency / time.Duration(stats.Requests)
+}
+
+// Total - returns the counters of all operations added up
+func (stats Stats) Total() OperationStats {
+	total := OperationStats{RetriedStatusCodes: make(map[int]int64), Errors: make(map[string]int64)}
+	for _, operationStats := range stats.Operations {
+		total.Requests += operationStats.Requests
+		total.Retries += operationStats.Retries
+		total.BackOff += operationStats.BackOff
+		total.CacheHits += operationStats.CacheHits
+		total.BytesSent += operationStats.BytesSent
+		total.BytesReceived += operationStats.BytesReceived
+		total.Latency += operationStats.Latency
+		for statusCode, count := range operationStats.RetriedStatusCodes {
+			total.RetriedStatusCodes[statusCode] += count
+		}
+		for class, count := range operationStats.Errors {
+			total.Errors[class] += count
+		}
+	}
+	return total
+}
+
+// clientStats - holds the counters of a client, it is shared by copies of the client
+type clientStats struct {
+	mutex      sync.Mutex
+	operations map[string]*OperationStats
+}
+
+// Stats - returns a snapshot of the counters of the client
+func (client *Client) Stats() Stats {
+	stats := Stats{Operations: make(map[string]OperationStats)}
+	if client.stats == nil {
+		return stats
+	}
+	client.stats.mutex.Lock()
+	defer client.stats.mutex.Unlock()
+	for operation, operationStats := range client.stats.operations {
+		snapshot := *operationStats
+		snapshot.RetriedStatusCodes = make(map[int]int64, len(operationStats.RetriedStatusCodes))
+		for statusCode, count := range operationStats.RetriedStatusCodes {
+			snapshot.RetriedStatusCodes[statusCode] = count
+		}
+		snapshot.Errors = make(map[string]int64, len(operationStats.Errors))
+		for class, count := range operationStats.Errors {
+			snapshot.Errors[class] = count
+		}
+		stats.Operations[operation] = snapshot
+	}
+	return stats
+}
+
+// PoolStats - returns the live statistics of the connection pool of the client, like idle connections, requests in
+// flight and the share of requests sent on reused connections
+func (client *Client) PoolStats() httprequest.PoolStats {
+	if handler, ok := client.handler.(interface{ PoolStats() httprequest.PoolStats }); ok {
+		return handler.PoolStats()
+	}
+	return httprequest.PoolStats{}
+}
+
+// recordAttempt - counts an attempt of an operation and passes retries on to the metrics
+func (client *Client) recordAttempt(operation string, attempt httprequest.Attempt) {
+	if client.stats != nil {
+		client.stats.record(operation, attempt)
+	}
+	if client.metrics != nil && attempt.BackOff > 0 {
+		client.metrics.ObserveRetry(operation, attempt.StatusCode, attempt.BackOff)
+	}
+}
+
+// recordRequest - counts a request of an operation along with its errors, bytes and latency, once per call however
+// often it was resent after 429 or 401. Requests failing before their first attempt only count their error
+func (client *Client) recordRequest(operation string, attempts int, bodySize int, statusCode int, responseSize int, err error, latency time.Duration) {
+	if client.stats == nil {
+		return
+	}
+	client.stats.mutex.Lock()
+	defer client.stats.mutex.Unlock()
+	operationStats := client.stats.operation(operation)
+	if class := errorClass(statusCode, err); class != "" {
+		operationStats.Errors[class]++
+	}
+	if attempts > 0 {
+		operationStats.Requests++
+		operationStats.BytesSent += int64(attempts * bodySize)
+		operationStats.BytesReceived += int64(responseSize)
+		operationStats.Latency += latency
+	}
+}
+
+// recordCacheHit - counts a request of an operation answered without sending it
+func (client *Client) recordCacheHit(operation string) {
+	if client.stats == nil {
+		return
+	}
+	client.stats.mutex.Lock()
+	defer client.stats.mutex.Unlock()
+	client.stats.operation(operation).CacheHits++
+}
+
+// errorClass - returns the class of the error or the error status code of a request, empty for successful requests
+func errorClass(statusCode int, err error) string {
+	if code, ok := accounterrors.StatusCode(err); ok {
+		statusCode = code
+	} else if err != nil {
+		var tokenErr *tokenError
+		switch {
+		case errors.Is(err, accounterrors.ErrInvalidSpecifications):
+			return ErrorClassInvalid
+		case errors.As(err, &tokenErr):
+			return ErrorClassToken
+		case errors.Is(err, accounterrors.ErrClockSkew):
+			return ErrorClassClockSkew
+		case errors.Is(err, accounterrors.ErrBadSignature):
+			return ErrorClassBadSignature
+		case errors.Is(err, accounterrors.ErrPanic):
+			return ErrorClassPanic
+		case errors.Is(err, context.Canceled):
+			return ErrorClassCanceled
+		case errors.Is(err, accounterrors.ErrTimeout) || errors.Is(err, context.DeadlineExceeded):
+			return ErrorClassTimeout
+		default:
+			return ErrorClassTransport
+		}
+	}
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorClassThrottled
+	case statusCode >= http.StatusInternalServerError:
+		return ErrorClassServer
+	case statusCode >= http.StatusBadRequest:
+		return ErrorClassClient
+	default:
+		return ""
+	}
+}
+
+// operation - returns the counters of an operation, creating them on first use, the mutex has to be held
+func (stats *clientStats) operation(operation string) *OperationStats {
+	operationStats, ok := stats.operations[operation]
+	if !ok {
+		operationStats = &OperationStats{RetriedStatusCodes: make(map[int]int64), Errors: make(map[string]int64)}
+		stats.operations[operation] = operationStats
+	}
+	return operationStats
+}
+
+// record - counts the retries of an operation, requests are counted once per call by recordRequest
+func (stats *clientStats) record(operation string, attempt httprequest.Attempt) {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+	operationStats := stats.operation(operation)
+	if attempt.BackOff > 0 {
+		operationStats.Retries++
+		operationStats.RetriedStatusCodes[attempt.StatusCode]++
+		operationStats.BackOff += attempt.BackOff
+	}
+}
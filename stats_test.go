@@ -0,0 +1,211 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// retry observation received by the metrics
+type observedRetry struct {
+	operation  string
+	statusCode int
+	backOff    time.Duration
+}
+
+// metricsRecorder - records the retries passed to the metrics
+type metricsRecorder struct {
+	mutex   sync.Mutex
+	retries []observedRetry
+}
+
+// ObserveRetry - records a retry
+func (recorder *metricsRecorder) ObserveRetry(operation string, statusCode int, backOff time.Duration) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.retries = append(recorder.retries, observedRetry{operation: operation, statusCode: statusCode, backOff: backOff})
+}
+
+// TestStats - tests requests, retries, retried status codes and back off are counted by operation
+func TestStats(t *testing.T) {
+	check := assert.New(t)
+	statusCodes := []int{http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusOK, http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCodes[0])
+		statusCodes = statusCodes[1:]
+		_, _ = w.Write([]byte(`{"data": [], "status": "up"}`))
+	}))
+	defer server.Close()
+	metrics := &metricsRecorder{}
+	client := NewClient(&ClientOptions{BaseURL: server.URL, Metrics: metrics})
+
+	_, err := client.List(context.Background(), ListParams{})
+	check.Nil(err)
+	_, err = client.List(context.Background(), ListParams{})
+	check.Nil(err)
+
+	stats := client.Stats()
+	listStats := stats.Operations[OperationList]
+	check.True(listStats.Latency >= 300*time.Millisecond)
+	check.Equal(listStats.Latency/2, listStats.AverageLatency())
+	listStats.Latency = 0
+	stats.Operations[OperationList] = listStats
+	check.Equal(Stats{Operations: map[string]OperationStats{
+		OperationList: {
+			Requests:           2,
+			Retries:            2,
+			RetriedStatusCodes: map[int]int64{http.StatusServiceUnavailable: 1, http.StatusGatewayTimeout: 1},
+			BackOff:            300 * time.Millisecond,
+			Errors:             map[string]int64{},
+			BytesReceived:      56,
+		},
+	}}, stats)
+	check.Equal([]observedRetry{
+		{operation: OperationList, statusCode: http.StatusServiceUnavailable, backOff: 100 * time.Millisecond},
+		{operation: OperationList, statusCode: http.StatusGatewayTimeout, backOff: 200 * time.Millisecond},
+	}, metrics.retries)
+}
+
+// TestStatsSnapshot - tests a snapshot is not changed by later requests
+func TestStatsSnapshot(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, RetryCount: 2})
+	check.Empty(client.Stats().Operations)
+
+	_, err := client.Health(context.Background())
+	check.NotNil(err)
+	snapshot := client.Stats()
+	_, _ = client.Health(context.Background())
+
+	check.Equal(int64(1), snapshot.Operations[OperationHealth].Requests)
+	check.Equal(map[int]int64{http.StatusServiceUnavailable: 1}, snapshot.Operations[OperationHealth].RetriedStatusCodes)
+	check.Equal(int64(2), client.Stats().Operations[OperationHealth].Retries)
+}
+
+// TestStatsCounters - tests errors by class, cache hits and bytes are counted and added up over operations
+func TestStatsCounters(t *testing.T) {
+	check := assert.New(t)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusConflict)
+		case r.URL.Query().Get("page[number]") == "1":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/v1/health":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			<-release
+			_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`))
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL, DisableRetries: true, DeduplicateFetches: true})
+
+	var fetches sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		fetches.Add(1)
+		go func() {
+			defer fetches.Done()
+			_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+			check.Nil(err)
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	fetches.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Fetch(ctx, "7eb322ba-57f6-465c-b600-79f26ac7fdc3", WithHeader("X-Trace", "1"))
+	check.NotNil(err)
+	_, err = client.Create(context.Background(), AccountCreateParams{ID: "7eb322ba-57f6-465c-b600-79f26ac7fdc3", OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c", Type: "accounts"})
+	check.NotNil(err)
+	_, _ = client.List(context.Background(), ListParams{PageNumber: 1})
+	_, _ = client.Health(context.Background())
+
+	stats := client.Stats()
+	fetchStats := stats.Operations[OperationFetch]
+	check.Equal(int64(2), fetchStats.Requests)
+	check.Equal(int64(2), fetchStats.CacheHits)
+	check.Equal(int64(len(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`)), fetchStats.BytesReceived)
+	check.Equal(map[string]int64{ErrorClassCanceled: 1}, fetchStats.Errors)
+	check.True(stats.Operations[OperationCreate].BytesSent > 0)
+
+	total := stats.Total()
+	check.Equal(map[string]int64{
+		ErrorClassCanceled:  1,
+		ErrorClassClient:    1,
+		ErrorClassThrottled: 1,
+		ErrorClassServer:    1,
+	}, total.Errors)
+	check.Equal(int64(2), total.CacheHits)
+	check.Equal(stats.Operations[OperationCreate].BytesSent, total.BytesSent)
+}
+
+// TestStatsResentRequests - tests requests resent after 429 and 401 are counted once and other errors get their own class
+func TestStatsResentRequests(t *testing.T) {
+	check := assert.New(t)
+	statusCodes := []int{http.StatusTooManyRequests, http.StatusUnauthorized, http.StatusNotFound}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCodes[0])
+		statusCodes = statusCodes[1:]
+	}))
+	defer server.Close()
+	tokens := 0
+	provider := NewCachingTokenProvider(func(ctx context.Context) (AccessToken, error) {
+		if tokens++; tokens > 2 {
+			return AccessToken{}, errors.New("token endpoint unavailable")
+		}
+		return AccessToken{Token: "token"}, nil
+	})
+	client := NewClient(&ClientOptions{BaseURL: server.URL, TokenProvider: provider})
+
+	_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.NotNil(err)
+	fetchStats := client.Stats().Operations[OperationFetch]
+	check.Equal(int64(1), fetchStats.Requests)
+	check.Equal(map[string]int64{ErrorClassClient: 1}, fetchStats.Errors)
+	check.Equal(fetchStats.Latency, fetchStats.AverageLatency())
+
+	provider.Invalidate("token")
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.NotNil(err)
+	fetchStats = client.Stats().Operations[OperationFetch]
+	check.Equal(int64(1), fetchStats.Requests)
+	check.Equal(map[string]int64{ErrorClassClient: 1, ErrorClassToken: 1}, fetchStats.Errors)
+}
+
+// TestPoolStats - tests the connections of the client are counted and reused
+func TestPoolStats(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+		check.Nil(err)
+	}
+	stats := client.PoolStats()
+	check.Equal(int64(1), stats.OpenConnections)
+	check.Equal(int64(1), stats.IdleConnections)
+	check.Equal(int64(0), stats.InFlightRequests)
+	check.Equal(int64(1), stats.NewConnections)
+	check.Equal(int64(2), stats.ReusedConnections)
+	check.InDelta(2.0/3.0, stats.ReuseRatio, 0.001)
+
+	client.httpClient.CloseIdleConnections()
+	check.Equal(int64(0), client.PoolStats().OpenConnections)
+}
@@ -0,0 +1,67 @@
+package accountlib
+
+import (
+	"fmt"
+	"strings"
+
+	"accountlib/redact"
+)
+
+// String - returns a one line summary of the account suitable for logs
+// Personal data such as names is left out, use json encoding where the complete account is required
+func (data *AccountData) String() string {
+	if data == nil {
+		return "AccountData<nil>"
+	}
+	fields := []string{"id=" + data.ID}
+	fields = appendField(fields, "organisation_id", data.OrganisationID)
+	if data.Attributes != nil {
+		fields = appendField(fields, "country", data.Attributes.countryValue())
+		fields = appendField(fields, "status", data.Attributes.statusValue())
+	}
+	if data.Version != nil {
+		fields = append(fields, fmt.Sprintf("version=%d", *data.Version))
+	}
+	return "AccountData{" + strings.Join(fields, ", ") + "}"
+}
+
+// String - returns a one line summary of the account attributes with sensitive values masked
+func (attributes *AccountAttributes) String() string {
+	if attributes == nil {
+		return "AccountAttributes<nil>"
+	}
+	var fields []string
+	fields = appendField(fields, "country", attributes.countryValue())
+	fields = appendField(fields, "status", attributes.statusValue())
+	if attributes.AccountClassification != nil {
+		fields = appendField(fields, "classification", string(*attributes.AccountClassification))
+	}
+	fields = appendField(fields, "bank_id_code", attributes.BankIDCode)
+	fields = appendField(fields, "account_number", redact.Mask(attributes.AccountNumber))
+	fields = appendField(fields, "iban", redact.Mask(attributes.Iban))
+	return "AccountAttributes{" + strings.Join(fields, ", ") + "}"
+}
+
+// appendField - appends a key=value pair when the value is set
+func appendField(fields []string, key, value string) []string {
+	if value == "" {
+		return fields
+	}
+	return append(fields, key+"="+value)
+}
+
+// countryValue - returns the country, empty when not set
+func (attributes *AccountAttributes) countryValue() string {
+	if attributes.Country == nil {
+		return ""
+	}
+	return string(*attributes.Country)
+}
+
+// statusValue - returns the status, empty when not set
+func (attributes *AccountAttributes) statusValue() string {
+	if attributes.Status == nil {
+		return ""
+	}
+	return string(*attributes.Status)
+}
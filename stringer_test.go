@@ -0,0 +1,50 @@
+package accountlib
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAccountDataString - tests the account summary leaves out personal data
+func TestAccountDataString(t *testing.T) {
+	check := assert.New(t)
+	country, status := CountryCode("GB"), AccountStatusConfirmed
+	version := int64(0)
+	data := &AccountData{
+		ID:             "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		OrganisationID: "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+		Version:        &version,
+		Attributes: &AccountAttributes{
+			Country: &country,
+			Status:  &status,
+			Name:    []string{"Samantha Holder"},
+		},
+	}
+
+	summary := fmt.Sprint(data)
+	check.Equal(summary, "AccountData{id=7eb322ba-57f6-465c-b600-79f26ac7fdc3, organisation_id=35eedc2c-0318-40dc-a090-d6f42e7b2754, "+
+		"country=GB, status=confirmed, version=0}")
+	check.NotContains(summary, "Samantha")
+}
+
+// TestAccountAttributesString - tests sensitive attributes are masked
+func TestAccountAttributesString(t *testing.T) {
+	check := assert.New(t)
+	attributes := &AccountAttributes{
+		BankIDCode:    "GBDSC",
+		AccountNumber: "41426819",
+		Iban:          "GB11NWBK40030041426819",
+		Name:          []string{"Samantha Holder"},
+	}
+
+	check.Equal(attributes.String(), "AccountAttributes{bank_id_code=GBDSC, account_number=****6819, iban=******************6819}")
+}
+
+// TestStringNil - tests nil values are printable
+func TestStringNil(t *testing.T) {
+	check := assert.New(t)
+	check.Equal((*AccountData)(nil).String(), "AccountData<nil>")
+	check.Equal((*AccountAttributes)(nil).String(), "AccountAttributes<nil>")
+}
@@ -0,0 +1,191 @@
+package accountlib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// subscription api constants
+const (
+	subscriptionPath = "subscriptions"
+	subscriptionType = "subscriptions"
+)
+
+// SubscriptionCreateParams - holds fields for subscription creation
+type SubscriptionCreateParams struct {
+	Attributes     *SubscriptionAttributes `json:"attributes,omitempty"`
+	ID             string                  `json:"id,omitempty"`
+	OrganisationID string                  `json:"organisation_id,omitempty"`
+	Type           string                  `json:"type,omitempty"`
+}
+
+// SubscriptionAttributes - holds the callback of a subscription and the event types delivered to it
+// EventTypes hold webhook event types like account.created, see the webhooks package
+type SubscriptionAttributes struct {
+	CallbackURI string   `json:"callback_uri,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+}
+
+// Subscription - holds complete subscription response
+type Subscription struct {
+	Attributes     *SubscriptionAttributes `json:"attributes,omitempty"`
+	ID             string                  `json:"id,omitempty"`
+	OrganisationID string                  `json:"organisation_id,omitempty"`
+	Type           string                  `json:"type,omitempty"`
+	Version        *int64                  `json:"version,omitempty"`
+}
+
+// SubscriptionList - holds a page of subscriptions
+type SubscriptionList struct {
+	Data []Subscription
+}
+
+// subscriptionEnvelope - holds the json:api envelope of a single subscription response
+type subscriptionEnvelope struct {
+	Data  *Subscription   `json:"data"`
+	Links json.RawMessage `json:"links,omitempty"`
+}
+
+// subscriptionListEnvelope - holds the json:api envelope of a subscription list response
+type subscriptionListEnvelope struct {
+	Data  []Subscription  `json:"data"`
+	Links json.RawMessage `json:"links,omitempty"`
+}
+
+// CreateSubscription - creates an event subscription delivering the given event types to the callback uri
+func (client *Client) CreateSubscription(ctx context.Context, createParams SubscriptionCreateParams, options ...CallOption) (subscription *Subscription, err error) {
+	// validate subscription id, organisation id and attributes
+	if err = validateID("subscription id", createParams.ID); err != nil {
+		return
+	}
+	if err = validateID("organisation id", createParams.OrganisationID); err != nil {
+		return
+	}
+	if err = createParams.Attributes.validate(); err != nil {
+		return
+	}
+	if createParams.Type == "" {
+		createParams.Type = subscriptionType
+	}
+
+	// marshal create params
+	params, err := json.Marshal(map[string]SubscriptionCreateParams{"data": createParams})
+	if err != nil {
+		err = fmt.Errorf("unable to marshal create params, error: %s", err.Error())
+		return
+	}
+
+	// prepare request specifications
+	requestSpecifications := client.requestSpecifications(ctx, OperationCreateSubscription, http.MethodPost, client.subscriptionsURL("", nil), params)
+	defer wrapError(&err, requestSpecifications, time.Now())
+
+	// make request
+	statusCode, response, _, err := client.do(requestSpecifications, options)
+	if err != nil {
+		return
+	}
+
+	// handle status code, response
+	if statusCode == http.StatusCreated {
+		dataResponse := subscriptionEnvelope{}
+		err = client.decodeResponse(response, &dataResponse)
+		if err != nil {
+			err = fmt.Errorf("resource created, but received invalid response. error: %s", err.Error())
+			return
+		}
+		return dataResponse.Data, nil
+	}
+	err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
+
+	return
+}
+
+// ListSubscriptions - returns a page of subscriptions based on list params
+func (client *Client) ListSubscriptions(ctx context.Context, listParams ListParams, options ...CallOption) (subscriptionList *SubscriptionList, err error) {
+	// prepare request specifications
+	requestSpecifications := client.requestSpecifications(ctx, OperationListSubscriptions, http.MethodGet, client.subscriptionsURL("", listParams.query()), nil)
+	defer wrapError(&err, requestSpecifications, time.Now())
+
+	// make request
+	statusCode, response, _, err := client.do(requestSpecifications, options)
+	if err != nil {
+		return
+	}
+
+	// handle status code, response
+	if statusCode == http.StatusOK {
+		dataResponse := subscriptionListEnvelope{}
+		err = client.decodeResponse(response, &dataResponse)
+		if err != nil {
+			err = fmt.Errorf("received invalid response. error: %s", err.Error())
+			return
+		}
+		return &SubscriptionList{Data: dataResponse.Data}, nil
+	}
+	err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
+
+	return
+}
+
+// DeleteSubscription - deletes a subscription based on subscription id and version
+func (client *Client) DeleteSubscription(ctx context.Context, subscriptionID string, version *int64, options ...CallOption) (err error) {
+	// validate subscription id, version
+	if err = validateID("subscription id", subscriptionID); err != nil {
+		return
+	}
+	if version == nil {
+		err = errors.New("invalid version")
+		return
+	}
+
+	// prepare request specifications
+	query := url.Values{"version": []string{strconv.FormatInt(*version, 10)}}
+	requestSpecifications := client.requestSpecifications(ctx, OperationDeleteSubscription, http.MethodDelete, client.subscriptionsURL(subscriptionID, query), nil)
+	defer wrapError(&err, requestSpecifications, time.Now())
+
+	// make request
+	statusCode, response, _, err := client.do(requestSpecifications, options)
+	if err != nil {
+		return
+	}
+
+	// handle status code, response
+	if statusCode != http.StatusNoContent {
+		err = client.statusErrors.HandleErrorStatusCode(statusCode, response)
+	}
+
+	return
+}
+
+// validate - checks the subscription has an absolute callback uri and at least one event type
+func (attributes *SubscriptionAttributes) validate() error {
+	if attributes == nil {
+		return errors.New("invalid subscription: attributes are required")
+	}
+	callback, err := url.Parse(attributes.CallbackURI)
+	if err != nil || !callback.IsAbs() || callback.Host == "" {
+		return fmt.Errorf("invalid subscription: callback uri %q is not an absolute url", attributes.CallbackURI)
+	}
+	if len(attributes.EventTypes) == 0 {
+		return errors.New("invalid subscription: at least one event type is required")
+	}
+	return nil
+}
+
+// subscriptionsURL - returns the url of the subscriptions collection, or of a single subscription when an id is given
+func (client *Client) subscriptionsURL(subscriptionID string, query url.Values) string {
+	requestURL := client.apiURL(subscriptionPath)
+	if subscriptionID != "" {
+		requestURL += "/" + url.PathEscape(subscriptionID)
+	}
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	return requestURL
+}
@@ -0,0 +1,97 @@
+package accountlib
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// subscription ids used by the subscription tests
+const (
+	subscriptionID           = "a1f3c2b4-2d4e-4f6a-8b9c-0d1e2f3a4b5c"
+	subscriptionOrganisation = "35eedc2c-0318-40dc-a090-d6f42e7b2754"
+)
+
+// validSubscription - returns valid subscription create params
+func validSubscription() SubscriptionCreateParams {
+	return SubscriptionCreateParams{
+		ID:             subscriptionID,
+		OrganisationID: subscriptionOrganisation,
+		Attributes:     &SubscriptionAttributes{CallbackURI: "https://hooks.internal/accounts", EventTypes: []string{"account.created"}},
+	}
+}
+
+// TestCreateSubscription - tests the create request and response of a subscription
+func TestCreateSubscription(t *testing.T) {
+	check := assert.New(t)
+	var requestBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		check.Equal("/v1/subscriptions", r.URL.Path)
+		requestBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data": {"id": "` + subscriptionID + `", "version": 0, "attributes": {"callback_uri": "https://hooks.internal/accounts"}}}`))
+	}))
+	defer server.Close()
+
+	subscription, err := NewClient(&ClientOptions{BaseURL: server.URL}).CreateSubscription(context.Background(), validSubscription())
+	check.Nil(err)
+	check.Equal(subscriptionID, subscription.ID)
+	check.Equal("https://hooks.internal/accounts", subscription.Attributes.CallbackURI)
+	check.JSONEq(`{"data": {"id": "`+subscriptionID+`", "organisation_id": "`+subscriptionOrganisation+`", "type": "subscriptions",
+		"attributes": {"callback_uri": "https://hooks.internal/accounts", "event_types": ["account.created"]}}}`, string(requestBody))
+}
+
+// TestCreateSubscriptionInvalid - tests invalid subscriptions are rejected before sending them
+func TestCreateSubscriptionInvalid(t *testing.T) {
+	check := assert.New(t)
+	client := NewClient(&ClientOptions{BaseURL: "http://127.0.0.1:1"})
+
+	params := validSubscription()
+	params.ID = "subscription"
+	_, err := client.CreateSubscription(context.Background(), params)
+	check.Contains(err.Error(), "invalid subscription id")
+
+	params = validSubscription()
+	params.Attributes = nil
+	_, err = client.CreateSubscription(context.Background(), params)
+	check.Contains(err.Error(), "attributes are required")
+
+	params = validSubscription()
+	params.Attributes.CallbackURI = "/accounts"
+	_, err = client.CreateSubscription(context.Background(), params)
+	check.Contains(err.Error(), `callback uri "/accounts" is not an absolute url`)
+
+	params = validSubscription()
+	params.Attributes.EventTypes = nil
+	_, err = client.CreateSubscription(context.Background(), params)
+	check.Contains(err.Error(), "at least one event type is required")
+}
+
+// TestSubscriptionErrors - tests error status codes and invalid responses of the subscription operations
+func TestSubscriptionErrors(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"data": [`))
+			return
+		}
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error_message": "invalid version"}`))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	_, err := client.CreateSubscription(context.Background(), validSubscription())
+	check.Contains(err.Error(), "request conflict")
+	_, err = client.ListSubscriptions(context.Background(), ListParams{})
+	check.Contains(err.Error(), "received invalid response")
+	version := int64(0)
+	err = client.DeleteSubscription(context.Background(), subscriptionID, &version)
+	check.Contains(err.Error(), "invalid version")
+	err = client.DeleteSubscription(context.Background(), subscriptionID, nil)
+	check.Equal("invalid version", err.Error())
+}
@@ -0,0 +1,139 @@
+package accountlib
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rate limit headers of the api and bounds of throttling
+const (
+	retryAfterHeader         = "Retry-After"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+	maxThrottledRetries      = 3
+	maxThrottlePause         = time.Minute
+	minRateDivisor           = 16
+	rateIncreaseDivisor      = 10
+)
+
+// throttle - paces the requests of a client by the rate limit signals of the api and the rate limit of the client
+// A 429 with Retry-After or a response without remaining requests pauses every request until the api accepts them
+// again. With a rate limit, requests are spaced evenly, the rate halves on every 429 and recovers on success
+type throttle struct {
+	mutex       sync.Mutex
+	limit       float64
+	rate        float64
+	pausedUntil time.Time
+	next        time.Time
+	now         func() time.Time
+}
+
+// newThrottle - returns a throttle, limit is the maximum number of requests per second, zero for no limit
+func newThrottle(limit float64) *throttle {
+	return &throttle{limit: limit, rate: limit, now: time.Now}
+}
+
+// wait - waits until the next request may be sent, returning early with the error of ctx
+func (throttle *throttle) wait(ctx context.Context) error {
+	throttle.mutex.Lock()
+	now := throttle.now()
+	start := now
+	if throttle.pausedUntil.After(start) {
+		start = throttle.pausedUntil
+	}
+	if throttle.rate > 0 {
+		if throttle.next.After(start) {
+			start = throttle.next
+		}
+		throttle.next = start.Add(time.Duration(float64(time.Second) / throttle.rate))
+	}
+	throttle.mutex.Unlock()
+
+	if !start.After(now) {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(start.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// observe - adapts the throttle to the rate limit signals of a response
+func (throttle *throttle) observe(statusCode int, headers http.Header) {
+	throttle.mutex.Lock()
+	defer throttle.mutex.Unlock()
+	now := throttle.now()
+
+	if statusCode == http.StatusTooManyRequests {
+		throttle.pause(now, parseRetryAfter(headers.Get(retryAfterHeader), now))
+		if throttle.limit > 0 {
+			throttle.rate = maxFloat(throttle.rate/2, throttle.limit/minRateDivisor)
+		}
+	} else if statusCode != 0 && throttle.limit > 0 {
+		throttle.rate = minFloat(throttle.rate+throttle.limit/rateIncreaseDivisor, throttle.limit)
+	}
+	if headers.Get(rateLimitRemainingHeader) == "0" {
+		throttle.pause(now, parseRateLimitReset(headers.Get(rateLimitResetHeader), now))
+	}
+}
+
+// pause - holds back requests until the time, bounded by the maximum pause
+func (throttle *throttle) pause(now, until time.Time) {
+	if limit := now.Add(maxThrottlePause); until.After(limit) {
+		until = limit
+	}
+	if until.After(throttle.pausedUntil) {
+		throttle.pausedUntil = until
+	}
+}
+
+// parseRetryAfter - returns the time of a Retry-After header holding seconds or an http date, now when it is invalid
+func parseRetryAfter(value string, now time.Time) time.Time {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date
+	}
+	return now
+}
+
+// parseRateLimitReset - returns the time of a rate limit reset header, holding seconds until the reset or the
+// unix time of the reset, now when it is invalid
+func parseRateLimitReset(value string, now time.Time) time.Time {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	switch {
+	case err != nil || seconds <= 0:
+		return now
+	case seconds > now.Unix()/2:
+		return time.Unix(seconds, 0)
+	default:
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+}
+
+// minFloat - returns the smaller value
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxFloat - returns the larger value
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
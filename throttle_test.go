@@ -0,0 +1,85 @@
+package accountlib
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestThrottlePause - tests 429s with Retry-After and exhausted rate limits pause the requests
+func TestThrottlePause(t *testing.T) {
+	check := assert.New(t)
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	throttle := newThrottle(0)
+	throttle.now = func() time.Time { return now }
+
+	throttle.observe(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"2"}})
+	check.Equal(now.Add(2*time.Second), throttle.pausedUntil)
+
+	throttle.observe(http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"0"}, "X-Ratelimit-Reset": []string{"5"}})
+	check.Equal(now.Add(5*time.Second), throttle.pausedUntil)
+
+	throttle.observe(http.StatusOK, http.Header{"X-Ratelimit-Remaining": []string{"0"}, "X-Ratelimit-Reset": []string{"1614600010"}})
+	check.True(now.Add(10 * time.Second).Equal(throttle.pausedUntil))
+
+	throttle.observe(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"Mon, 01 Mar 2021 13:00:00 GMT"}})
+	check.Equal(now.Add(maxThrottlePause), throttle.pausedUntil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	check.Equal(context.Canceled, throttle.wait(ctx))
+}
+
+// TestThrottleRate - tests requests are spaced by the rate, which halves on 429s and recovers on success
+func TestThrottleRate(t *testing.T) {
+	check := assert.New(t)
+	throttle := newThrottle(100)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		check.Nil(throttle.wait(context.Background()))
+	}
+	check.True(time.Since(start) >= 40*time.Millisecond)
+
+	throttle.observe(http.StatusTooManyRequests, http.Header{})
+	check.Equal(50.0, throttle.rate)
+	for i := 0; i < 10; i++ {
+		throttle.observe(http.StatusTooManyRequests, http.Header{})
+	}
+	check.Equal(100.0/minRateDivisor, throttle.rate)
+	for i := 0; i < 20; i++ {
+		throttle.observe(http.StatusOK, http.Header{})
+	}
+	check.Equal(100.0, throttle.rate)
+}
+
+// TestThrottledRequestsResent - tests requests answered with 429 are resent
+func TestThrottledRequestsResent(t *testing.T) {
+	check := assert.New(t)
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	account, err := client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+	check.Nil(err)
+	check.Equal("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", account.ID)
+	check.Equal(int32(3), atomic.LoadInt32(&requests))
+
+	atomic.StoreInt32(&requests, 0)
+	_, err = client.Fetch(context.Background(), "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", WithRetries(0))
+	check.Contains(err.Error(), "too many requests")
+	check.Equal(int32(1), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,98 @@
+package accountlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// timestampLayouts - layouts of the timestamps of the api, tried in order, timestamps without zone are read as utc
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// accountDataFields - json field names modelled by AccountData
+var accountDataFields = jsonFieldNames(reflect.TypeOf(AccountData{}))
+
+// tolerantTime - timestamp decoded with any of the timestamp layouts of the api
+type tolerantTime time.Time
+
+// UnmarshalJSON - decodes a timestamp string, failing for timestamps in unknown layouts
+func (timestamp *tolerantTime) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid timestamp %s", string(data))
+	}
+	parsed, err := parseTimestamp(value)
+	if err != nil {
+		return err
+	}
+	*timestamp = tolerantTime(parsed)
+	return nil
+}
+
+// parseTimestamp - parses a timestamp of the api, accepting rfc3339 with or without fractional seconds and zone,
+// with a space instead of the T and plain dates
+func parseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// timePointer - returns the time of a decoded timestamp, nil when it is unset
+func (timestamp *tolerantTime) timePointer() *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	value := time.Time(*timestamp)
+	return &value
+}
+
+// UnmarshalJSON - decodes an account, reading its timestamps tolerantly
+// Fields unknown to this library are remembered for strict decoding
+func (data *AccountData) UnmarshalJSON(raw []byte) error {
+	type accountData AccountData
+	var decoded struct {
+		accountData
+		CreatedOn  *tolerantTime `json:"created_on"`
+		ModifiedOn *tolerantTime `json:"modified_on"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return err
+	}
+	extra, err := extraFields(raw, accountDataFields)
+	if err != nil {
+		return err
+	}
+	*data = AccountData(decoded.accountData)
+	data.CreatedOn = decoded.CreatedOn.timePointer()
+	data.ModifiedOn = decoded.ModifiedOn.timePointer()
+	data.unknown = nil
+	for name := range extra {
+		data.unknown = append(data.unknown, name)
+	}
+	sort.Strings(data.unknown)
+	return nil
+}
+
+// unknownFields - returns an error if the account holds fields or attributes unknown to this library
+func (data *AccountData) unknownFields() error {
+	if len(data.unknown) > 0 {
+		return fmt.Errorf("json: unknown field %q", data.unknown[0])
+	}
+	if data.Attributes == nil {
+		return nil
+	}
+	return unknownAttributesError(data.Attributes.Extra)
+}
@@ -0,0 +1,57 @@
+package accountlib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDecodeAccountTimestamps - tests the timestamps of accounts are decoded from the layouts used by the api
+func TestDecodeAccountTimestamps(t *testing.T) {
+	check := assert.New(t)
+	for name, testCase := range map[string]struct {
+		timestamp string
+		want      time.Time
+	}{
+		"rfc3339":        {"2021-03-04T10:11:12Z", time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)},
+		"fraction":       {"2021-03-04T10:11:12.345Z", time.Date(2021, 3, 4, 10, 11, 12, 345000000, time.UTC)},
+		"offset":         {"2021-03-04T11:11:12+01:00", time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)},
+		"without zone":   {"2021-03-04T10:11:12.345", time.Date(2021, 3, 4, 10, 11, 12, 345000000, time.UTC)},
+		"space":          {"2021-03-04 10:11:12Z", time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)},
+		"space no zone":  {"2021-03-04 10:11:12", time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)},
+		"date":           {"2021-03-04", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+		"surrounding ws": {" 2021-03-04T10:11:12Z ", time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)},
+	} {
+		account, err := DecodeAccount([]byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "created_on": "` + testCase.timestamp + `", "modified_on": "` + testCase.timestamp + `"}}`))
+		check.Nil(err, name)
+		check.True(testCase.want.Equal(*account.CreatedOn), name)
+		check.True(testCase.want.Equal(*account.ModifiedOn), name)
+	}
+
+	account, err := DecodeAccount([]byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "created_on": null}}`))
+	check.Nil(err)
+	check.Nil(account.CreatedOn)
+	check.Nil(account.ModifiedOn)
+
+	_, err = DecodeAccount([]byte(`{"data": {"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", "created_on": "yesterday"}}`))
+	check.EqualError(err, `invalid timestamp "yesterday"`)
+}
+
+// TestAccountTimestampsDiffAndClone - tests timestamps are compared by instant and copied by Clone
+func TestAccountTimestampsDiffAndClone(t *testing.T) {
+	check := assert.New(t)
+	modifiedOn := time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)
+	account := &AccountData{ID: "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", ModifiedOn: &modifiedOn}
+	clone := account.Clone()
+	check.True(account.Equal(clone))
+
+	inZone := modifiedOn.In(time.FixedZone("CET", 3600))
+	clone.ModifiedOn = &inZone
+	check.True(account.Equal(clone))
+
+	later := modifiedOn.Add(time.Second)
+	clone.ModifiedOn = &later
+	check.Equal([]FieldChange{{Path: "modified_on", Old: modifiedOn, New: later}}, Diff(account, clone))
+	check.Equal(modifiedOn, *account.ModifiedOn)
+}
@@ -0,0 +1,80 @@
+package accountlib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// tokenCacheMode - permissions of token cache files, readable by the owner only
+const tokenCacheMode = 0600
+
+// TokenCache - persists the token of a caching token provider across processes, so short lived processes like cli
+// invocations reuse a valid token instead of fetching a new one each. Load returns a zero token when none is stored,
+// storing a zero token removes the stored one. Implement it for keychains or shared caches
+type TokenCache interface {
+	Load(ctx context.Context) (AccessToken, error)
+	Store(ctx context.Context, token AccessToken) error
+}
+
+// FileTokenCache - token cache storing the token as json in a file readable by the owner only
+type FileTokenCache struct {
+	Path string
+}
+
+// NewFileTokenCache - returns a token cache storing the token in the file
+func NewFileTokenCache(path string) *FileTokenCache {
+	return &FileTokenCache{Path: path}
+}
+
+// Load - returns the token of the file, a missing file holds no token
+func (cache *FileTokenCache) Load(_ context.Context) (AccessToken, error) {
+	var token AccessToken
+	content, err := ioutil.ReadFile(cache.Path)
+	if os.IsNotExist(err) {
+		return token, nil
+	}
+	if err != nil {
+		return token, fmt.Errorf("unable to read token cache. error: %w", err)
+	}
+	if err = json.Unmarshal(content, &token); err != nil {
+		return AccessToken{}, fmt.Errorf("invalid token cache %s. error: %w", cache.Path, err)
+	}
+	return token, nil
+}
+
+// Store - writes the token to a temporary file readable by the owner only and renames it to the file, so concurrent
+// processes never read a partial token. A zero token removes the file
+func (cache *FileTokenCache) Store(_ context.Context, token AccessToken) error {
+	if token.Token == "" {
+		if err := os.Remove(cache.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to clear token cache. error: %w", err)
+		}
+		return nil
+	}
+	content, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(cache.Path), filepath.Base(cache.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to write token cache. error: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if err = file.Chmod(tokenCacheMode); err == nil {
+		_, err = file.Write(content)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), cache.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write token cache. error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package accountlib
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFileTokenCache - tests tokens are stored in a file readable by the owner only and cleared with a zero token
+func TestFileTokenCache(t *testing.T) {
+	check := assert.New(t)
+	dir, err := ioutil.TempDir("", "tokencache")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	cache := NewFileTokenCache(filepath.Join(dir, "token.json"))
+
+	token, err := cache.Load(context.Background())
+	check.Nil(err)
+	check.Equal(AccessToken{}, token)
+
+	expiry := time.Date(2021, 3, 4, 10, 11, 12, 0, time.UTC)
+	check.Nil(cache.Store(context.Background(), AccessToken{Token: "secret", Expiry: expiry}))
+	info, err := os.Stat(cache.Path)
+	check.Nil(err)
+	check.Equal(os.FileMode(0600), info.Mode().Perm())
+	token, err = cache.Load(context.Background())
+	check.Nil(err)
+	check.Equal("secret", token.Token)
+	check.True(expiry.Equal(token.Expiry))
+
+	check.Nil(cache.Store(context.Background(), AccessToken{}))
+	_, err = os.Stat(cache.Path)
+	check.True(os.IsNotExist(err))
+	check.Nil(cache.Store(context.Background(), AccessToken{}))
+
+	check.Nil(ioutil.WriteFile(cache.Path, []byte("not json"), 0600))
+	_, err = cache.Load(context.Background())
+	check.NotNil(err)
+	files, _ := ioutil.ReadDir(dir)
+	check.Len(files, 1)
+}
+
+// TestPersistentTokenProvider - tests valid tokens of the cache are reused across providers and rejected tokens are cleared
+func TestPersistentTokenProvider(t *testing.T) {
+	check := assert.New(t)
+	dir, err := ioutil.TempDir("", "tokencache")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	cache := NewFileTokenCache(filepath.Join(dir, "token.json"))
+	fetched := 0
+	source := func(ctx context.Context) (AccessToken, error) {
+		fetched++
+		return AccessToken{Token: "token-" + string(rune('0'+fetched)), Expiry: time.Now().Add(time.Hour)}, nil
+	}
+
+	token, err := NewPersistentTokenProvider(source, cache).Token(context.Background())
+	check.Nil(err)
+	check.Equal("token-1", token)
+
+	// a new provider, like of the next cli invocation, reuses the persisted token
+	provider := NewPersistentTokenProvider(source, cache)
+	token, err = provider.Token(context.Background())
+	check.Nil(err)
+	check.Equal("token-1", token)
+	check.Equal(1, fetched)
+
+	provider.Invalidate("token-1")
+	stored, err := cache.Load(context.Background())
+	check.Nil(err)
+	check.Equal(AccessToken{}, stored)
+	token, err = provider.Token(context.Background())
+	check.Nil(err)
+	check.Equal("token-2", token)
+
+	// expired tokens of the cache and unreadable caches are replaced by a fresh token
+	check.Nil(cache.Store(context.Background(), AccessToken{Token: "expired", Expiry: time.Now().Add(time.Second)}))
+	token, err = NewPersistentTokenProvider(source, cache).Token(context.Background())
+	check.Nil(err)
+	check.Equal("token-3", token)
+	check.Nil(ioutil.WriteFile(cache.Path, []byte("not json"), 0600))
+	token, err = NewPersistentTokenProvider(source, cache).Token(context.Background())
+	check.Nil(err)
+	check.Equal("token-4", token)
+	stored, err = cache.Load(context.Background())
+	check.Nil(err)
+	check.Equal("token-4", stored.Token)
+}
@@ -0,0 +1,49 @@
+package accountlib
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// unix socket base urls, requests to them are sent to the placeholder host over the socket
+const (
+	unixSocketScheme = "unix://"
+	unixSocketHost   = "http://unix"
+)
+
+// unixSocketPath - returns the path of the socket of a unix socket base url like unix:///var/run/accounts.sock
+func unixSocketPath(baseURL string) (string, bool) {
+	if !strings.HasPrefix(baseURL, unixSocketScheme) {
+		return "", false
+	}
+	return strings.TrimPrefix(baseURL, unixSocketScheme), true
+}
+
+// unixSocketClient - returns a copy of the http client dialing the socket with the dialer of its transport for every
+// request, proxies are not used
+// The transport is cloned, custom round trippers which are not an *http.Transport are kept and have to dial the socket
+func unixSocketClient(httpClient *http.Client, socketPath string) *http.Client {
+	socketClient := *httpClient
+	var transport *http.Transport
+	switch base := httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = base.Clone()
+	default:
+		return &socketClient
+	}
+	// the socket is dialed with the dialer of the transport, which counts the connections of the pool statistics
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dial(ctx, "unix", socketPath)
+	}
+	socketClient.Transport = transport
+	return &socketClient
+}
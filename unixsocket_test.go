@@ -0,0 +1,49 @@
+package accountlib
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnixSocket - tests requests of unix socket base urls are sent over the socket and their connections counted
+func TestUnixSocket(t *testing.T) {
+	check := assert.New(t)
+	dir, err := ioutil.TempDir("", "accountlib")
+	check.Nil(err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "accounts.sock")
+	listener, err := net.Listen("unix", socketPath)
+	check.Nil(err)
+	var path string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, `{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "version": 0}}`)
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{BaseURL: "unix://" + socketPath})
+	account, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", account.ID)
+	check.Equal("/v1/organisation/accounts/7eb322ba-57f6-465c-b600-79f26ac7fdc3", path)
+	// the connections over the socket are counted in the pool statistics
+	check.Equal(int64(1), client.PoolStats().OpenConnections)
+
+	// custom http clients are copied, their transport is left unchanged
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	client = NewClient(&ClientOptions{BaseURL: "unix://" + socketPath, HTTPClient: httpClient})
+	_, err = client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Nil(httpClient.Transport.(*http.Transport).DialContext)
+}
@@ -0,0 +1,39 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// UpdateWith - fetches an account, applies the mutation to it and updates the account with the mutated attributes
+// When the account was changed in between and the update fails with a version conflict, the account is fetched
+// again and the mutation applied to the fresh copy once more. Errors of the mutation abort the update unchanged,
+// the mutation may therefore run twice and must not have side effects. Call options apply to every request
+func (client *Client) UpdateWith(ctx context.Context, accountID string, mutate func(account *AccountData) error, options ...CallOption) (*AccountData, error) {
+	if mutate == nil {
+		return nil, errors.New("invalid mutation: must not be nil")
+	}
+	for attempt := 1; ; attempt++ {
+		current, err := client.Fetch(ctx, accountID, options...)
+		if err != nil {
+			return nil, err
+		}
+		mutated := current.Clone()
+		if err = mutate(mutated); err != nil {
+			return nil, err
+		}
+
+		updated, statusCode, err := client.update(ctx, AccountUpdateParams{
+			Attributes:     mutated.Attributes.CreateAttributes(),
+			ID:             current.ID,
+			OrganisationID: current.OrganisationID,
+			Type:           current.Type,
+			Version:        current.Version,
+		}, options)
+		if statusCode == http.StatusConflict && attempt < 2 {
+			continue
+		}
+		return updated, err
+	}
+}
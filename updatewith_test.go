@@ -0,0 +1,74 @@
+package accountlib_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/accountlibtest"
+	"accountlib/fakeserver"
+)
+
+// TestUpdateWith - tests the mutation is applied to the fetched account and retried once after a version conflict
+func TestUpdateWith(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	params := accountlibtest.ValidGBAccount().CreateParams()
+	_, err := client.Create(context.Background(), params)
+	check.Nil(err)
+
+	// a concurrent update during the first mutation makes the first update fail with a version conflict
+	mutations := 0
+	updated, err := client.UpdateWith(context.Background(), params.ID, func(account *accountlib.AccountData) error {
+		mutations++
+		if mutations == 1 {
+			concurrent := account.Clone()
+			concurrent.Attributes.Bic = "NWBKGB42"
+			_, err := client.Update(context.Background(), accountlib.AccountUpdateParams{
+				ID: account.ID, Version: account.Version, Attributes: concurrent.Attributes.CreateAttributes(),
+			})
+			check.Nil(err)
+		}
+		account.Attributes.Name = []string{"Jane Doe"}
+		return nil
+	})
+	check.Nil(err)
+	check.Equal(2, mutations)
+	check.Equal(int64(2), *updated.Version)
+	check.Equal([]string{"Jane Doe"}, updated.Attributes.Name)
+	check.Equal("NWBKGB42", updated.Attributes.Bic)
+}
+
+// TestUpdateWithConflicts - tests a second version conflict and errors of the mutation are returned
+func TestUpdateWithConflicts(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL})
+	params := accountlibtest.ValidGBAccount().CreateParams()
+	_, err := client.Create(context.Background(), params)
+	check.Nil(err)
+
+	mutations := 0
+	_, err = client.UpdateWith(context.Background(), params.ID, func(account *accountlib.AccountData) error {
+		mutations++
+		_, err := client.Update(context.Background(), accountlib.AccountUpdateParams{
+			ID: account.ID, Version: account.Version, Attributes: account.Attributes.CreateAttributes(),
+		})
+		check.Nil(err)
+		return nil
+	})
+	check.Contains(err.Error(), "request conflict")
+	check.Equal(2, mutations)
+
+	aborted := errors.New("aborted")
+	_, err = client.UpdateWith(context.Background(), params.ID, func(*accountlib.AccountData) error { return aborted })
+	check.Equal(aborted, err)
+	_, err = client.UpdateWith(context.Background(), params.ID, nil)
+	check.EqualError(err, "invalid mutation: must not be nil")
+}
@@ -0,0 +1,78 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// minimum iban length, country code, check digits and at least one character of account identification
+const minIBANLength = 5
+
+// ibanLengths - iban lengths of the countries in the iban registry
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BR": 29,
+	"BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DK": 18, "DO": 28, "EE": 20, "EG": 29,
+	"ES": 24, "FI": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28,
+	"HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24, "ME": 22, "MK": 19,
+	"MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29,
+	"RO": 24, "RS": 22, "SA": 24, "SC": 31, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+// iban validation errors
+var (
+	ErrIBANLength     = errors.New("invalid iban length")
+	ErrIBANCharacters = errors.New("invalid iban characters")
+	ErrIBANCountry    = errors.New("unsupported iban country")
+	ErrIBANChecksum   = errors.New("invalid iban checksum")
+)
+
+// IBAN - validates the structure and the mod-97 checksum of an iban
+// Spaces used for printing ibans in groups are ignored, letters must be upper case
+func IBAN(iban string) error {
+	iban = strings.Replace(iban, " ", "", -1)
+	if len(iban) < minIBANLength {
+		return ErrIBANLength
+	}
+	for i, character := range iban {
+		isLetter := character >= 'A' && character <= 'Z'
+		isDigit := character >= '0' && character <= '9'
+		if (i < 2 && !isLetter) || (i >= 2 && i < 4 && !isDigit) || (!isLetter && !isDigit) {
+			return ErrIBANCharacters
+		}
+	}
+	length, ok := ibanLengths[iban[:2]]
+	if !ok {
+		return ErrIBANCountry
+	}
+	if len(iban) != length {
+		return fmt.Errorf("%w: %s ibans have %d characters", ErrIBANLength, iban[:2], length)
+	}
+	if ibanChecksum(iban) != 1 {
+		return ErrIBANChecksum
+	}
+	return nil
+}
+
+// ibanChecksum - returns the iban modulo 97, after moving the first four characters to the end
+// and replacing letters with two digit numbers
+func ibanChecksum(iban string) int {
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, character := range rearranged {
+		if character >= 'A' && character <= 'Z' {
+			remainder = (remainder*100 + int(character-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(character-'0')) % 97
+		}
+	}
+	return remainder
+}
+
+// IBANCheckDigits - returns the two check digits of an iban with the country code and the basic bank account number
+// The iban is the country code, the check digits and the basic bank account number, which must be upper case
+func IBANCheckDigits(countryCode, bban string) string {
+	return fmt.Sprintf("%02d", 98-ibanChecksum(countryCode+"00"+bban))
+}
@@ -0,0 +1,40 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIBANValid - tests valid ibans, with and without print grouping
+func TestIBANValid(t *testing.T) {
+	check := assert.New(t)
+	check.Equal(IBAN("GB29NWBK60161331926819"), nil)
+	check.Equal(IBAN("DE89 3704 0044 0532 0130 00"), nil)
+	check.Equal(IBAN("NO9386011117947"), nil)
+}
+
+// TestIBANInvalidChecksum - tests an iban with a wrong check digit
+func TestIBANInvalidChecksum(t *testing.T) {
+	check := assert.New(t)
+	check.Equal(IBAN("GB28NWBK60161331926819"), ErrIBANChecksum)
+}
+
+// TestIBANInvalidStructure - tests ibans with malformed structure
+func TestIBANInvalidStructure(t *testing.T) {
+	check := assert.New(t)
+	check.Equal(IBAN("GB"), ErrIBANLength)
+	check.Equal(IBAN("gb29NWBK60161331926819"), ErrIBANCharacters)
+	check.Equal(IBAN("GB29NWBK6016133192681-"), ErrIBANCharacters)
+	check.Equal(IBAN("ZZ29NWBK60161331926819"), ErrIBANCountry)
+	check.True(errors.Is(IBAN("GB29NWBK6016133192681"), ErrIBANLength))
+}
+
+// TestIBANCheckDigits - tests the check digits of ibans are computed
+func TestIBANCheckDigits(t *testing.T) {
+	check := assert.New(t)
+	check.Equal(IBANCheckDigits("GB", "NWBK60161331926819"), "29")
+	check.Equal(IBANCheckDigits("DE", "370400440532013000"), "89")
+	check.Nil(IBAN("GB" + IBANCheckDigits("GB", "ABCD12345678901234") + "ABCD12345678901234"))
+}
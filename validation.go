@@ -0,0 +1,122 @@
+package accountlib
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"accountlib/errors"
+	"accountlib/validate"
+)
+
+// account field limits enforced by the api
+const (
+	maxNames            = 4
+	maxAlternativeNames = 3
+	maxNameLength       = 140
+)
+
+// Validate - checks the create params locally, including the country rules documented by the api,
+// returning all problems found in a single error
+func (params *AccountCreateParams) Validate() error {
+	return params.ValidateWithRules(defaultRulesEngine)
+}
+
+// ValidateWithRules - checks the create params locally using the country rules of the given engine
+// The returned error is a *accounterrors.ValidationError holding every field error
+func (params *AccountCreateParams) ValidateWithRules(engine *RulesEngine) error {
+	var fieldErrors []accounterrors.FieldError
+	fieldErrors = append(fieldErrors, checkUUID("id", params.ID)...)
+	fieldErrors = append(fieldErrors, checkUUID("organisation_id", params.OrganisationID)...)
+	if params.Relationships != nil && params.Relationships.MasterAccount != nil {
+		fieldErrors = append(fieldErrors, checkMasterAccount(params.ID, params.Relationships.MasterAccount)...)
+	}
+
+	attributes := params.Attributes
+	if attributes == nil {
+		fieldErrors = append(fieldErrors, fieldError("attributes", accounterrors.CodeRequired, "are required"))
+		return validationError(fieldErrors)
+	}
+	if attributes.Country == nil {
+		fieldErrors = append(fieldErrors, fieldError("attributes.country", accounterrors.CodeRequired, "is required"))
+	} else if !attributes.Country.Valid() {
+		fieldErrors = append(fieldErrors, fieldError("attributes.country", accounterrors.CodeInvalid, fmt.Sprintf("%q is not an ISO 3166-1 alpha-2 code", *attributes.Country)))
+	}
+	if attributes.BaseCurrency != "" && !attributes.BaseCurrency.Valid() {
+		fieldErrors = append(fieldErrors, fieldError("attributes.base_currency", accounterrors.CodeInvalid, fmt.Sprintf("%q is not an ISO 4217 code", attributes.BaseCurrency)))
+	}
+	if attributes.AccountClassification != nil && !attributes.AccountClassification.Valid() {
+		fieldErrors = append(fieldErrors, fieldError("attributes.account_classification", accounterrors.CodeUnsupported, fmt.Sprintf("%q is not supported", *attributes.AccountClassification)))
+	}
+	fieldErrors = append(fieldErrors, checkNames("attributes.name", attributes.Name, maxNames)...)
+	fieldErrors = append(fieldErrors, checkNames("attributes.alternative_names", attributes.AlternativeNames, maxAlternativeNames)...)
+	if attributes.Iban != "" {
+		if err := validate.IBAN(attributes.Iban); err != nil {
+			fieldErrors = append(fieldErrors, fieldError("attributes.iban", accounterrors.CodeInvalid, fmt.Sprintf("is not valid: %s", err.Error())))
+		}
+	}
+	if utf8.RuneCountInString(attributes.SecondaryIdentification) > maxNameLength {
+		fieldErrors = append(fieldErrors, fieldError("attributes.secondary_identification", accounterrors.CodeTooLong, fmt.Sprintf("exceeds %d characters", maxNameLength)))
+	}
+
+	// bank id and bank id code are only meaningful together
+	if attributes.BankID != "" && attributes.BankIDCode == "" {
+		fieldErrors = append(fieldErrors, fieldError("attributes.bank_id_code", accounterrors.CodeRequired, "is required when attributes.bank_id is set"))
+	}
+	if attributes.BankIDCode != "" && attributes.BankID == "" {
+		fieldErrors = append(fieldErrors, fieldError("attributes.bank_id", accounterrors.CodeRequired, "is required when attributes.bank_id_code is set"))
+	}
+	fieldErrors = append(fieldErrors, engine.Check(params)...)
+
+	return validationError(fieldErrors)
+}
+
+// fieldError - returns the field error of a field in the data of the create params
+func fieldError(field, code, message string) accounterrors.FieldError {
+	return accounterrors.FieldError{Field: field, Message: message, Pointer: accounterrors.FieldPointer(field), Code: code}
+}
+
+// checkUUID - checks a required uuid field
+func checkUUID(field, value string) []accounterrors.FieldError {
+	if value == "" {
+		return []accounterrors.FieldError{fieldError(field, accounterrors.CodeRequired, "is required")}
+	}
+	if !canonicalUUID(value) {
+		return []accounterrors.FieldError{fieldError(field, accounterrors.CodeInvalid, fmt.Sprintf("%q is not a valid uuid", value))}
+	}
+	return nil
+}
+
+// checkMasterAccount - checks the relationship of a virtual account to a single other account
+func checkMasterAccount(accountID string, relationship *Relationship) []accounterrors.FieldError {
+	if len(relationship.Data) != 1 {
+		return []accounterrors.FieldError{fieldError("relationships.master_account", accounterrors.CodeInvalid, "requires exactly one account")}
+	}
+	identifier := relationship.Data[0]
+	fieldErrors := checkUUID("relationships.master_account.id", identifier.ID)
+	if identifier.Type != defaultAccountType {
+		fieldErrors = append(fieldErrors, fieldError("relationships.master_account.type", accounterrors.CodeInvalid, fmt.Sprintf("%q is not %q", identifier.Type, defaultAccountType)))
+	}
+	if identifier.ID != "" && identifier.ID == accountID {
+		fieldErrors = append(fieldErrors, fieldError("relationships.master_account", accounterrors.CodeInvalid, "must not be the account itself"))
+	}
+	return fieldErrors
+}
+
+// checkNames - checks the number of names and the length of each name
+func checkNames(field string, names []string, maxCount int) []accounterrors.FieldError {
+	var fieldErrors []accounterrors.FieldError
+	if len(names) > maxCount {
+		fieldErrors = append(fieldErrors, fieldError(field, accounterrors.CodeTooMany, fmt.Sprintf("allows at most %d entries", maxCount)))
+	}
+	for i, name := range names {
+		if utf8.RuneCountInString(name) > maxNameLength {
+			fieldErrors = append(fieldErrors, fieldError(fmt.Sprintf("%s[%d]", field, i), accounterrors.CodeTooLong, fmt.Sprintf("exceeds %d characters", maxNameLength)))
+		}
+	}
+	return fieldErrors
+}
+
+// validationError - combines field errors into a validation error, nil if there are none
+func validationError(fieldErrors []accounterrors.FieldError) error {
+	return accounterrors.NewValidationError("invalid create params", fieldErrors)
+}
@@ -0,0 +1,90 @@
+package accountlib
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/errors"
+)
+
+// validCreateParams - returns create params passing validation
+func validCreateParams() AccountCreateParams {
+	return NewAccountBuilder().
+		ID("7eb322ba-57f6-465c-b600-79f26ac7fdc3").
+		OrganisationID("35eedc2c-0318-40dc-a090-d6f42e7b2754").
+		Country("GB").
+		BaseCurrency("GBP").
+		BankID("400300").
+		BankIDCode("GBDSC").
+		Bic("NWBKGB22").
+		Name("Samantha Holder").
+		Build()
+}
+
+// TestValidateSuccess - tests valid create params
+func TestValidateSuccess(t *testing.T) {
+	check := assert.New(t)
+	params := validCreateParams()
+	check.Equal(params.Validate(), nil)
+}
+
+// TestValidateRequiredFields - tests missing required fields are all reported
+func TestValidateRequiredFields(t *testing.T) {
+	check := assert.New(t)
+	params := AccountCreateParams{Attributes: &AccountCreateAttributes{}}
+
+	err := params.Validate()
+	check.Contains(err.Error(), "id is required")
+	check.Contains(err.Error(), "organisation_id is required")
+	check.Contains(err.Error(), "attributes.country is required")
+}
+
+// TestValidateInvalidValues - tests malformed values are reported
+func TestValidateInvalidValues(t *testing.T) {
+	check := assert.New(t)
+	params := validCreateParams()
+	params.ID = "57f6-465c"
+	params.Attributes.AccountClassification = new(AccountClassification)
+	*params.Attributes.AccountClassification = "personel"
+	params.Attributes.BaseCurrency = "GBR"
+	params.Attributes.Name = []string{"a", "b", "c", "d", strings.Repeat("e", 141)}
+	params.Attributes.BankIDCode = ""
+	params.Attributes.Iban = "GB28NWBK60161331926819"
+
+	err := params.Validate()
+	check.Contains(err.Error(), `id "57f6-465c" is not a valid uuid`)
+	check.Contains(err.Error(), `account_classification "personel" is not supported`)
+	check.Contains(err.Error(), `base_currency "GBR" is not an ISO 4217 code`)
+	check.Contains(err.Error(), "attributes.name allows at most 4 entries")
+	check.Contains(err.Error(), "attributes.name[4] exceeds 140 characters")
+	check.Contains(err.Error(), "attributes.bank_id_code is required when attributes.bank_id is set")
+	check.Contains(err.Error(), "attributes.iban is not valid: invalid iban checksum")
+}
+
+// TestValidateMissingAttributes - tests create params without attributes
+func TestValidateMissingAttributes(t *testing.T) {
+	check := assert.New(t)
+	params := validCreateParams()
+	params.Attributes = nil
+	check.Contains(params.Validate().Error(), "attributes are required")
+}
+
+// TestValidateFieldErrors - tests validation errors locate every problem with a json pointer and a code
+func TestValidateFieldErrors(t *testing.T) {
+	check := assert.New(t)
+	params := validCreateParams()
+	params.ID = ""
+	params.Attributes.Name = []string{"a", strings.Repeat("b", 141)}
+	params.Attributes.Bic = ""
+
+	validationError := &accounterrors.ValidationError{}
+	check.True(errors.As(params.Validate(), &validationError))
+	check.Equal([]accounterrors.FieldError{
+		{Field: "id", Message: "is required", Pointer: "/data/id", Code: accounterrors.CodeRequired},
+		{Field: "attributes.name[1]", Message: "exceeds 140 characters", Pointer: "/data/attributes/name/1", Code: accounterrors.CodeTooLong},
+		{Field: "attributes.bic", Message: "is required for this country", Pointer: "/data/attributes/bic", Code: accounterrors.CodeInvalid},
+	}, validationError.Errors)
+}
@@ -0,0 +1,70 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"accountlib/errors"
+)
+
+// Warmup - pre-establishes n connections to the api, so the first requests after a deploy do not pay for dns lookups,
+// tcp and tls handshakes. The connections are opened with concurrent HEAD requests to the health endpoint and kept
+// idle in the pool of the client, the status codes of the answers are ignored. At most 100 idle connections are kept
+// by clients without a custom http client, the first error of a connection is returned
+func (client *Client) Warmup(ctx context.Context, n int) error {
+	if n <= 0 {
+		return errors.New("invalid connection count: must be positive")
+	}
+	if !client.lifecycle.enter(ctx) {
+		return accounterrors.ErrClientShutdown
+	}
+	defer client.lifecycle.leave()
+
+	// responses are held until every request is answered, so every request gets a connection of its own
+	var answered, done sync.WaitGroup
+	answered.Add(n)
+	done.Add(n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer done.Done()
+			response, err := client.warmupRequest(ctx)
+			answered.Done()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			answered.Wait()
+			_, _ = io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}(i)
+	}
+	done.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warmupRequest - sends a HEAD request to the health endpoint on a connection of the pool
+func (client *Client) warmupRequest(ctx context.Context) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, client.apiURL(healthPath), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to warm up connection. error: %w", err)
+	}
+	for name, values := range client.headers {
+		request.Header[name] = values
+	}
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to warm up connection. error: %w", err)
+	}
+	return response, nil
+}
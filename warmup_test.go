@@ -0,0 +1,55 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib/errors"
+)
+
+// TestWarmup - tests the connections opened by the warmup are reused by the following requests
+func TestWarmup(t *testing.T) {
+	check := assert.New(t)
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3"}}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	check.Nil(client.Warmup(context.Background(), 3))
+	check.Equal(int32(3), atomic.LoadInt32(&connections))
+	check.Equal(int64(3), client.PoolStats().IdleConnections)
+
+	_, err := client.Fetch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3")
+	check.Nil(err)
+	check.Equal(int32(3), atomic.LoadInt32(&connections))
+	check.Equal(int64(1), client.PoolStats().ReusedConnections)
+}
+
+// TestWarmupErrors - tests invalid connection counts, unreachable apis and shut down clients fail
+func TestWarmupErrors(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	check.EqualError(client.Warmup(context.Background(), 0), "invalid connection count: must be positive")
+	check.Contains(client.Warmup(context.Background(), 2).Error(), "unable to warm up connection")
+
+	check.Nil(client.Shutdown(context.Background()))
+	check.True(errors.Is(client.Warmup(context.Background(), 1), accounterrors.ErrClientShutdown))
+}
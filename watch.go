@@ -0,0 +1,85 @@
+package accountlib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// AccountChange - notification sent by Watch when a watched account changed
+// Changes hold the changed fields between Previous and Current. Deleted is set when the account no longer
+// exists, Current is nil then. Err is set when polling failed, polling continues with the next interval
+type AccountChange struct {
+	AccountID string
+	Previous  *AccountData
+	Current   *AccountData
+	Changes   []FieldChange
+	Deleted   bool
+	Err       error
+}
+
+// Watch - polls an account every interval and sends a notification whenever its version or attributes change
+// The account is fetched once before Watch returns, so an unknown account returns an error right away.
+// The channel is closed when ctx is done, the client is shut down or after the deletion of the account was sent, options apply to every poll
+func (client *Client) Watch(ctx context.Context, accountID string, interval time.Duration, options ...CallOption) (<-chan AccountChange, error) {
+	if interval <= 0 {
+		return nil, errors.New("invalid interval: must be positive")
+	}
+	current, err := client.Fetch(ctx, accountID, options...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, release, err := client.lifecycle.background(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(chan AccountChange)
+	go func() {
+		defer release()
+		client.watch(ctx, accountID, interval, options, current, changes)
+	}()
+	return changes, nil
+}
+
+// watch - polls the account until ctx is done or the account is deleted
+func (client *Client) watch(ctx context.Context, accountID string, interval time.Duration, options []CallOption, previous *AccountData, changes chan<- AccountChange) {
+	defer close(changes)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		current, statusCode, err := client.fetch(ctx, accountID, options)
+		if ctx.Err() != nil {
+			return
+		}
+		change := AccountChange{AccountID: accountID, Previous: previous}
+		switch {
+		case statusCode == http.StatusNotFound:
+			change.Deleted = true
+		case err != nil:
+			change.Err = err
+		default:
+			change.Current = current
+			change.Changes = Diff(previous, current)
+			if len(change.Changes) == 0 {
+				continue
+			}
+			previous = current
+		}
+
+		select {
+		case changes <- change:
+		case <-ctx.Done():
+			return
+		}
+		if change.Deleted {
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package accountlib
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWatch - tests changes, polling errors and the deletion of a watched account are sent
+func TestWatch(t *testing.T) {
+	check := assert.New(t)
+	responses := []struct {
+		statusCode int
+		body       string
+	}{
+		{http.StatusOK, `{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "version": 0, "attributes": {"bic": "NWBKGB22"}}}`},
+		{http.StatusOK, `{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "version": 0, "attributes": {"bic": "NWBKGB22"}}}`},
+		{http.StatusOK, `{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "version": 1, "attributes": {"bic": "BARCGB22"}}}`},
+		{http.StatusInternalServerError, `{"error_message": "database unavailable"}`},
+		{http.StatusNotFound, ``},
+	}
+	var mutex sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		response := responses[0]
+		if len(responses) > 1 {
+			responses = responses[1:]
+		}
+		w.WriteHeader(response.statusCode)
+		fmt.Fprint(w, response.body)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+	changes, err := client.Watch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", time.Millisecond)
+	check.Nil(err)
+
+	change := <-changes
+	check.Nil(change.Err)
+	check.Equal(int64(0), *change.Previous.Version)
+	check.Equal(int64(1), *change.Current.Version)
+	check.Equal([]FieldChange{
+		{Path: "attributes.bic", Old: "NWBKGB22", New: "BARCGB22"},
+		{Path: "version", Old: int64(0), New: int64(1)},
+	}, change.Changes)
+
+	change = <-changes
+	check.Contains(change.Err.Error(), "database unavailable")
+	check.False(change.Deleted)
+
+	change = <-changes
+	check.True(change.Deleted)
+	check.Nil(change.Current)
+	check.Equal(int64(1), *change.Previous.Version)
+	_, ok := <-changes
+	check.False(ok)
+}
+
+// TestWatchErrors - tests invalid intervals and unknown accounts are returned right away
+func TestWatchErrors(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientOptions{BaseURL: server.URL})
+
+	_, err := client.Watch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", 0)
+	check.Equal("invalid interval: must be positive", err.Error())
+	_, err = client.Watch(context.Background(), "7eb322ba-57f6-465c-b600-79f26ac7fdc3", time.Second)
+	check.Contains(err.Error(), "resource not found")
+}
+
+// TestWatchCanceled - tests the channel is closed when the context is done
+func TestWatchCanceled(t *testing.T) {
+	check := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "version": 0}}`)
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	changes, err := NewClient(&ClientOptions{BaseURL: server.URL}).Watch(ctx, "7eb322ba-57f6-465c-b600-79f26ac7fdc3", time.Millisecond)
+	check.Nil(err)
+	cancel()
+	_, ok := <-changes
+	check.False(ok)
+}
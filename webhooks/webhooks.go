@@ -0,0 +1,128 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"accountlib"
+)
+
+// EventType - type of an account event
+type EventType string
+
+// account event types sent by the api
+const (
+	EventAccountCreated EventType = "account.created"
+	EventAccountUpdated EventType = "account.updated"
+	EventAccountDeleted EventType = "account.deleted"
+)
+
+// ErrUnknownEventType - matches errors returned for payloads with an event type unknown to this package
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// Event - account event delivered by a webhook, use a type switch to get the typed event
+type Event interface {
+	// EventID - returns the id of the event, deliveries of the same event share it
+	EventID() string
+	// Type - returns the type of the event
+	Type() EventType
+}
+
+// EventHeader - holds the fields shared by every event
+type EventHeader struct {
+	ID        string    `json:"id"`
+	EventType EventType `json:"event_type"`
+	CreatedOn time.Time `json:"created_on"`
+}
+
+// EventID - returns the id of the event
+func (header EventHeader) EventID() string {
+	return header.ID
+}
+
+// Type - returns the type of the event
+func (header EventHeader) Type() EventType {
+	return header.EventType
+}
+
+// AccountCreatedEvent - sent after an account was created, Account holds the created account
+type AccountCreatedEvent struct {
+	EventHeader
+	Account *accountlib.AccountData
+}
+
+// AccountUpdatedEvent - sent after an account was updated, Account holds the account after the update
+type AccountUpdatedEvent struct {
+	EventHeader
+	Account *accountlib.AccountData
+}
+
+// AccountDeletedEvent - sent after an account was deleted, the payload holds the deleted account without attributes
+type AccountDeletedEvent struct {
+	EventHeader
+	AccountID      string
+	OrganisationID string
+	Version        *int64
+}
+
+// payload - holds the json envelope of an event
+type payload struct {
+	EventHeader
+	Data json.RawMessage `json:"data"`
+}
+
+// Parse - parses a webhook body into its typed event
+// Payloads with an unknown event type return an error matching ErrUnknownEventType, so consumers can skip them
+func Parse(body []byte) (Event, error) {
+	envelope := payload{}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid event payload. error: %s", err.Error())
+	}
+	if envelope.ID == "" {
+		return nil, errors.New("invalid event payload. error: missing event id")
+	}
+
+	switch envelope.EventType {
+	case EventAccountCreated:
+		account, err := decodeAccount(envelope)
+		if err != nil {
+			return nil, err
+		}
+		return &AccountCreatedEvent{EventHeader: envelope.EventHeader, Account: account}, nil
+	case EventAccountUpdated:
+		account, err := decodeAccount(envelope)
+		if err != nil {
+			return nil, err
+		}
+		return &AccountUpdatedEvent{EventHeader: envelope.EventHeader, Account: account}, nil
+	case EventAccountDeleted:
+		account, err := decodeAccount(envelope)
+		if err != nil {
+			return nil, err
+		}
+		return &AccountDeletedEvent{
+			EventHeader:    envelope.EventHeader,
+			AccountID:      account.ID,
+			OrganisationID: account.OrganisationID,
+			Version:        account.Version,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownEventType, envelope.EventType)
+}
+
+// decodeAccount - decodes the account of an event, every account event carries at least the account id
+func decodeAccount(envelope payload) (*accountlib.AccountData, error) {
+	account := &accountlib.AccountData{}
+	if len(envelope.Data) == 0 || string(envelope.Data) == "null" {
+		return nil, fmt.Errorf("invalid %s event %s. error: missing account data", envelope.EventType, envelope.ID)
+	}
+	if err := json.Unmarshal(envelope.Data, account); err != nil {
+		return nil, fmt.Errorf("invalid %s event %s. error: %s", envelope.EventType, envelope.ID, err.Error())
+	}
+	if account.ID == "" {
+		return nil, fmt.Errorf("invalid %s event %s. error: missing account id", envelope.EventType, envelope.ID)
+	}
+	return account, nil
+}
@@ -0,0 +1,80 @@
+package webhooks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createdPayload - payload of an account created event
+const createdPayload = `{
+	"id": "1c6a0f6c-3f3b-4b1e-9a51-5d9b3c1d7e2f",
+	"event_type": "account.created",
+	"created_on": "2026-03-01T10:00:00Z",
+	"data": {
+		"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3",
+		"organisation_id": "35eedc2c-0318-40dc-a090-d6f42e7b2754",
+		"type": "accounts",
+		"version": 0,
+		"attributes": {"country": "GB", "bank_id": "400300", "bic": "NWBKGB22"}
+	}
+}`
+
+// TestParseCreated - tests an account created event is parsed with its account
+func TestParseCreated(t *testing.T) {
+	check := assert.New(t)
+	event, err := Parse([]byte(createdPayload))
+	check.Nil(err)
+	check.Equal("1c6a0f6c-3f3b-4b1e-9a51-5d9b3c1d7e2f", event.EventID())
+	check.Equal(EventAccountCreated, event.Type())
+
+	created, ok := event.(*AccountCreatedEvent)
+	check.True(ok)
+	check.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), created.CreatedOn)
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", created.Account.ID)
+	check.Equal("NWBKGB22", created.Account.Attributes.Bic)
+}
+
+// TestParseUpdated - tests an account updated event is parsed with its account
+func TestParseUpdated(t *testing.T) {
+	check := assert.New(t)
+	event, err := Parse([]byte(`{"id": "e1", "event_type": "account.updated", "data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "version": 3, "attributes": {"bic": "BARCGB22"}}}`))
+	check.Nil(err)
+	updated, ok := event.(*AccountUpdatedEvent)
+	check.True(ok)
+	check.Equal(int64(3), *updated.Account.Version)
+	check.Equal("BARCGB22", updated.Account.Attributes.Bic)
+}
+
+// TestParseDeleted - tests an account deleted event is parsed without attributes
+func TestParseDeleted(t *testing.T) {
+	check := assert.New(t)
+	event, err := Parse([]byte(`{"id": "e2", "event_type": "account.deleted", "data": {"id": "7eb322ba-57f6-465c-b600-79f26ac7fdc3", "organisation_id": "35eedc2c-0318-40dc-a090-d6f42e7b2754", "version": 4}}`))
+	check.Nil(err)
+	deleted, ok := event.(*AccountDeletedEvent)
+	check.True(ok)
+	check.Equal("7eb322ba-57f6-465c-b600-79f26ac7fdc3", deleted.AccountID)
+	check.Equal("35eedc2c-0318-40dc-a090-d6f42e7b2754", deleted.OrganisationID)
+	check.Equal(int64(4), *deleted.Version)
+}
+
+// TestParseInvalid - tests invalid payloads and unknown event types are rejected
+func TestParseInvalid(t *testing.T) {
+	check := assert.New(t)
+	for body, message := range map[string]string{
+		`{"id": `:                           "invalid event payload",
+		`{"event_type": "account.created"}`: "missing event id",
+		`{"id": "e3", "event_type": "account.created"}`:             "invalid account.created event e3. error: missing account data",
+		`{"id": "e3", "event_type": "account.updated", "data": {}}`: "missing account id",
+		`{"id": "e3", "event_type": "account.deleted", "data": []}`: "invalid account.deleted event e3",
+	} {
+		_, err := Parse([]byte(body))
+		check.Contains(err.Error(), message, body)
+	}
+
+	_, err := Parse([]byte(`{"id": "e4", "event_type": "payment.created", "data": {}}`))
+	check.True(errors.Is(err, ErrUnknownEventType))
+	check.Equal(`unknown event type "payment.created"`, err.Error())
+}
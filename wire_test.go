@@ -0,0 +1,40 @@
+package accountlib_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"accountlib"
+	"accountlib/accountlibtest"
+	"accountlib/fakeserver"
+)
+
+// TestWireFormat - tests the urls and bodies of the requests of every account operation against a golden snapshot
+// Run with ACCOUNTLIB_UPDATE_GOLDEN=1 after intended wire format changes
+func TestWireFormat(t *testing.T) {
+	check := assert.New(t)
+	server := fakeserver.New()
+	defer server.Close()
+	recorder := accountlibtest.NewRequestRecorder(nil)
+	client := accountlib.NewClient(&accountlib.ClientOptions{BaseURL: server.URL, HTTPClient: recorder.HTTPClient()})
+	fixture := accountlibtest.ValidGBAccount()
+	params := fixture.CreateParams()
+
+	created, err := client.Create(context.Background(), params)
+	check.Nil(err)
+	_, err = client.Fetch(context.Background(), params.ID)
+	check.Nil(err)
+	_, err = client.List(context.Background(), accountlib.ListParams{PageNumber: 1, PageSize: 10, Filter: map[string]string{"country": "GB", "bank_id": "400300"}})
+	check.Nil(err)
+	updated, err := client.Update(context.Background(), accountlib.AccountUpdateParams{
+		ID:         params.ID,
+		Version:    created.Version,
+		Attributes: fixture.WithName("Jane Doe").CreateParams().Attributes,
+	})
+	check.Nil(err)
+	check.Nil(client.Delete(context.Background(), params.ID, updated.Version))
+
+	accountlibtest.AssertRequestsGolden(t, "requests.golden", recorder.Requests())
+}